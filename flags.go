@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/theme"
+)
+
+// ============================================================================
+// Command line flags
+// ============================================================================
+
+// options are the global flags. they go before the command and override the
+// config for this run only, nothing is written back
+type options struct {
+	configPath    string
+	runtime       string
+	pickRuntime   bool // bare --runtime, opens the interactive selector
+	refresh       time.Duration
+	composeView   bool
+	noPrechecks   bool
+	theme         string
+	debugLog      string
+	host          string
+	dockerContext string
+	version       bool
+}
+
+const usageHeader = `DockMate - a terminal UI for Docker and Podman
+
+Usage:
+  dockmate [flags]                start the TUI
+  dockmate [flags] COMMAND [...]  run a command and exit
+
+Commands:
+  ps        list containers
+  projects  list compose projects
+  stats     print container stats
+  logs      print a container's log
+  inspect   print a container's inspect data
+  config    check the config file: config validate [PATH]
+  version   print the version
+  update    update dockmate
+  help      show this help
+
+Flags:
+`
+
+func newFlagSet(o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("dockmate", flag.ContinueOnError)
+	fs.StringVar(&o.configPath, "config", "", "read and save the config at `path` instead of ~/.config/dockmate/config.yml")
+	fs.StringVar(&o.runtime, "runtime", "", "use `docker|podman` for this run, a bare --runtime opens the selector and saves the choice")
+	fs.Func("refresh", "refresh `interval` for this run, e.g. 5s", func(s string) error {
+		d, err := parseRefresh(s)
+		o.refresh = d
+		return err
+	})
+	fs.BoolVar(&o.composeView, "compose-view", false, "start in the compose view")
+	fs.BoolVar(&o.noPrechecks, "no-prechecks", false, "skip the install and daemon checks before the TUI starts")
+	fs.StringVar(&o.theme, "theme", "", "color `theme` for this run: "+strings.Join(theme.Names, ", "))
+	fs.StringVar(&o.debugLog, "debug-log", "", "write the debug log to `path` instead of ./dockmate-debug.log")
+	fs.StringVar(&o.host, "host", "", "connect to a configured host `name`, or a DOCKER_HOST url")
+	fs.StringVar(&o.dockerContext, "context", "", "connect through the docker context `name`")
+	fs.BoolVar(&o.version, "version", false, "print the version")
+	fs.BoolVar(&o.version, "v", false, "shorthand for --version")
+	return fs
+}
+
+func printUsage(w io.Writer) {
+	fs := newFlagSet(&options{})
+	fs.SetOutput(w)
+	fmt.Fprint(w, usageHeader)
+	fs.PrintDefaults()
+	fmt.Fprint(w, "\nRun 'dockmate COMMAND -h' for the flags of a command.\n")
+}
+
+// parseOptions reads the global flags and returns what's left, the command
+// and its own flags
+func parseOptions(args []string) (options, []string, error) {
+	var o options
+	fs := newFlagSet(&o)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() { printUsage(os.Stderr) }
+
+	// a bare --runtime used to be the only way to get the selector, keep it
+	args = slices.Clone(args)
+	for i, arg := range args {
+		if arg == "--runtime" || arg == "-runtime" {
+			if i+1 == len(args) || strings.HasPrefix(args[i+1], "-") {
+				args[i] = "--runtime="
+				o.pickRuntime = true
+			}
+			break
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return o, nil, err
+	}
+
+	fail := func(format string, a ...any) (options, []string, error) {
+		err := fmt.Errorf(format, a...)
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		fs.Usage()
+		return o, nil, err
+	}
+	if o.runtime != "" && o.runtime != "docker" && o.runtime != "podman" {
+		return fail("--runtime must be docker or podman, not %q", o.runtime)
+	}
+	if o.theme != "" && !slices.Contains(theme.Names, o.theme) {
+		return fail("unknown theme %q, try %s", o.theme, strings.Join(theme.Names, ", "))
+	}
+	return o, fs.Args(), nil
+}
+
+// parseRefresh takes a duration ("5s", "1m") or plain seconds ("5")
+func parseRefresh(s string) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		s = fmt.Sprintf("%ds", n)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("refresh %q should be a duration of at least 1s", s)
+	}
+	return d, nil
+}
+
+// load reads the config and puts the flags on top. a --context becomes a
+// host for this run. returns the host to connect to, and the error from
+// config.Load, the config is usable either way
+func (o options) load() (*config.Config, string, error) {
+	cfg, err := config.Load()
+	if o.runtime != "" {
+		cfg.Runtime.Type = o.runtime
+	}
+	if o.refresh > 0 {
+		// the poll rate is whole seconds
+		cfg.Performance.PollRate = int(math.Ceil(o.refresh.Seconds()))
+	}
+	if o.composeView {
+		cfg.Display.ComposeView = true
+	}
+	switch {
+	case o.theme != "":
+		cfg.Theme.Name = o.theme
+	case os.Getenv("NO_COLOR") != "":
+		// https://no-color.org, an explicit --theme still wins
+		cfg.Theme = config.ThemeConfig{Name: "monochrome"}
+	}
+
+	host := o.host
+	if o.dockerContext != "" {
+		if _, ok := cfg.FindHost(o.dockerContext); !ok {
+			cfg.Hosts = append(cfg.Hosts, config.HostConfig{Name: o.dockerContext, Context: o.dockerContext})
+		}
+		host = o.dockerContext
+	}
+	return cfg, host, err
+}
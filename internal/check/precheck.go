@@ -38,10 +38,8 @@ const (
 )
 
 func isPrecheckEnabled() bool {
-	cfg, err := config.Load()
-	if err != nil {
-		return true // default to true if error loading config
-	}
+	// a config with errors still loads, with defaults for the bad values
+	cfg, _ := config.Load()
 	return cfg.Runtime.RunPreChecks
 }
 
@@ -61,11 +59,8 @@ func isRuntimeConfigured() bool {
 		return false
 	}
 
-	// Load the config (should succeed if it exists)
-	cfg, err := config.Load()
-	if err != nil {
-		return false
-	}
+	// a bad runtime.type falls back to docker, which is configured enough
+	cfg, _ := config.Load()
 	runtimeType := strings.TrimSpace(strings.ToLower(cfg.Runtime.Type))
 	return (runtimeType == "docker" || runtimeType == "podman") && runtimeType != "" && runtimeType != "auto"
 }
@@ -92,8 +87,11 @@ func promptRuntimeSelection() error {
 		return fmt.Errorf("no runtime selected")
 	}
 
-	// Load current config and update runtime
-	cfg, _ := config.Load()
+	// Load current config and update runtime, not over a file with errors
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("can't save the runtime selection, fix the config first: %w", err)
+	}
 	cfg.Runtime.Type = selectedRuntime
 
 	// Save updated config
@@ -506,10 +504,12 @@ func isDaemonRunning() bool {
 	return err == nil
 }
 
-func RunPreChecks() PreCheckResult {
+// RunPreChecks checks the configured runtime, or runtimeOverride when it's
+// set (--runtime), in which case there's nothing to prompt for either
+func RunPreChecks(runtimeOverride string) PreCheckResult {
 
 	// Check - Is runtime configured? If not, prompt user
-	if !isRuntimeConfigured() {
+	if runtimeOverride == "" && !isRuntimeConfigured() {
 		err := promptRuntimeSelection()
 		if err != nil {
 			return PreCheckResult{
@@ -521,17 +521,13 @@ func RunPreChecks() PreCheckResult {
 		}
 	}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return PreCheckResult{
-			Passed:          false,
-			ErrorType:       NoError,
-			ErrorMessage:    fmt.Sprintf("Failed to load config: %v", err),
-			SuggestedAction: "Try running:\n  dockmate --runtime\n\nOr delete your config file and try again:\n  rm ~/.config/dockmate/config.yml",
-		}
-	}
+	// problems in the file are reported by the TUI, the checks only need the runtime
+	cfg, loadErr := config.Load()
 
 	runtimeType := strings.TrimSpace(strings.ToLower(cfg.Runtime.Type))
+	if runtimeOverride != "" {
+		runtimeType = runtimeOverride
+	}
 	if runtimeType == "" {
 		runtimeType = "docker"
 	}
@@ -585,7 +581,11 @@ func RunPreChecks() PreCheckResult {
 		}
 	}
 
-	// save to config that prechecks have passed (if needed in future)
+	// save to config that prechecks have passed (if needed in future). a file
+	// with errors is left alone, saving would write the defaults over it
+	if !cfg.Runtime.RunPreChecks || loadErr != nil {
+		return PreCheckResult{Passed: true}
+	}
 	cfg.Runtime.RunPreChecks = false
 	if err := cfg.Save(); err != nil {
 		// log but don't fail prechecks
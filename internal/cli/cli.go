@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// Headless subcommands (ps, projects, stats, logs, inspect)
+// ============================================================================
+//
+// same runtime and compose grouping as the TUI, just printed once (or
+// streamed) for scripts and CI instead of drawn
+
+// Commands are the subcommands Run knows
+var Commands = []string{"ps", "projects", "stats", "logs", "inspect"}
+
+// IsCommand reports whether name is one of Commands
+func IsCommand(name string) bool {
+	return slices.Contains(Commands, name)
+}
+
+type command struct {
+	rt     docker.Runtime
+	cfg    *config.Config
+	out    io.Writer
+	errOut io.Writer // usage and stderr log lines
+}
+
+// Run runs args[0] against rt. ctx ends streams (stats, logs -f), main ties
+// it to ctrl+c
+func Run(ctx context.Context, rt docker.Runtime, cfg *config.Config, args []string, stdout, stderr io.Writer) error {
+	c := &command{rt: rt, cfg: cfg, out: stdout, errOut: stderr}
+
+	var err error
+	switch args[0] {
+	case "ps":
+		err = c.ps(args[1:])
+	case "projects":
+		err = c.projects(args[1:])
+	case "stats":
+		err = c.stats(ctx, args[1:])
+	case "logs":
+		err = c.logs(ctx, args[1:])
+	case "inspect":
+		err = c.inspect(args[1:])
+	default:
+		err = fmt.Errorf("unknown command %q, try %s", args[0], strings.Join(Commands, ", "))
+	}
+	// -h already printed the usage
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// ============================================================================
+// Flags and output
+// ============================================================================
+
+// newFlags is a flag set that returns its errors instead of exiting
+func (c *command) newFlags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(c.errOut, "usage: dockmate %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// outputFlag adds --output and its -o shorthand
+func outputFlag(fs *flag.FlagSet, def string) *string {
+	format := fs.String("output", def, "output format: table, json or yaml")
+	fs.StringVar(format, "o", def, "shorthand for --output")
+	return format
+}
+
+// parse lets flags go before or after the positional args, "logs web -f"
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func checkFormat(format string, allowed ...string) error {
+	if !slices.Contains(allowed, format) {
+		return fmt.Errorf("unknown output %q, use %s", format, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// write prints v as json or yaml, or hands a tabwriter to table
+func (c *command) write(format string, v any, table func(w io.Writer)) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		enc := yaml.NewEncoder(c.out)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	tw := tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// ============================================================================
+// Containers
+// ============================================================================
+
+func containerName(c docker.Container) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// sortContainers orders them the way the compose view does: projects by
+// name first, then the standalone containers
+func sortContainers(containers []docker.Container) {
+	sort.SliceStable(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if (a.ComposeProject == "") != (b.ComposeProject == "") {
+			return b.ComposeProject == ""
+		}
+		if a.ComposeProject != b.ComposeProject {
+			return a.ComposeProject < b.ComposeProject
+		}
+		return containerName(a) < containerName(b)
+	})
+}
+
+// attachStats fills in Stats for the running containers
+func (c *command) attachStats(containers []docker.Container) error {
+	var ids []string
+	for _, ct := range containers {
+		if strings.EqualFold(ct.State, "running") {
+			ids = append(ids, ct.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	stats, err := c.rt.GetAllContainerStats(ids)
+	var partial *docker.StatsError
+	if errors.As(err, &partial) {
+		// the rest are still worth printing, say which ones are blank
+		fmt.Fprintf(c.errOut, "warning: %v\n", partial)
+	} else if err != nil {
+		return err
+	}
+	for i := range containers {
+		if s, ok := stats[containers[i].ID]; ok {
+			containers[i].Stats = &s
+		}
+	}
+	return nil
+}
+
+// find picks a container by name, id or id prefix
+func find(containers []docker.Container, ref string) (docker.Container, error) {
+	ref = strings.TrimPrefix(ref, "/")
+	var prefixed []docker.Container
+	for _, c := range containers {
+		if containerName(c) == ref || c.ID == ref {
+			return c, nil
+		}
+		// a full 64 char id against our short ones, or a short prefix
+		if strings.HasPrefix(c.ID, ref) || (len(ref) > len(c.ID) && strings.HasPrefix(ref, c.ID)) {
+			prefixed = append(prefixed, c)
+		}
+	}
+	switch len(prefixed) {
+	case 0:
+		return docker.Container{}, fmt.Errorf("no such container: %s", ref)
+	case 1:
+		return prefixed[0], nil
+	}
+	return docker.Container{}, fmt.Errorf("%s matches %d containers, use more of the id or the name", ref, len(prefixed))
+}
+
+// lookup lists the containers and finds ref among them
+func (c *command) lookup(ref string) (docker.Container, error) {
+	containers, err := c.rt.ListContainers()
+	if err != nil {
+		return docker.Container{}, err
+	}
+	return find(containers, ref)
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleRuntime() *docker.FakeRuntime {
+	rt := docker.NewFakeRuntime(
+		docker.Container{
+			ID: "112233445566", Names: []string{"old-job"}, Image: "busybox",
+			Status: "Exited (0) 2 hours ago", State: "exited",
+		},
+		docker.Container{
+			ID: "a1b2c3d4e5f6", Names: []string{"shop-web-1"}, Image: "nginx:1.27",
+			Status: "Up 5 minutes", State: "running", Ports: "0.0.0.0:8080->80/tcp",
+			ComposeProject: "shop", ComposeService: "web",
+		},
+		docker.Container{
+			ID: "0f9e8d7c6b5a", Names: []string{"shop-db-1"}, Image: "postgres:16",
+			Status: "Up 3 hours", State: "running", Ports: "5432/tcp",
+			ComposeProject: "shop", ComposeService: "db",
+		},
+	)
+	rt.SetStats(docker.ContainerStats{ID: "a1b2c3d4e5f6", CPU: 0.6, MemUsed: 12_500_000, MemLimit: 1_000_000_000, PIDs: 3})
+	rt.SetStats(docker.ContainerStats{ID: "0f9e8d7c6b5a", CPU: 0.02, MemUsed: 80_000_000, MemLimit: 1_000_000_000, PIDs: 9})
+	return rt
+}
+
+// run runs a subcommand and returns stdout and stderr
+func run(t *testing.T, rt docker.Runtime, args ...string) (string, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := Run(context.Background(), rt, config.DefaultConfig(), args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestPs(t *testing.T) {
+	rt := sampleRuntime()
+
+	out, _, err := run(t, rt, "ps")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "CONTAINER ID"))
+	// compose projects first, standalone last
+	assert.Contains(t, lines[1], "shop-db-1")
+	assert.Contains(t, lines[3], "old-job")
+
+	// the filter language from the TUI, stats get fetched for cpu>
+	out, _, err = run(t, rt, "ps", "--filter", "cpu>50", "-o", "json")
+	require.NoError(t, err)
+	var got []containerOut
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, "shop-web-1", got[0].Name)
+	assert.Equal(t, "shop", got[0].Project)
+
+	out, _, err = run(t, rt, "ps", "--filter", "state:paused", "--output", "json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", out)
+
+	_, _, err = run(t, rt, "ps", "--output", "xml")
+	assert.ErrorContains(t, err, `unknown output "xml"`)
+	_, _, err = run(t, rt, "ps", "--filter", "bogus:1")
+	assert.Error(t, err)
+}
+
+func TestProjects(t *testing.T) {
+	out, _, err := run(t, sampleRuntime(), "projects", "--output", "yaml")
+	require.NoError(t, err)
+	assert.Contains(t, out, "name: shop\n")
+	assert.Contains(t, out, "status: running\n")
+	assert.Contains(t, out, "running: 2\n")
+}
+
+func TestStatsOnce(t *testing.T) {
+	out, _, err := run(t, sampleRuntime(), "stats", "--once")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3, "running containers only")
+	assert.Contains(t, lines[2], "60.00%")
+	assert.Contains(t, lines[2], "12.5MB / 1GB")
+
+	_, _, err = run(t, sampleRuntime(), "stats", "--once", "shop-db", "-o", "json")
+	require.Error(t, err, "names aren't fuzzy")
+	out, _, err = run(t, sampleRuntime(), "stats", "--once", "shop-db-1", "-o", "json")
+	require.NoError(t, err)
+	var got []statsOut
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, uint64(9), got[0].PIDs)
+}
+
+func TestLogs(t *testing.T) {
+	rt := sampleRuntime()
+	now := time.Now()
+	rt.SetLogLines("a1b2c3d4e5f6",
+		docker.LogLine{Time: now.Add(-time.Hour), Stream: "stdout", Text: "booting"},
+		docker.LogLine{Time: now.Add(-time.Minute), Stream: "stderr", Text: "warning: slow"},
+		docker.LogLine{Time: now, Stream: "stdout", Text: "ready"},
+	)
+
+	// flags after the name work too, stderr stays stderr
+	out, errOut, err := run(t, rt, "logs", "a1b2", "--since", "10m")
+	require.NoError(t, err)
+	assert.Equal(t, "ready\n", out)
+	assert.Equal(t, "warning: slow\n", errOut)
+
+	_, _, err = run(t, rt, "logs", "nope")
+	assert.ErrorContains(t, err, "no such container")
+	_, _, err = run(t, rt, "logs")
+	assert.Error(t, err)
+}
+
+func TestFind(t *testing.T) {
+	containers := []docker.Container{
+		{ID: "abc111111111", Names: []string{"/web"}},
+		{ID: "abc222222222", Names: []string{"db"}},
+	}
+	c, err := find(containers, "web")
+	require.NoError(t, err)
+	assert.Equal(t, "abc111111111", c.ID)
+
+	c, err = find(containers, "abc2222222220000000000")
+	require.NoError(t, err)
+	assert.Equal(t, "db", containerName(c))
+
+	_, err = find(containers, "abc")
+	assert.ErrorContains(t, err, "matches 2 containers")
+}
+
+func TestConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/config.yml"
+	require.NoError(t, os.WriteFile(path, []byte("performance:\n  poll_rate: 0\ncolour: red\n"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	err := RunConfig([]string{"validate", path}, &stdout, &stderr)
+	require.Error(t, err, "errors fail the command")
+	assert.Contains(t, stdout.String(), "  error: line 2: performance.poll_rate: must be at least 1 second, got 0\n")
+	assert.Contains(t, stdout.String(), "  warning: line 3: colour: unknown key, it's ignored\n")
+	assert.Contains(t, stdout.String(), "1 error, 1 warning\n")
+
+	// warnings alone pass
+	require.NoError(t, os.WriteFile(path, []byte("colour: red\n"), 0o644))
+	stdout.Reset()
+	require.NoError(t, RunConfig([]string{"validate", path}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "0 errors, 1 warning\n")
+
+	require.NoError(t, os.WriteFile(path, []byte("version: 2\nperformance:\n  poll_rate: 2\n"), 0o644))
+	stdout.Reset()
+	require.NoError(t, RunConfig([]string{"validate", path}, &stdout, &stderr))
+	assert.Equal(t, path+": config is valid\n", stdout.String())
+
+	// an unversioned file is valid, it's only told about the upgrade
+	require.NoError(t, os.WriteFile(path, []byte("performance:\n  poll_rate: 2\n"), 0o644))
+	stdout.Reset()
+	require.NoError(t, RunConfig([]string{"validate", path}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "schema version 0, dockmate upgrades it to 2 on the next start")
+
+	assert.Error(t, RunConfig([]string{"check"}, &stdout, &stderr))
+}
@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/filter"
+	"github.com/shubh-io/dockmate/internal/units"
+)
+
+// ============================================================================
+// Output types
+// ============================================================================
+//
+// json/yaml output is a stable snake_case shape of its own, scripts shouldn't
+// break when the docker package changes
+
+type containerOut struct {
+	ID      string            `json:"id" yaml:"id"`
+	Name    string            `json:"name" yaml:"name"`
+	Image   string            `json:"image" yaml:"image"`
+	State   string            `json:"state" yaml:"state"`
+	Status  string            `json:"status" yaml:"status"`
+	Ports   string            `json:"ports" yaml:"ports"`
+	Health  string            `json:"health,omitempty" yaml:"health,omitempty"` // empty without a healthcheck
+	Project string            `json:"project,omitempty" yaml:"project,omitempty"`
+	Service string            `json:"service,omitempty" yaml:"service,omitempty"`
+	Host    string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+func toContainerOut(c docker.Container) containerOut {
+	return containerOut{
+		ID:      c.ID,
+		Name:    containerName(c),
+		Image:   c.Image,
+		State:   c.State,
+		Status:  c.Status,
+		Ports:   c.Ports,
+		Health:  string(c.Health),
+		Project: c.ComposeProject,
+		Service: c.ComposeService,
+		Host:    c.Host,
+		Labels:  c.Labels,
+	}
+}
+
+type projectOut struct {
+	Name       string         `json:"name" yaml:"name"`
+	Status     string         `json:"status" yaml:"status"` // running, partial or stopped
+	Running    int            `json:"running" yaml:"running"`
+	Total      int            `json:"total" yaml:"total"`
+	ConfigFile string         `json:"config_file,omitempty" yaml:"config_file,omitempty"`
+	WorkingDir string         `json:"working_dir,omitempty" yaml:"working_dir,omitempty"`
+	Host       string         `json:"host,omitempty" yaml:"host,omitempty"`
+	Containers []containerOut `json:"containers" yaml:"containers"`
+}
+
+type statsOut struct {
+	ID         string  `json:"id" yaml:"id"`
+	Name       string  `json:"name" yaml:"name"`
+	CPUPercent float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemUsed    uint64  `json:"mem_used" yaml:"mem_used"`   // bytes
+	MemLimit   uint64  `json:"mem_limit" yaml:"mem_limit"` // bytes, 0 when unknown
+	MemPercent float64 `json:"mem_percent" yaml:"mem_percent"`
+	NetRx      uint64  `json:"net_rx" yaml:"net_rx"`
+	NetTx      uint64  `json:"net_tx" yaml:"net_tx"`
+	BlockRead  uint64  `json:"block_read" yaml:"block_read"`
+	BlockWrite uint64  `json:"block_write" yaml:"block_write"`
+	PIDs       uint64  `json:"pids" yaml:"pids"`
+}
+
+// ============================================================================
+// ps
+// ============================================================================
+
+func (c *command) ps(args []string) error {
+	fs := c.newFlags("ps", "[--filter QUERY] [--output table|json|yaml]")
+	query := fs.String("filter", "", `filter like / in the TUI, e.g. "state:running project:shop"`)
+	format := outputFlag(fs, "table")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "table", "json", "yaml"); err != nil {
+		return err
+	}
+	q, err := filter.Parse(*query)
+	if err != nil {
+		return err
+	}
+
+	containers, err := c.rt.ListContainers()
+	if err != nil {
+		return err
+	}
+	// cpu>50 and mem<10 need numbers the list doesn't have
+	if q.UsesStats() {
+		if err := c.attachStats(containers); err != nil {
+			return err
+		}
+	}
+	sortContainers(containers)
+
+	out := []containerOut{}
+	showHost := false
+	for _, ct := range containers {
+		if q.Match(ct) {
+			out = append(out, toContainerOut(ct))
+			showHost = showHost || ct.Host != ""
+		}
+	}
+
+	return c.write(*format, out, func(w io.Writer) {
+		header := "CONTAINER ID\tNAME\tIMAGE\tSTATUS\tPORTS\tPROJECT"
+		if showHost {
+			header = "HOST\t" + header
+		}
+		fmt.Fprintln(w, header)
+		for _, ct := range out {
+			row := strings.Join([]string{ct.ID, ct.Name, ct.Image, ct.Status, dash(ct.Ports), dash(ct.Project)}, "\t")
+			if showHost {
+				row = ct.Host + "\t" + row
+			}
+			fmt.Fprintln(w, row)
+		}
+	})
+}
+
+// dash stands in for an empty cell so columns stay readable
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ============================================================================
+// projects
+// ============================================================================
+
+func (c *command) projects(args []string) error {
+	fs := c.newFlags("projects", "[--output table|json|yaml]")
+	format := outputFlag(fs, "table")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "table", "json", "yaml"); err != nil {
+		return err
+	}
+
+	projects, err := c.rt.FetchComposeProjects()
+	if err != nil {
+		return err
+	}
+	// keys, not names: the aggregated view keys by host/name
+	keys := make([]string, 0, len(projects))
+	for key := range projects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := []projectOut{}
+	for _, key := range keys {
+		p := projects[key]
+		sortContainers(p.Containers)
+		po := projectOut{
+			Name:       p.Name,
+			Status:     p.Status.String(),
+			Total:      len(p.Containers),
+			ConfigFile: p.ConfigFile,
+			WorkingDir: p.WorkingDir,
+			Containers: []containerOut{},
+		}
+		for _, ct := range p.Containers {
+			if strings.EqualFold(ct.State, "running") {
+				po.Running++
+			}
+			po.Host = ct.Host
+			po.Containers = append(po.Containers, toContainerOut(ct))
+		}
+		out = append(out, po)
+	}
+
+	return c.write(*format, out, func(w io.Writer) {
+		fmt.Fprintln(w, "PROJECT\tSTATUS\tRUNNING\tCONFIG FILE")
+		for _, p := range out {
+			name := p.Name
+			if p.Host != "" {
+				name = p.Host + "/" + name
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\n", name, p.Status, p.Running, p.Total, dash(p.ConfigFile))
+		}
+	})
+}
+
+// ============================================================================
+// stats
+// ============================================================================
+
+func (c *command) stats(ctx context.Context, args []string) error {
+	fs := c.newFlags("stats", "[--once] [--output table|json|yaml] [CONTAINER...]")
+	once := fs.Bool("once", false, "print one sample and exit instead of refreshing")
+	format := outputFlag(fs, "table")
+	refs, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "table", "json", "yaml"); err != nil {
+		return err
+	}
+
+	interval := time.Duration(max(1, c.cfg.Performance.PollRate)) * time.Second
+	for {
+		if err := c.statsOnce(*format, refs); err != nil {
+			return err
+		}
+		if *once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		fmt.Fprintln(c.out)
+	}
+}
+
+// statsOnce prints one sample of the running containers, or just refs
+func (c *command) statsOnce(format string, refs []string) error {
+	containers, err := c.rt.ListContainers()
+	if err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		picked := make([]docker.Container, 0, len(refs))
+		for _, ref := range refs {
+			ct, err := find(containers, ref)
+			if err != nil {
+				return err
+			}
+			picked = append(picked, ct)
+		}
+		containers = picked
+	} else {
+		sortContainers(containers)
+	}
+	if err := c.attachStats(containers); err != nil {
+		return err
+	}
+
+	out := []statsOut{}
+	for _, ct := range containers {
+		if ct.Stats == nil {
+			continue
+		}
+		s := ct.Stats
+		out = append(out, statsOut{
+			ID:         ct.ID,
+			Name:       containerName(ct),
+			CPUPercent: s.CPU * 100,
+			MemUsed:    s.MemUsed,
+			MemLimit:   s.MemLimit,
+			MemPercent: s.MemPercent(),
+			NetRx:      s.NetRx,
+			NetTx:      s.NetTx,
+			BlockRead:  s.BlockRead,
+			BlockWrite: s.BlockWrite,
+			PIDs:       s.PIDs,
+		})
+	}
+
+	size := func(v uint64) string { return units.Bytes(float64(v), c.cfg.Display.Units) }
+	return c.write(format, out, func(w io.Writer) {
+		fmt.Fprintln(w, "CONTAINER ID\tNAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
+		for _, s := range out {
+			mem := size(s.MemUsed)
+			if s.MemLimit > 0 {
+				mem += " / " + size(s.MemLimit)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%s\t%.2f%%\t%s / %s\t%s / %s\t%d\n",
+				s.ID, s.Name, s.CPUPercent, mem, s.MemPercent,
+				size(s.NetRx), size(s.NetTx), size(s.BlockRead), size(s.BlockWrite), s.PIDs)
+		}
+	})
+}
+
+// ============================================================================
+// logs
+// ============================================================================
+
+func (c *command) logs(ctx context.Context, args []string) error {
+	fs := c.newFlags("logs", "[-f] [--tail N] [--since 10m] [-t] CONTAINER")
+	follow := fs.Bool("follow", false, "keep printing new lines")
+	fs.BoolVar(follow, "f", false, "shorthand for --follow")
+	tail := fs.Int("tail", 0, "lines of history to print, 0 for all")
+	since := fs.String("since", "", "only lines newer than a duration (10m, 2h) or an RFC3339 time")
+	timestamps := fs.Bool("timestamps", false, "prefix each line with its time")
+	fs.BoolVar(timestamps, "t", false, "shorthand for --timestamps")
+	refs, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(refs) != 1 {
+		fs.Usage()
+		return fmt.Errorf("logs takes exactly one container")
+	}
+
+	opts := docker.LogOptions{Follow: *follow, Tail: *tail}
+	if *since != "" {
+		if opts.Since, err = parseSince(*since); err != nil {
+			return err
+		}
+	}
+	ct, err := c.lookup(refs[0])
+	if err != nil {
+		return err
+	}
+	lines, err := c.rt.StreamLogs(ctx, ct.ID, opts)
+	if err != nil {
+		return err
+	}
+	for line := range lines {
+		// stderr stays stderr so `2>/dev/null` works like it does with docker logs
+		w := c.out
+		if line.Stream == "stderr" {
+			w = c.errOut
+		}
+		if *timestamps && !line.Time.IsZero() {
+			fmt.Fprintf(w, "%s ", line.Time.Format(time.RFC3339Nano))
+		}
+		fmt.Fprintln(w, line.Text)
+	}
+	return nil
+}
+
+// parseSince takes a duration back from now or an absolute RFC3339 time
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("--since %q isn't a duration (10m) or an RFC3339 time", s)
+}
+
+// ============================================================================
+// inspect
+// ============================================================================
+
+// inspect prints the typed inspect data, an array like docker inspect does
+func (c *command) inspect(args []string) error {
+	fs := c.newFlags("inspect", "[--output json|yaml] CONTAINER...")
+	format := outputFlag(fs, "json")
+	refs, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "json", "yaml"); err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		fs.Usage()
+		return fmt.Errorf("inspect needs at least one container")
+	}
+
+	containers, err := c.rt.ListContainers()
+	if err != nil {
+		return err
+	}
+	out := []*docker.ContainerInspect{}
+	for _, ref := range refs {
+		ct, err := find(containers, ref)
+		if err != nil {
+			return err
+		}
+		in, err := c.rt.Inspect(ct.ID)
+		if err != nil {
+			return err
+		}
+		out = append(out, in)
+	}
+	return c.write(*format, out, nil)
+}
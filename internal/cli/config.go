@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/config"
+)
+
+// ============================================================================
+// config validate
+// ============================================================================
+//
+// config doesn't talk to a runtime, so it's apart from Run and main calls it
+// before connecting to anything
+
+// RunConfig runs `dockmate config SUBCOMMAND`
+func RunConfig(args []string, stdout, stderr io.Writer) error {
+	c := &command{out: stdout, errOut: stderr}
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(stderr, "usage: dockmate config validate [PATH]")
+		if len(args) == 0 {
+			return fmt.Errorf("config needs a subcommand")
+		}
+		return fmt.Errorf("unknown config subcommand %q", args[0])
+	}
+
+	err := c.validate(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// validate prints every problem in the config file with its line. warnings
+// alone still pass, errors make it fail so CI can catch them
+func (c *command) validate(args []string) error {
+	fs := c.newFlags("config validate", "[PATH]")
+	paths, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 1 {
+		fs.Usage()
+		return fmt.Errorf("config validate takes one file")
+	}
+	var path string
+	if len(paths) == 1 {
+		path = paths[0]
+	} else if path, err = config.GetConfigPath(); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, problems := config.Validate(data)
+
+	errorCount := 0
+	for _, p := range problems {
+		if p.Severity == config.Error {
+			errorCount++
+		}
+	}
+	// an old schema isn't a problem, it's upgraded the next time dockmate starts
+	upgrade := func() {
+		if _, m, err := config.Migrate(data); err == nil && m.To > m.From {
+			fmt.Fprintf(c.out, "schema version %d, dockmate upgrades it to %d on the next start: %s\n",
+				m.From, m.To, strings.Join(m.Steps, ", "))
+		}
+	}
+	if len(problems) == 0 {
+		fmt.Fprintf(c.out, "%s: config is valid\n", path)
+		upgrade()
+		return nil
+	}
+
+	fmt.Fprintf(c.out, "%s:\n", path)
+	for _, p := range problems {
+		fmt.Fprintf(c.out, "  %s: %s\n", p.Severity, p)
+	}
+	fmt.Fprintf(c.out, "%s, %s\n", plural(errorCount, "error"), plural(len(problems)-errorCount, "warning"))
+	upgrade()
+	if errorCount > 0 {
+		return fmt.Errorf("%s has %s, the defaults are used for those values", path, plural(errorCount, "error"))
+	}
+	return nil
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,81 @@
+package columns
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// Container list columns
+// ============================================================================
+//
+// every column the container list can show, under the names
+// `layout.columns` in the config uses:
+//
+//	layout:
+//	  columns:
+//	    - {name: name, width: 20}
+//	    - {name: label:com.example.team, width: 10}
+//	    - {name: cpu, width: 8, hidden: true}
+//
+// the listed ones are shown in that order, widths are percentages of the
+// screen. how a column is drawn and sorted lives in the TUI
+
+// Column is a column of the container list
+type Column struct {
+	Name  string // key in the config
+	Title string // header
+	Width int    // default share of the screen, percent
+	Min   int    // narrowest it gets, characters
+}
+
+// All columns in the order settings lists the unlisted ones
+var All = []Column{
+	{"id", "CONTAINER ID", 8, 13},
+	{"name", "NAME", 14, 17},
+	{"memory", "MEMORY", 6, 8},
+	{"cpu", "CPU", 6, 6},
+	{"net_io", "NET I/O", 10, 10},
+	{"disk_io", "DISK I/O", 12, 11},
+	{"image", "IMAGE", 18, 11},
+	{"status", "STATUS", 13, 13},
+	{"ports", "PORTS", 13, 15},
+	{"created", "CREATED", 8, 10},
+	{"uptime", "UPTIME", 7, 9},
+	{"health", "HEALTH", 8, 11},
+	{"restarts", "RESTARTS", 5, 10},
+	{"pids", "PIDS", 5, 7},
+	{"mem_limit", "MEM USED/LIMIT", 12, 18},
+	{"project", "PROJECT", 10, 10},
+	{"service", "SERVICE", 10, 10},
+	{"ip", "IP ADDRESS", 10, 13},
+}
+
+// LabelPrefix makes a column out of any label: "label:com.example.team"
+const LabelPrefix = "label:"
+
+// Lookup finds a column by its config name, label columns included
+func Lookup(name string) (Column, bool) {
+	if key, ok := strings.CutPrefix(name, LabelPrefix); ok {
+		if key == "" {
+			return Column{}, false
+		}
+		// the last part of a dotted key is usually the readable one
+		title := key[strings.LastIndex(key, ".")+1:]
+		return Column{Name: name, Title: strings.ToUpper(title), Width: 10, Min: 10}, true
+	}
+	for _, c := range All {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Names lists the built-in columns, for error messages
+func Names() string {
+	names := make([]string, len(All))
+	for i, c := range All {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ") + " or " + LabelPrefix + "<key>"
+}
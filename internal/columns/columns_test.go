@@ -0,0 +1,31 @@
+package columns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	c, ok := Lookup("mem_limit")
+	assert.True(t, ok)
+	assert.Equal(t, Column{"mem_limit", "MEM USED/LIMIT", 12, 18}, c)
+
+	c, ok = Lookup("label:com.docker.compose.service")
+	assert.True(t, ok)
+	assert.Equal(t, Column{"label:com.docker.compose.service", "SERVICE", 10, 10}, c)
+
+	for _, name := range []string{"label:", "weather", ""} {
+		_, ok = Lookup(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestNamesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range All {
+		assert.False(t, seen[c.Name], c.Name)
+		seen[c.Name] = true
+		assert.LessOrEqual(t, len(c.Title), c.Min, "%s: the title fits the narrowest column", c.Name)
+	}
+}
@@ -5,37 +5,35 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Version     int               `yaml:"version"` // schema version, see migrate.go
 	Layout      LayoutConfig      `yaml:"layout"`
 	Performance PerformanceConfig `yaml:"performance"`
 	Runtime     RuntimeConfig     `yaml:"runtime"`
 	Exec        ExecConfig        `yaml:"exec"`
+	Safety      SafetyConfig      `yaml:"safety"`
+	Filter      FilterConfig      `yaml:"filter"`
+	Display     DisplayConfig     `yaml:"display"`
+	Hosts       []HostConfig      `yaml:"hosts"`
+	Theme       ThemeConfig       `yaml:"theme"`
+	Keys        KeysConfig        `yaml:"keys"`
 }
 
+// LayoutConfig is the container list, see internal/columns for the columns
 type LayoutConfig struct {
-	ContainerId        int `yaml:"container_id_width"`
-	ContainerNameWidth int `yaml:"container_name_width"`
-	MemoryWidth        int `yaml:"memory_width"`
-	CPUWidth           int `yaml:"cpu_width"`
-	NetIOWidth         int `yaml:"net_io_width"`
-	DiskIOWidth        int `yaml:"disk_io_width"`
-	ImageWidth         int `yaml:"image_width"`
-	StatusWidth        int `yaml:"status_width"`
-	PortWidth          int `yaml:"port_width"`
-
-	ContainerIdVisible   bool `yaml:"container_id_visible"`
-	ContainerNameVisible bool `yaml:"container_name_visible"`
-	MemoryVisible        bool `yaml:"memory_visible"`
-	CPUVisible           bool `yaml:"cpu_visible"`
-	NetIOVisible         bool `yaml:"net_io_visible"`
-	DiskIOVisible        bool `yaml:"disk_io_visible"`
-	ImageVisible         bool `yaml:"image_visible"`
-	StatusVisible        bool `yaml:"status_visible"`
-	PortVisible          bool `yaml:"port_visible"`
+	Columns []ColumnConfig `yaml:"columns"` // in the order they're shown
+}
+
+// ColumnConfig is one column of the container list
+type ColumnConfig struct {
+	Name   string `yaml:"name"`
+	Width  int    `yaml:"width"`            // percent of the screen
+	Hidden bool   `yaml:"hidden,omitempty"` // keeps its place for when it's shown again
 }
 
 type PerformanceConfig struct {
@@ -44,7 +42,7 @@ type PerformanceConfig struct {
 
 type RuntimeConfig struct {
 	Type         string `yaml:"type"`   // "docker" or "podman"
-	Socket       string `yaml:"socket"` // custom socket path (would add in future)
+	Socket       string `yaml:"socket"` // custom API socket path, empty = runtime default
 	RunPreChecks bool   `yaml:"run_pre_checks"`
 }
 
@@ -52,9 +50,121 @@ type ExecConfig struct {
 	Shell string `yaml:"shell"` // preferred shell for container exec
 }
 
+// SafetyConfig marks containers that need the name typed out before they're
+// removed (or their compose project is taken down)
+type SafetyConfig struct {
+	ProtectedLabels []string `yaml:"protected_labels"` // "key" (any value) or "key=value"
+	ProtectedNames  []string `yaml:"protected_names"`  // glob patterns like "prod-*"
+}
+
+// Protects reports whether a container with this name and labels is protected
+func (s SafetyConfig) Protects(name string, labels map[string]string) bool {
+	name = strings.TrimPrefix(name, "/")
+	for _, pattern := range s.ProtectedNames {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	for _, l := range s.ProtectedLabels {
+		key, value, hasValue := strings.Cut(l, "=")
+		got, ok := labels[key]
+		if ok && (!hasValue || got == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// HostConfig is a named daemon to switch to besides the local one. set one
+// of Context, Host or Connection.
+type HostConfig struct {
+	Name       string `yaml:"name"`
+	Runtime    string `yaml:"runtime,omitempty"`    // "docker" (default) or "podman"
+	Context    string `yaml:"context,omitempty"`    // docker context name
+	Host       string `yaml:"host,omitempty"`       // DOCKER_HOST style url: unix://, tcp://, ssh://
+	Connection string `yaml:"connection,omitempty"` // podman system connection name
+}
+
+// FindHost looks a host up by name
+func (c *Config) FindHost(name string) (HostConfig, bool) {
+	for _, h := range c.Hosts {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return HostConfig{}, false
+}
+
+// DisplayConfig is how values are shown
+type DisplayConfig struct {
+	Units       string `yaml:"units"`        // "si" (kB, MB) or "iec" (KiB, MiB)
+	ComposeView bool   `yaml:"compose_view"` // start in the compose view
+}
+
+// ThemeConfig picks the colors, see internal/theme for the themes and roles
+type ThemeConfig struct {
+	Name   string            `yaml:"name"`    // dark, light, high-contrast, solarized or monochrome
+	Colors map[string]string `yaml:",inline"` // role: color, on top of the theme
+}
+
+// KeysConfig remaps the main screen, see internal/keymap for the action names
+type KeysConfig struct {
+	Preset   string             `yaml:"preset,omitempty"` // default, vim, emacs or htop
+	Bindings map[string]KeyList `yaml:",inline"`          // action: keys
+}
+
+// Overrides is Bindings as plain lists, for keymap.Resolve
+func (k KeysConfig) Overrides() map[string][]string {
+	out := make(map[string][]string, len(k.Bindings))
+	for action, keys := range k.Bindings {
+		out[action] = keys
+	}
+	return out
+}
+
+// KeyList is one key or a list of them, `stop: b` and `stop: [b, B]` both work
+type KeyList []string
+
+func (l *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = KeyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*l = keys
+	return nil
+}
+
+// how many filters FilterConfig remembers
+const maxRecentFilters = 10
+
+// FilterConfig keeps the last filters typed into the `/` bar for recall
+type FilterConfig struct {
+	Recent []string `yaml:"recent"` // newest first
+}
+
+// Remember puts query at the front of the recent filters, dropping duplicates
+func (f *FilterConfig) Remember(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	recent := []string{query}
+	for _, q := range f.Recent {
+		if q != query && len(recent) < maxRecentFilters {
+			recent = append(recent, q)
+		}
+	}
+	f.Recent = recent
+}
+
 // Default config
 func DefaultConfig() *Config {
 	return &Config{
+		Version: CurrentVersion,
 		//  8%  CONTAINER ID
 		//  14%  NAME
 		//   6%  MEMORY
@@ -65,43 +175,56 @@ func DefaultConfig() *Config {
 		//  13%  STATUS
 		//  13%  PORTS
 		Layout: LayoutConfig{
-			ContainerId:        8,
-			ContainerNameWidth: 14,
-			MemoryWidth:        6,
-			CPUWidth:           6,
-			NetIOWidth:         10,
-			DiskIOWidth:        12,
-			ImageWidth:         18,
-			StatusWidth:        13,
-			PortWidth:          13,
-
-			ContainerIdVisible:   true,
-			ContainerNameVisible: true,
-			MemoryVisible:        true,
-			CPUVisible:           true,
-			NetIOVisible:         false,
-			DiskIOVisible:        false,
-			ImageVisible:         true,
-			StatusVisible:        true,
-			PortVisible:          true,
+			Columns: []ColumnConfig{
+				{Name: "id", Width: 8},
+				{Name: "name", Width: 14},
+				{Name: "memory", Width: 6},
+				{Name: "cpu", Width: 6},
+				{Name: "net_io", Width: 10, Hidden: true},
+				{Name: "disk_io", Width: 12, Hidden: true},
+				{Name: "image", Width: 18},
+				{Name: "status", Width: 13},
+				{Name: "ports", Width: 13},
+			},
 		},
 		Performance: PerformanceConfig{
 			PollRate: 2,
 		},
 		Runtime: RuntimeConfig{
 			Type: "docker",
-			// optional, would add support later for custom sockets
+			// optional, empty means /var/run/docker.sock or the podman socket
 			Socket:       "",
 			RunPreChecks: true,
 		},
 		Exec: ExecConfig{
 			Shell: "/bin/sh",
 		},
+		Safety: SafetyConfig{
+			ProtectedLabels: []string{"dockmate.protected=true"},
+		},
+		Display: DisplayConfig{
+			Units: "si",
+		},
+		Theme: ThemeConfig{
+			Name: "dark",
+		},
 	}
 }
 
+// set by --config, wins over the default location
+var pathOverride string
+
+// SetPath makes Load and Save use path instead of the default location
+func SetPath(path string) {
+	pathOverride = path
+}
+
 // Get config path
 func GetConfigPath() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+
 	// Try XDG_CONFIG_HOME first
 	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
 		return filepath.Join(xdg, "dockmate", "config.yml"), nil
@@ -116,7 +239,10 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(home, ".config", "dockmate", "config.yml"), nil
 }
 
-// Load config
+// Load reads the config file, a missing file is the defaults. when the file
+// has errors it returns a *ValidationError along with a config that's still
+// usable: bad values are replaced by their defaults, and a file that doesn't
+// parse at all is all defaults
 func Load() (*Config, error) {
 	path, err := GetConfigPath()
 	if err != nil {
@@ -124,28 +250,25 @@ func Load() (*Config, error) {
 	}
 
 	// If file doesn't exist, return default
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
 		return DefaultConfig(), nil
 	}
-
-	// Read file
-	data, err := os.ReadFile(path)
 	if err != nil {
-		return DefaultConfig(), nil
+		return DefaultConfig(), &ValidationError{Path: path, Problems: []Problem{{Message: err.Error(), Severity: Error}}}
 	}
 
-	// Parse YAML
-	cfg := DefaultConfig()
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		// If YAML is invalid, return default config
-		return DefaultConfig(), nil
+	data = migrateFile(path, data)
+	cfg, problems := Validate(data)
+	var errs []Problem
+	for _, p := range problems {
+		if p.Severity == Error {
+			errs = append(errs, p)
+		}
 	}
-
-	// Apply defaults for missing fields
-	if cfg.Exec.Shell == "" {
-		cfg.Exec.Shell = "/bin/sh"
+	if len(errs) > 0 {
+		return cfg, &ValidationError{Path: path, Problems: errs}
 	}
-
 	return cfg, nil
 }
 
@@ -162,8 +285,10 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(c)
+	// Marshal to YAML, always as the current schema
+	out := *c
+	out.Version = CurrentVersion
+	data, err := yaml.Marshal(&out)
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,16 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/shubh-io/dockmate/internal/columns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -16,7 +20,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "", cfg.Runtime.Socket)
 	assert.Equal(t, "/bin/sh", cfg.Exec.Shell)
 	assert.Equal(t, 2, cfg.Performance.PollRate)
-	assert.Equal(t, 8, cfg.Layout.ContainerId)
+	assert.Equal(t, ColumnConfig{Name: "id", Width: 8}, cfg.Layout.Columns[0])
 }
 
 func TestLoadNonExistent(t *testing.T) {
@@ -88,25 +92,11 @@ func TestSaveAndLoad(t *testing.T) {
 
 	cfg := &Config{
 		Layout: LayoutConfig{
-			ContainerId:        10,
-			ContainerNameWidth: 15,
-			MemoryWidth:        7,
-			CPUWidth:           7,
-			NetIOWidth:         11,
-			DiskIOWidth:        13,
-			ImageWidth:         19,
-			StatusWidth:        14,
-			PortWidth:          14,
-
-			ContainerIdVisible:   true,
-			ContainerNameVisible: true,
-			MemoryVisible:        true,
-			CPUVisible:           true,
-			NetIOVisible:         true,
-			DiskIOVisible:        true,
-			ImageVisible:         true,
-			StatusVisible:        true,
-			PortVisible:          true,
+			Columns: []ColumnConfig{
+				{Name: "name", Width: 30},
+				{Name: "label:com.example.team", Width: 10},
+				{Name: "cpu", Width: 7, Hidden: true},
+			},
 		},
 		Performance: PerformanceConfig{
 			PollRate: 4,
@@ -130,7 +120,7 @@ func TestSaveAndLoad(t *testing.T) {
 	assert.Equal(t, cfg.Runtime.Socket, loaded.Runtime.Socket)
 	assert.Equal(t, cfg.Exec.Shell, loaded.Exec.Shell)
 	assert.Equal(t, cfg.Performance.PollRate, loaded.Performance.PollRate)
-	assert.Equal(t, cfg.Layout.ContainerId, loaded.Layout.ContainerId)
+	assert.Equal(t, cfg.Layout.Columns, loaded.Layout.Columns)
 }
 
 func TestGetConfigPath(t *testing.T) {
@@ -154,6 +144,23 @@ func TestGetConfigPath(t *testing.T) {
 	})
 }
 
+func TestSetPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.yml")
+	SetPath(path)
+	t.Cleanup(func() { SetPath("") })
+
+	got, err := GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, path, got)
+
+	cfg := DefaultConfig()
+	cfg.Performance.PollRate = 7
+	require.NoError(t, cfg.Save())
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 7, loaded.Performance.PollRate)
+}
+
 func TestLoadInvalidYAML(t *testing.T) {
 	tempDir := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", tempDir)
@@ -164,9 +171,350 @@ func TestLoadInvalidYAML(t *testing.T) {
 	configPath := filepath.Join(configDir, "config.yml")
 	require.NoError(t, os.WriteFile(configPath, []byte("invalid: yaml: content:"), 0644))
 
+	// the defaults still come back, but not silently
 	cfg, err := Load()
 
-	require.NoError(t, err)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, configPath, verr.Path)
+	require.Len(t, verr.Problems, 1)
+	assert.Equal(t, 1, verr.Problems[0].Line)
 	assert.Equal(t, "/bin/sh", cfg.Exec.Shell)
 	assert.Equal(t, "docker", cfg.Runtime.Type)
 }
+
+func TestValidate(t *testing.T) {
+	cfg, problems := Validate([]byte(`layout:
+  cpu_width: -5
+  image_width: 30
+performance:
+  poll_rate: 0
+  refersh: 3
+runtime:
+  type: dokcer
+exec:
+  shell: bash
+display:
+  units: fast
+hosts:
+  - name: vm1
+    host: tcp://vm1:2375
+  - name: broken
+filter:
+  recent: nope
+`))
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Severity.String()+" "+p.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"warning line 6: performance.refersh: unknown key, it's ignored",
+		"error line 18: cannot unmarshal !!str `nope` into []string",
+		"error line 2: layout.columns[3].width: width can't be negative, got -5",
+		"error line 5: performance.poll_rate: must be at least 1 second, got 0",
+		`error line 8: runtime.type: must be docker or podman, got "dokcer"`,
+		`error line 10: exec.shell: must be an absolute path like /bin/bash, got "bash"`,
+		`error line 12: display.units: must be si or iec, got "fast"`,
+		"error line 16: hosts[1]: set exactly one of context, host or connection",
+	}, got)
+
+	// only the bad values fell back, the rest of the file is kept
+	assert.Equal(t, 30, columnWidth(cfg, "image"))
+	assert.Equal(t, 6, columnWidth(cfg, "cpu"))
+	assert.Equal(t, 2, cfg.Performance.PollRate)
+	assert.Equal(t, "docker", cfg.Runtime.Type)
+	assert.Equal(t, "/bin/sh", cfg.Exec.Shell)
+	require.Len(t, cfg.Hosts, 1)
+	assert.Equal(t, "vm1", cfg.Hosts[0].Name)
+
+	_, problems = Validate([]byte("runtime:\n  type: podman\n"))
+	assert.Empty(t, problems)
+}
+
+func TestKeysConfig(t *testing.T) {
+	cfg, problems := Validate([]byte(`keys:
+  preset: vim
+  stop: b
+  logs: [l, ctrl+l]
+  info: s
+  fly: f
+`))
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Severity.String()+" "+p.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"error line 6: keys.fly: unknown action",
+		`error line 5: keys.info: "s" is start's key too`,
+	}, got)
+	assert.Equal(t, "vim", cfg.Keys.Preset)
+	assert.Equal(t, KeyList{"b"}, cfg.Keys.Bindings["stop"])
+	assert.Equal(t, KeyList{"l", "ctrl+l"}, cfg.Keys.Bindings["logs"])
+
+	_, problems = Validate([]byte("keys:\n  preset: nano\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 2, problems[0].Line)
+}
+
+func TestThemeConfig(t *testing.T) {
+	cfg, problems := Validate([]byte(`theme:
+  name: paper
+  accent: "#0e7490"
+  stopped: red
+`))
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Severity.String()+" "+p.String())
+	}
+	assert.Equal(t, []string{
+		`error line 2: theme.name: unknown theme "paper", use dark, light, high-contrast, solarized, monochrome`,
+		`error line 4: theme.stopped: "red" isn't a color, use #rrggbb, #rgb or 0-255`,
+	}, got)
+	assert.Equal(t, "dark", cfg.Theme.Name)
+	assert.Equal(t, map[string]string{"accent": "#0e7490"}, cfg.Theme.Colors)
+}
+
+func TestSafetyProtects(t *testing.T) {
+	s := SafetyConfig{
+		ProtectedLabels: []string{"dockmate.protected=true", "env"},
+		ProtectedNames:  []string{"prod-*"},
+	}
+
+	assert.True(t, s.Protects("/prod-db", nil))
+	assert.True(t, s.Protects("web", map[string]string{"dockmate.protected": "true"}))
+	assert.False(t, s.Protects("web", map[string]string{"dockmate.protected": "false"}))
+	assert.True(t, s.Protects("web", map[string]string{"env": ""}))
+	assert.False(t, s.Protects("staging-db", map[string]string{"other": "x"}))
+}
+
+func TestFilterRemember(t *testing.T) {
+	var f FilterConfig
+	f.Remember("state:running")
+	f.Remember("  ")
+	f.Remember("image:postgres")
+	f.Remember("state:running")
+	assert.Equal(t, []string{"state:running", "image:postgres"}, f.Recent)
+
+	for i := 0; i < 20; i++ {
+		f.Remember(fmt.Sprintf("cpu>%d", i))
+	}
+	assert.Len(t, f.Recent, maxRecentFilters)
+	assert.Equal(t, "cpu>19", f.Recent[0])
+}
+
+func TestMigrations(t *testing.T) {
+	// the list and CurrentVersion have to move together
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, CurrentVersion, migrations[len(migrations)-1].version)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "migrations go up one version at a time")
+	}
+}
+
+// columnWidth is the width of a listed column, -1 when it isn't listed
+func columnWidth(cfg *Config, name string) int {
+	for _, c := range cfg.Layout.Columns {
+		if c.Name == name {
+			return c.Width
+		}
+	}
+	return -1
+}
+
+// applyMigration runs the migration to version on a yaml document, on its own
+func applyMigration(t *testing.T, version int, data string) string {
+	t.Helper()
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(data), &root))
+	migrations[version-1].apply(root.Content[0])
+	out, err := yaml.Marshal(&root)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestMigratePinColumnVisibility(t *testing.T) {
+	out := applyMigration(t, 1, `# my layout
+layout:
+  cpu_width: 9
+  memory_visible: false
+`)
+	// what was set stays set, the rest is written out with the old defaults
+	assert.Equal(t, `# my layout
+layout:
+    cpu_width: 9
+    memory_visible: false
+    container_id_visible: true
+    container_name_visible: true
+    cpu_visible: true
+    net_io_visible: false
+    disk_io_visible: false
+    image_visible: true
+    status_visible: true
+    port_visible: true
+`, out)
+
+	// no layout section, nothing to pin
+	assert.Equal(t, "performance:\n    poll_rate: 3\n", applyMigration(t, 1, "performance:\n  poll_rate: 3\n"))
+}
+
+func TestMigrateListColumns(t *testing.T) {
+	out := applyMigration(t, 2, `layout:
+  cpu_width: 9
+  memory_visible: false
+  image_visible: maybe
+  theme: dark
+`)
+	// the old keys go, the list keeps the old order and defaults
+	assert.Equal(t, `layout:
+    theme: dark
+    columns:
+        - name: id
+          width: 8
+        - name: name
+          width: 14
+        - name: memory
+          width: 6
+          hidden: true
+        - name: cpu
+          width: 9
+        - name: net_io
+          width: 10
+          hidden: true
+        - name: disk_io
+          width: 12
+          hidden: true
+        - name: image
+          width: 18
+        - name: status
+          width: 13
+        - name: ports
+          width: 13
+`, out)
+
+	// a list that's there already is left alone
+	listed := "layout:\n    columns:\n        - name: cpu\n    cpu_width: 9\n"
+	assert.Equal(t, listed, applyMigration(t, 2, listed))
+}
+
+func TestMigrateColumnLayout(t *testing.T) {
+	out, m, err := Migrate([]byte(`# my layout
+layout:
+  cpu_width: 9
+  memory_visible: false
+performance:
+  poll_rate: 3
+`))
+	require.NoError(t, err)
+	assert.Equal(t, Migration{From: 0, To: 2, Steps: []string{
+		"write out the column visibility",
+		"turn the column widths into a layout.columns list",
+	}}, m)
+
+	text := string(out)
+	// version goes first, comments stay with their keys
+	assert.True(t, strings.HasPrefix(text, "version: 2\n# my layout\nlayout:\n"), text)
+	assert.NotContains(t, text, "_width")
+	assert.NotContains(t, text, "_visible")
+	cfg, problems := Validate(out)
+	assert.Empty(t, problems)
+	assert.Equal(t, 2, cfg.Version)
+	// the old order, what was set stays set, the rest gets the old defaults
+	assert.Equal(t, []ColumnConfig{
+		{Name: "id", Width: 8},
+		{Name: "name", Width: 14},
+		{Name: "memory", Width: 6, Hidden: true},
+		{Name: "cpu", Width: 9},
+		{Name: "net_io", Width: 10, Hidden: true},
+		{Name: "disk_io", Width: 12, Hidden: true},
+		{Name: "image", Width: 18},
+		{Name: "status", Width: 13},
+		{Name: "ports", Width: 13},
+	}, cfg.Layout.Columns)
+
+	// no layout section, nothing to convert
+	out, m, err = Migrate([]byte("performance:\n  poll_rate: 3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.To)
+	assert.NotContains(t, string(out), "layout")
+
+	// current and broken files are left alone
+	for _, data := range []string{"version: 2\nperformance:\n  poll_rate: 3\n", "invalid: yaml: content:", ""} {
+		out, m, err = Migrate([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, data, string(out))
+		assert.Empty(t, m.Steps)
+	}
+}
+
+func TestValidateUpgradesInMemory(t *testing.T) {
+	// an old file is checked as upgraded, with the lines it has on disk
+	cfg, problems := Validate([]byte("version: 1\nlayout:\n  cpu_width: -2\n  image_width: 20\n  colour: red\n"))
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Severity.String()+" "+p.String())
+	}
+	assert.Equal(t, []string{
+		"error line 3: layout.columns[3].width: width can't be negative, got -2",
+		"warning line 5: layout.colour: unknown key, it's ignored",
+	}, got)
+	assert.Equal(t, 20, columnWidth(cfg, "image"))
+
+	_, problems = Validate([]byte(`version: 2
+layout:
+  columns:
+    - {name: name, width: 30}
+    - {name: label:com.example.team, width: 10}
+    - {name: name, width: 5}
+    - {name: weather, width: 5}
+`))
+	got = nil
+	for _, p := range problems {
+		got = append(got, p.Severity.String()+" "+p.String())
+	}
+	assert.Equal(t, []string{
+		"error line 6: layout.columns[2].name: name is listed twice",
+		"error line 7: layout.columns[3].name: unknown column \"weather\", use " + columns.Names(),
+	}, got)
+}
+
+func TestLoadMigratesWithBackup(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+	configDir := filepath.Join(tempDir, "dockmate")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+
+	old := "layout:\n  cpu_width: 9\n"
+	configPath := filepath.Join(configDir, "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(old), 0644))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+	assert.Equal(t, 9, columnWidth(cfg, "cpu"))
+
+	backup, err := os.ReadFile(configPath + ".v0.bak")
+	require.NoError(t, err)
+	assert.Equal(t, old, string(backup))
+	upgraded, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(upgraded), "version: 2\n")
+
+	// the second start has nothing to do
+	require.NoError(t, os.Remove(configPath+".v0.bak"))
+	_, err = Load()
+	require.NoError(t, err)
+	assert.NoFileExists(t, configPath+".v0.bak")
+}
+
+func TestNewerVersionWarns(t *testing.T) {
+	cfg, problems := Validate([]byte("version: 99\nperformance:\n  poll_rate: 3\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, Warning, problems[0].Severity)
+	assert.Equal(t, 1, problems[0].Line)
+	assert.Equal(t, 3, cfg.Performance.PollRate)
+
+	_, m, err := Migrate([]byte("version: 99\n"))
+	require.NoError(t, err)
+	assert.Empty(t, m.Steps)
+}
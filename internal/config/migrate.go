@@ -0,0 +1,237 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// Schema versions and migrations
+// ============================================================================
+//
+// the file carries a `version:` key. Load runs every migration newer than the
+// file's version, keeps a copy of the old file next to it and writes the
+// upgraded one back. migrations work on the yaml tree, so comments and key
+// order survive the rewrite.
+//
+// bumping the schema: add a migration to the end of the list with the next
+// version and bump CurrentVersion. a migration spells out the values it
+// writes, DefaultConfig moves on but what an old file meant doesn't
+
+// migration upgrades a file from version-1 to version
+type migration struct {
+	version int
+	what    string // shown by `dockmate config validate`
+	apply   func(doc *yaml.Node)
+}
+
+var migrations = []migration{
+	{1, "write out the column visibility", pinColumnVisibility},
+	{2, "turn the column widths into a layout.columns list", listColumns},
+}
+
+// CurrentVersion is the schema this build reads and writes, the version of
+// the last migration
+const CurrentVersion = 2
+
+// Migration says what Migrate did to a file
+type Migration struct {
+	From, To int
+	Steps    []string // what each applied migration did, oldest first
+}
+
+// Migrate upgrades a config file to CurrentVersion. data comes back untouched
+// when it's current already, newer, or doesn't parse (Validate reports that)
+func Migrate(data []byte) ([]byte, Migration, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return data, Migration{}, nil
+	}
+	m := upgrade(root.Content[0])
+	if m.To == m.From {
+		return data, m, nil
+	}
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return data, Migration{}, err
+	}
+	return out, m, nil
+}
+
+// upgrade runs the migrations newer than the document's version on the yaml
+// tree. the nodes that were there keep their line numbers, so Validate can
+// upgrade a file in memory and still point at the right lines
+func upgrade(doc *yaml.Node) Migration {
+	if doc.Kind != yaml.MappingNode {
+		return Migration{}
+	}
+	from := 0 // files from before versioning
+	if v := mapValue(doc, "version"); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return Migration{} // Validate says it's not a number
+		}
+		from = n
+	}
+	m := Migration{From: from, To: from}
+	for _, mig := range migrations {
+		if mig.version <= from {
+			continue
+		}
+		mig.apply(doc)
+		m.Steps = append(m.Steps, mig.what)
+		m.To = mig.version
+	}
+	if m.To != from {
+		setMapValue(doc, "version", m.To)
+	}
+	return m
+}
+
+// migrateFile upgrades the file at path in place, the old one is kept as
+// path.v<N>.bak. returns the data to load either way, if the file can't be
+// written the upgrade just happens again on the next start
+func migrateFile(path string, data []byte) []byte {
+	out, m, err := Migrate(data)
+	if err != nil || m.To == m.From {
+		return data
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", path, m.From)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return out
+	}
+	os.WriteFile(path, out, 0644)
+	return out
+}
+
+// ============================================================================
+// Migrations
+// ============================================================================
+
+// v1: before versioning a missing *_visible key quietly took whatever the
+// default was, so a column with a new default would flip on or off for
+// everyone. a layout section gets all of them written out with the defaults
+// of the unversioned releases, a file without one keeps following the defaults
+func pinColumnVisibility(doc *yaml.Node) {
+	layout := mapValue(doc, "layout")
+	if layout == nil || layout.Kind != yaml.MappingNode {
+		return
+	}
+	for _, col := range []struct {
+		key     string
+		visible bool
+	}{
+		{"container_id_visible", true},
+		{"container_name_visible", true},
+		{"memory_visible", true},
+		{"cpu_visible", true},
+		{"net_io_visible", false},
+		{"disk_io_visible", false},
+		{"image_visible", true},
+		{"status_visible", true},
+		{"port_visible", true},
+	} {
+		if mapValue(layout, col.key) == nil {
+			setMapValue(layout, col.key, col.visible)
+		}
+	}
+}
+
+// v2: the nine columns had a *_width and a *_visible key each. now any
+// number of columns can be listed in any order, the old keys become a
+// layout.columns list in the old order. new nodes take the line of the key
+// they came from, so a bad width is still reported where it was written
+func listColumns(doc *yaml.Node) {
+	layout := mapValue(doc, "layout")
+	if layout == nil || layout.Kind != yaml.MappingNode || mapValue(layout, "columns") != nil {
+		return
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: layout.Line}
+	for _, col := range []struct {
+		key, name string
+		width     int
+		visible   bool
+	}{
+		{"container_id", "id", 8, true},
+		{"container_name", "name", 14, true},
+		{"memory", "memory", 6, true},
+		{"cpu", "cpu", 6, true},
+		{"net_io", "net_io", 10, false},
+		{"disk_io", "disk_io", 12, false},
+		{"image", "image", 18, true},
+		{"status", "status", 13, true},
+		{"port", "ports", 13, true},
+	} {
+		item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMapValue(item, "name", col.name)
+
+		width := mapValue(layout, col.key+"_width")
+		if width == nil {
+			setMapValue(item, "width", col.width)
+		} else {
+			// the value node as it was, a bad one is Validate's to report
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "width", Line: width.Line}
+			item.Content = append(item.Content, key, width)
+			item.Line = width.Line
+		}
+
+		visible := col.visible
+		if v := mapValue(layout, col.key+"_visible"); v != nil {
+			v.Decode(&visible) // not a bool, it keeps the old default
+		}
+		if !visible {
+			setMapValue(item, "hidden", true)
+		}
+		list.Content = append(list.Content, item)
+
+		deleteMapValue(layout, col.key+"_width")
+		deleteMapValue(layout, col.key+"_visible")
+	}
+	// appended as is, setMapValue would encode it again and lose the lines
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "columns", Line: layout.Line}
+	layout.Content = append(layout.Content, key, list)
+}
+
+// ============================================================================
+// yaml tree helpers
+// ============================================================================
+
+// mapValue returns the value under key in a mapping node, nil if it's not there
+func mapValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// deleteMapValue removes key from a mapping node, if it's there
+func deleteMapValue(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// setMapValue sets key to value, a new key goes at the end. version is the
+// exception, it goes first so it's the first thing in the file
+func setMapValue(m *yaml.Node, key string, value any) {
+	var n yaml.Node
+	n.Encode(value) // plain values, can't fail
+	if v := mapValue(m, key); v != nil {
+		*v = n
+		return
+	}
+	pair := []*yaml.Node{{Kind: yaml.ScalarNode, Value: key}, &n}
+	if key == "version" {
+		m.Content = append(pair, m.Content...)
+		return
+	}
+	m.Content = append(m.Content, pair...)
+}
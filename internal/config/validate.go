@@ -0,0 +1,334 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/columns"
+	"github.com/shubh-io/dockmate/internal/keymap"
+	"github.com/shubh-io/dockmate/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// Validation
+// ============================================================================
+//
+// Load used to swap in the defaults without a word when the file didn't
+// parse. now every problem is reported with its line: errors replace just the
+// bad value with its default (or everything, when the yaml doesn't parse),
+// warnings like unknown keys load fine but show up in `dockmate config validate`.
+
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem is one thing wrong in the config file
+type Problem struct {
+	Line     int    // 1-based, 0 when it isn't tied to a line
+	Key      string // dotted path like layout.cpu_width, empty for syntax errors
+	Message  string
+	Severity Severity
+}
+
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", p.Line)
+	}
+	if p.Key != "" {
+		b.WriteString(p.Key + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// ValidationError is what Load returns when it had to fall back to defaults,
+// the config it returns alongside is still usable
+type ValidationError struct {
+	Path     string
+	Problems []Problem // errors only, see Validate for the warnings
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("%s: using defaults for: %s", e.Path, strings.Join(lines, "; "))
+}
+
+// Validate parses a config file and checks it. the config has the defaults in
+// place of anything that was reported as an error
+func Validate(data []byte) (*Config, []Problem) {
+	cfg := DefaultConfig()
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return cfg, yamlProblems(err)
+	}
+	if len(root.Content) == 0 {
+		return cfg, nil // empty file
+	}
+	doc := root.Content[0]
+	// an old file is checked as what it's upgraded to on the next start
+	upgrade(doc)
+
+	v := &validator{lines: map[string]int{}}
+	v.walk(doc, reflect.TypeOf(Config{}), "")
+
+	// type mismatches are reported per value, yaml.v3 still decodes the rest
+	if err := doc.Decode(cfg); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return DefaultConfig(), append(v.problems, yamlProblems(err)...)
+		}
+		v.problems = append(v.problems, yamlProblems(err)...)
+	}
+
+	v.check(cfg)
+	// in file order, the checks after decoding would come last otherwise
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].Line < v.problems[j].Line
+	})
+	return cfg, v.problems
+}
+
+// "yaml: line 3: mapping values are not allowed" and friends
+var yamlLine = regexp.MustCompile(`line (\d+): (.*)`)
+
+func yamlProblems(err error) []Problem {
+	var msgs []string
+	var typeErr *yaml.TypeError
+	firstLine := 0
+	if errors.As(err, &typeErr) {
+		msgs = typeErr.Errors
+	} else {
+		msgs = []string{err.Error()}
+		// yaml.v3 leaves the line out of syntax errors on the first one
+		firstLine = 1
+	}
+
+	problems := make([]Problem, 0, len(msgs))
+	for _, msg := range msgs {
+		p := Problem{Line: firstLine, Message: strings.TrimPrefix(msg, "yaml: "), Severity: Error}
+		if m := yamlLine.FindStringSubmatch(msg); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+			p.Message = m[2]
+		}
+		problems = append(problems, p)
+	}
+	return problems
+}
+
+type validator struct {
+	lines    map[string]int // key path -> line, for the checks after decoding
+	problems []Problem
+}
+
+func (v *validator) add(key string, sev Severity, format string, args ...any) {
+	v.problems = append(v.problems, Problem{
+		Line:     v.lines[key],
+		Key:      key,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: sev,
+	})
+}
+
+// walk flags keys the struct doesn't have and remembers where each key is
+func (v *validator) walk(node *yaml.Node, t reflect.Type, prefix string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := path.Join(prefix, key.Value)
+			v.lines[dotted(keyPath)] = key.Line
+			ft, ok := fields[key.Value]
+			if !ok {
+				ft, ok = fields["*"]
+			}
+			if !ok {
+				v.problems = append(v.problems, Problem{
+					Line:     key.Line,
+					Key:      dotted(keyPath),
+					Message:  "unknown key, it's ignored",
+					Severity: Warning,
+				})
+				continue
+			}
+			v.walk(value, ft, keyPath)
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", prefix, i)
+			v.lines[dotted(itemPath)] = item.Line
+			v.walk(item, t.Elem(), itemPath)
+		}
+	}
+}
+
+func dotted(keyPath string) string {
+	return strings.ReplaceAll(keyPath, "/", ".")
+}
+
+// yamlFields maps the yaml keys of a struct to their field types. an inline
+// map takes any other key, it's under "*"
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if opts == "inline" && f.Type.Kind() == reflect.Map {
+			fields["*"] = f.Type.Elem()
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// check looks at the values, bad ones go back to their defaults. an empty
+// string counts as not set, it quietly gets the default like a missing key
+func (v *validator) check(cfg *Config) {
+	def := DefaultConfig()
+	for _, s := range []struct{ value, def *string }{
+		{&cfg.Runtime.Type, &def.Runtime.Type},
+		{&cfg.Exec.Shell, &def.Exec.Shell},
+		{&cfg.Display.Units, &def.Display.Units},
+		{&cfg.Theme.Name, &def.Theme.Name},
+	} {
+		if *s.value == "" {
+			*s.value = *s.def
+		}
+	}
+
+	if cfg.Version > CurrentVersion {
+		v.add("version", Warning, "written by a newer dockmate (schema %d, this one reads %d), settings it added are ignored", cfg.Version, CurrentVersion)
+	}
+
+	// an unknown or repeated column is dropped, a bad width gets the default
+	seen := map[string]bool{}
+	cols := cfg.Layout.Columns[:0]
+	sum := 0
+	for i, c := range cfg.Layout.Columns {
+		key := fmt.Sprintf("layout.columns[%d]", i)
+		known, ok := columns.Lookup(c.Name)
+		switch {
+		case !ok:
+			v.add(key+".name", Error, "unknown column %q, use %s", c.Name, columns.Names())
+			continue
+		case seen[c.Name]:
+			v.add(key+".name", Error, "%s is listed twice", c.Name)
+			continue
+		}
+		seen[c.Name] = true
+		if c.Width < 0 {
+			v.add(key+".width", Error, "width can't be negative, got %d", c.Width)
+			c.Width = known.Width
+		}
+		if !c.Hidden {
+			sum += c.Width
+		}
+		cols = append(cols, c)
+	}
+	cfg.Layout.Columns = cols
+
+	// widths are percentages of the screen, shared by the visible columns
+	switch {
+	case sum == 0:
+		v.add("layout.columns", Error, "the visible columns have no width at all")
+		cfg.Layout = def.Layout
+	case sum > 100:
+		v.add("layout.columns", Warning, "the visible column widths add up to %d%%, they're scaled down to fit", sum)
+	}
+
+	if cfg.Performance.PollRate < 1 {
+		v.add("performance.poll_rate", Error, "must be at least 1 second, got %d", cfg.Performance.PollRate)
+		cfg.Performance.PollRate = def.Performance.PollRate
+	}
+
+	switch cfg.Runtime.Type {
+	case "docker", "podman":
+	default:
+		v.add("runtime.type", Error, "must be docker or podman, got %q", cfg.Runtime.Type)
+		cfg.Runtime.Type = def.Runtime.Type
+	}
+
+	if !strings.HasPrefix(cfg.Exec.Shell, "/") {
+		v.add("exec.shell", Error, "must be an absolute path like /bin/bash, got %q", cfg.Exec.Shell)
+		cfg.Exec.Shell = def.Exec.Shell
+	}
+
+	switch cfg.Display.Units {
+	case "si", "iec":
+	default:
+		v.add("display.units", Error, "must be si or iec, got %q", cfg.Display.Units)
+		cfg.Display.Units = def.Display.Units
+	}
+
+	// bad bindings are reported here, the TUI drops them the same way
+	_, keyProblems := keymap.Resolve(cfg.Keys.Preset, cfg.Keys.Overrides())
+	for _, p := range keyProblems {
+		v.add("keys."+p.Action, Error, "%s", p.Message)
+	}
+
+	// same for the theme, an unknown name means the default one
+	_, themeProblems := theme.Resolve(cfg.Theme.Name, cfg.Theme.Colors)
+	for _, p := range themeProblems {
+		v.add("theme."+p.Role, Error, "%s", p.Message)
+		if p.Role == "name" {
+			cfg.Theme.Name = def.Theme.Name
+		} else {
+			delete(cfg.Theme.Colors, p.Role)
+		}
+	}
+
+	// a broken host is dropped, the rest stay usable
+	hosts := cfg.Hosts[:0]
+	for i, h := range cfg.Hosts {
+		key := fmt.Sprintf("hosts[%d]", i)
+		set := 0
+		for _, s := range []string{h.Context, h.Host, h.Connection} {
+			if s != "" {
+				set++
+			}
+		}
+		switch {
+		case h.Name == "":
+			v.add(key, Error, "needs a name")
+		case h.Name == "local" || h.Name == "all":
+			v.add(key+".name", Error, "%q is taken by the host switcher", h.Name)
+		case set != 1:
+			v.add(key, Error, "set exactly one of context, host or connection")
+		default:
+			hosts = append(hosts, h)
+		}
+	}
+	cfg.Hosts = hosts
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Container actions
+// ============================================================================
+
+// ContainerAction is a lifecycle action on a single container. the plain ones
+// (start, pause, rm...) only need Kind, kill, stop and rename carry an option.
+type ContainerAction struct {
+	Kind    string // start, stop, restart, pause, unpause, kill, rename, rm
+	Signal  string // kill: signal to send, empty sends the runtime default (SIGKILL)
+	Timeout *int   // stop/restart: seconds to wait before killing, nil = the container's own
+	Name    string // rename: the new name
+}
+
+// Action is a ContainerAction without options
+func Action(kind string) ContainerAction {
+	return ContainerAction{Kind: kind}
+}
+
+// KillSignals are offered by the kill picker, most useful first
+var KillSignals = []string{"SIGTERM", "SIGKILL", "SIGHUP", "SIGINT", "SIGQUIT", "SIGUSR1", "SIGUSR2"}
+
+// same rule the daemon applies to container names
+var validName = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// Validate catches what the daemon would reject before anything runs
+func (a ContainerAction) Validate() error {
+	switch a.Kind {
+	case "start", "stop", "restart", "pause", "unpause", "kill", "rm", "remove":
+	case "rename":
+		if !validName.MatchString(a.Name) {
+			return fmt.Errorf("invalid name %q, letters, digits and _.- only", a.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported action %q", a.Kind)
+	}
+	if a.Timeout != nil && *a.Timeout < 0 {
+		return fmt.Errorf("stop timeout can't be negative")
+	}
+	return nil
+}
+
+// String is the action as a short command, "kill SIGHUP" or "stop -t 30"
+func (a ContainerAction) String() string {
+	switch {
+	case a.Kind == "kill" && a.Signal != "":
+		return "kill " + a.Signal
+	case a.Kind == "rename":
+		return "rename " + a.Name
+	case a.Timeout != nil && (a.Kind == "stop" || a.Kind == "restart"):
+		return fmt.Sprintf("%s -t %d", a.Kind, *a.Timeout)
+	}
+	return a.Kind
+}
+
+// cliArgs builds the command line for bin ("docker" or "podman")
+func (a ContainerAction) cliArgs(bin, containerID string) []string {
+	args := []string{a.Kind}
+	switch a.Kind {
+	case "remove":
+		args = []string{"rm"}
+	case "stop", "restart":
+		if a.Timeout != nil {
+			// docker renamed --time to --timeout in 23.0 so only -t works on
+			// every version, podman only knows --time
+			flag := "-t"
+			if bin == "podman" {
+				flag = "--time"
+			}
+			args = append(args, flag, strconv.Itoa(*a.Timeout))
+		}
+	case "kill":
+		if a.Signal != "" {
+			args = append(args, "--signal", a.Signal)
+		}
+	case "rename":
+		return []string{"rename", containerID, a.Name}
+	}
+	return append(args, containerID)
+}
+
+// actionTimeout gives a timed stop its grace period on top of the usual 30s
+func actionTimeout(a ContainerAction) time.Duration {
+	d := 30 * time.Second
+	if a.Timeout != nil {
+		d += time.Duration(*a.Timeout) * time.Second
+	}
+	return d
+}
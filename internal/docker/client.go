@@ -5,65 +5,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
-
-	"github.com/shubh-io/dockmate/internal/config"
 )
 
-// runtimeBin returns the configured container runtime binary name (podman or docker).
-
-func runtimeBin() string {
-	cfg, err := config.Load()
-	if err != nil {
-		return "docker"
-	}
-
-	rt := strings.TrimSpace(strings.ToLower(cfg.Runtime.Type))
-	if rt == "podman" {
-		return "podman"
-	}
+// ============================================================================
+// Shared CLI backend
+// ============================================================================
 
-	return "docker"
+// cliRuntime holds what docker and podman CLIs have in common.
+// DockerCLI and PodmanCLI embed it and only override the bits where output differs.
+type cliRuntime struct {
+	bin string   // binary we fork, "docker" or "podman"
+	env []string // added to the environment, picks the daemon (DOCKER_HOST, DOCKER_CONTEXT, CONTAINER_CONNECTION)
 }
 
-// GetContainerStats grabs cpu/mem/pids for a container
-// returns empty strings on error so we don't block the UI
-func GetContainerStats(containerID string) (cpu string, mem string, pids string, netIO string, blockIO string, err error) {
-	// 3 sec timeout because some containers are weird and hang
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, runtimeBin(), "stats", "--no-stream", "--format", "{{json .}}", containerID)
+// Name returns the binary name (also used for `exec -it`)
+func (r cliRuntime) Name() string {
+	return r.bin
+}
 
-	output, err := cmd.Output()
-	if err != nil {
-		return "", "", "", "", "", err
+func (r cliRuntime) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, r.bin, args...)
+	if len(r.env) > 0 {
+		cmd.Env = append(os.Environ(), r.env...)
 	}
+	return cmd
+}
 
-	type statsEntry struct {
-		CPUPerc string `json:"CPUPerc"`
-		MemPerc string `json:"MemPerc"`
-		PIDs    string `json:"PIDs"`
-		NetIO   string `json:"NetIO"`
-		BlockIO string `json:"BlockIO"`
-	}
+// ExecCommand is `exec -it` for handing the terminal over to a container shell
+func (r cliRuntime) ExecCommand(containerID string, cmd ...string) *exec.Cmd {
+	return cliCommand(r.bin, r.env, append([]string{"exec", "-it", containerID}, cmd...)...)
+}
 
-	var s statsEntry
-	if err := json.Unmarshal(output, &s); err != nil {
-		return "", "", "", "", "", err
+// cliCommand is a command without a context, for interactive use
+func cliCommand(bin string, env []string, args ...string) *exec.Cmd {
+	cmd := exec.Command(bin, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
 	}
-
-	return s.CPUPerc, s.MemPerc, s.PIDs, s.NetIO, s.BlockIO, nil
+	return cmd
 }
 
-func GetLogs(containerID string) ([]string, error) {
+// GetLogs returns the last 100 log lines for a container
+func (r cliRuntime) GetLogs(containerID string) ([]string, error) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, runtimeBin(), "logs", "--tail", "100", containerID)
+	cmd := r.command(ctx, "logs", "--tail", "100", containerID)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, err
@@ -99,558 +91,80 @@ func GetLogs(containerID string) ([]string, error) {
 	return out, nil
 }
 
-func ListContainers() ([]Container, error) {
-	// 30 sec timeout since we fetch stats for each running container
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	runtime := runtimeBin()
-	var cmd *exec.Cmd
-
-	// Docker returns newline-delimited JSON
-	cmd = exec.CommandContext(ctx, runtime, "ps", "--format", "{{json .}}", "--all")
-
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
+// DoAction runs a lifecycle action (start, stop, kill, rename...) against a container
+func (r cliRuntime) DoAction(action ContainerAction, containerID string) error {
+	if err := action.Validate(); err != nil {
+		return err
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout(action))
+	defer cancel()
 
-	var out []Container
-	var runningIDs []string
-
-	if runtime == "podman" {
-		// Podman format - JSON array
-		type podmanEntry struct {
-			Id     string            `json:"Id"`
-			Names  []string          `json:"Names"`
-			Image  string            `json:"Image"`
-			Status string            `json:"Status"`
-			State  string            `json:"State"`
-			Labels map[string]string `json:"Labels"`
-			Ports  []struct {
-				HostPort      int    `json:"host_port"`
-				ContainerPort int    `json:"container_port"`
-				Protocol      string `json:"protocol"`
-			} `json:"Ports"`
-		}
-
-		var entries []podmanEntry
-		if err := json.Unmarshal(output, &entries); err == nil {
-
-			for _, e := range entries {
-				// Format ports like Docker does
-				ports := ""
-				if len(e.Ports) > 0 {
-					var portStrs []string
-					for _, p := range e.Ports {
-						if p.HostPort > 0 {
-							portStrs = append(portStrs, fmt.Sprintf("0.0.0.0:%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol))
-						}
-					}
-					ports = strings.Join(portStrs, ", ")
-				}
-
-				state := strings.ToLower(e.State)
-
-				// check for compose project or quadlet unit
-				var projectName string
-				if runtime == "podman" {
-					projectName := e.Labels["io.podman.compose.project"]
-					if projectName == "" {
-						if unit, ok := e.Labels["PODMAN_SYSTEMD_UNIT"]; ok {
-							projectName = strings.TrimSuffix(unit, ".service")
-						}
-					}
-				} else {
-					projectName = e.Labels["com.docker.compose.project"]
-
-				}
-
-				container := Container{
-					ID:                   e.Id,
-					Names:                e.Names,
-					Image:                e.Image,
-					Status:               e.Status,
-					State:                state,
-					Ports:                ports,
-					ComposeProject:       projectName,
-					ComposeService:       e.Labels["com.docker.compose.service"],
-					ComposeDirectory:     e.Labels["com.docker.compose.project.working_dir"],
-					ComposeFileDirectory: (e.Labels["com.docker.compose.project.working_dir"] + "/" + e.Labels["com.docker.compose.project.config_files"]),
-				}
-
-				if state == "running" {
-					runningIDs = append(runningIDs, e.Id)
-				}
-
-				out = append(out, container)
-			}
-		} else {
-			// Fallback -
-			scanner := bufio.NewScanner(strings.NewReader(string(output)))
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				if line == "" {
-					continue
-				}
-
-				var e podmanEntry
-				if err := json.Unmarshal([]byte(line), &e); err != nil {
-					continue // skip weird lines
-				}
-
-				ports := ""
-				if len(e.Ports) > 0 {
-					var portStrs []string
-					for _, p := range e.Ports {
-						if p.HostPort > 0 {
-							portStrs = append(portStrs, fmt.Sprintf("0.0.0.0:%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol))
-						}
-					}
-					ports = strings.Join(portStrs, ", ")
-				}
-
-				state := strings.ToLower(e.State)
-
-				// check for compose project or quadlet unit
-				projectName := e.Labels["io.podman.compose.project"]
-				if projectName == "" {
-					if unit, ok := e.Labels["PODMAN_SYSTEMD_UNIT"]; ok {
-						projectName = strings.TrimSuffix(unit, ".service")
-					}
-				}
-
-				container := Container{
-					ID:                   e.Id,
-					Names:                e.Names,
-					Image:                e.Image,
-					Status:               e.Status,
-					State:                state,
-					Ports:                ports,
-					ComposeProject:       projectName,
-					ComposeService:       e.Labels["com.docker.compose.service"],
-					ComposeDirectory:     e.Labels["com.docker.compose.project.working_dir"],
-					ComposeFileDirectory: (e.Labels["com.docker.compose.project.working_dir"] + "/" + e.Labels["com.docker.compose.project.config_files"]),
-				}
-
-				if state == "running" {
-					runningIDs = append(runningIDs, e.Id)
-				}
-
-				out = append(out, container)
-			}
-			if err := scanner.Err(); err != nil {
-				return nil, err
-			}
-		}
-	} else {
-		type dockerEntry struct {
-			ID     string `json:"ID"`
-			Names  string `json:"Names"`
-			Image  string `json:"Image"`
-			Status string `json:"Status"`
-			Ports  string `json:"Ports"`
-			Labels string `json:"Labels"`
-		}
-
-		scanner := bufio.NewScanner(strings.NewReader(string(output)))
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" {
-				continue
-			}
-
-			var e dockerEntry
-			if err := json.Unmarshal([]byte(line), &e); err != nil {
-				return nil, fmt.Errorf("parsing docker output: %w", err)
-			}
-
-			names := []string{}
-			if e.Names != "" {
-				for _, n := range strings.Split(e.Names, ",") {
-					names = append(names, strings.TrimSpace(n))
-				}
-			}
-
-			st := strings.ToLower(strings.TrimSpace(e.Status))
-			state := "unknown"
-			if strings.HasPrefix(st, "up") {
-				state = "running"
-			} else if strings.HasPrefix(st, "paused") || strings.Contains(st, "paused") {
-				state = "paused"
-			} else if strings.Contains(st, "restarting") {
-				state = "restarting"
-			} else if strings.HasPrefix(st, "exited") || strings.Contains(st, "exited") || strings.Contains(st, "dead") {
-				state = "exited"
-			} else if strings.HasPrefix(st, "created") {
-				state = "created"
-			}
-
-			container := Container{
-				ID:                   e.ID,
-				Names:                names,
-				Image:                e.Image,
-				Status:               e.Status,
-				State:                state,
-				Ports:                e.Ports,
-				ComposeProject:       parseLabels(e.Labels)["com.docker.compose.project"],
-				ComposeService:       parseLabels(e.Labels)["com.docker.compose.service"],
-				ComposeDirectory:     parseLabels(e.Labels)["com.docker.compose.project.working_dir"],
-				ComposeFileDirectory: parseLabels(e.Labels)["com.docker.compose.project.config_files"],
-			}
+	_, err := r.command(ctx, action.cliArgs(r.bin, containerID)...).Output()
+	return cliError(err)
+}
 
-			if state == "running" {
-				runningIDs = append(runningIDs, e.ID)
-			}
+func (r cliRuntime) RemoveContainer(containerID string, opts RemoveOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-			out = append(out, container)
-		}
-		if err := scanner.Err(); err != nil {
-			return nil, err
-		}
+	args := []string{"rm"}
+	if opts.Force {
+		args = append(args, "--force")
 	}
-
-	// Fetch stats for all running containers in ONE call
-	if len(runningIDs) > 0 {
-		statsMap, err := GetAllContainerStats(runningIDs)
-		if err == nil {
-			for i := range out {
-				if stats, ok := statsMap[out[i].ID]; ok {
-					out[i].CPU = stats.CPU
-					out[i].Memory = stats.Memory
-					out[i].NetIO = stats.NetIO
-					out[i].BlockIO = stats.BlockIO
-				}
-			}
-		}
+	if opts.Volumes {
+		args = append(args, "--volumes")
 	}
-
-	return out, nil
+	_, err := r.command(ctx, append(args, containerID)...).Output()
+	return cliError(err)
 }
 
-// GetAllContainerStats fetches stats for multiple containers in a single docker stats call
-
-func GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
-	if len(containerIDs) == 0 {
-		return nil, nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Inspect runs `inspect` for a single container, both CLIs print a docker-shaped JSON array
+func (r cliRuntime) Inspect(containerID string) (*ContainerInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	runtime := runtimeBin()
-
-	args := []string{"stats", "--no-stream", "--format"}
-
-	if runtime == "podman" {
-		args = append(args, `{"ID":"{{.ID}}","CPUPerc":"{{.CPUPerc}}","MemPerc":"{{.MemPerc}}","NetIO":"{{.NetIO}}","BlockIO":"{{.BlockIO}}"}`)
-	} else {
-		// for docker
-		args = append(args, "{{json .}}")
-	}
-
-	args = append(args, containerIDs...)
-
-	cmd := exec.CommandContext(ctx, runtime, args...)
-	stdout, err := cmd.StdoutPipe()
+	output, err := r.command(ctx, "inspect", "--type", "container", containerID).Output()
 	if err != nil {
 		return nil, err
 	}
+	return parseInspectArray(output)
+}
 
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-
-	// Read stats JSON lines
-	scanner := bufio.NewScanner(stdout)
-	statsMap := make(map[string]ContainerStats)
-	type statsEntry struct {
-		ID      string `json:"ID"`
-		CPUPerc string `json:"CPUPerc"`
-		MemPerc string `json:"MemPerc"`
-		NetIO   string `json:"NetIO"`
-		BlockIO string `json:"BlockIO"`
-	}
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-
-		var s statsEntry
-		if err := json.Unmarshal([]byte(line), &s); err != nil {
-			continue // skip weird lines
-		}
-
-		mapID := s.ID
-		if runtime == "podman" {
-			for _, longID := range containerIDs {
-				if strings.HasPrefix(longID, s.ID) {
-					mapID = longID
-					break
-				}
-			}
-		}
-
-		statsMap[mapID] = ContainerStats{
-			ID:      mapID,
-			CPU:     s.CPUPerc,
-			Memory:  s.MemPerc,
-			NetIO:   s.NetIO,
-			BlockIO: s.BlockIO,
-		}
+// inspectAll inspects every given container in one call, for the views that
+// need more than ps prints (mounts, networks)
+func (r cliRuntime) inspectAll(ctx context.Context, ids []string) ([]inspectJSON, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
-
-	if err := scanner.Err(); err != nil {
-		cmd.Wait()
-		return nil, err
+	args := append([]string{"inspect", "--type", "container"}, ids...)
+	// a container removed since ps makes inspect exit 1, the rest are still printed
+	output, err := r.command(ctx, args...).Output()
+	if err != nil && len(output) == 0 {
+		return nil, cliError(err)
 	}
 
-	if err := cmd.Wait(); err != nil {
-		return nil, err
+	var raws []inspectJSON
+	if err := json.Unmarshal(output, &raws); err != nil {
+		return nil, fmt.Errorf("parsing inspect output: %w", err)
 	}
-
-	return statsMap, nil
+	return raws, nil
 }
 
-func DoAction(action, containerID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, runtimeBin(), action, containerID)
-	return cmd.Run()
-}
-
-// FetchComposeProjects fetches all Docker/Podman Compose projects with their containers
-
-func FetchComposeProjects() (map[string]*ComposeProject, error) {
-	// 30 sec timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	runtime := runtimeBin()
-	var cmd *exec.Cmd
-
-	if runtime == "podman" {
-		// well podman uses io.podman.compose labels
-		cmd = exec.CommandContext(ctx, runtime, "ps", "-a",
-			"--filter", "label=io.podman.compose.project",
-			"--format", "json")
-	} else {
-		// and docker uses com.docker.compose labels
-		cmd = exec.CommandContext(ctx, runtime, "ps", "-a",
-			"--filter", "label=com.docker.compose.project",
-			"--format", "{{json .}}")
-	}
-
-	output, err := cmd.Output()
+// containerIDs lists every container id, running or not
+func (r cliRuntime) containerIDs(ctx context.Context) ([]string, error) {
+	output, err := r.command(ctx, "ps", "--all", "--quiet").Output()
 	if err != nil {
-		return nil, err
-	}
-
-	projects := make(map[string]*ComposeProject)
-	var runningIDs []string
-
-	if runtime == "podman" {
-		// Podman format - json array
-		type podmanEntry struct {
-			Id     string            `json:"Id"`
-			Names  []string          `json:"Names"`
-			Image  string            `json:"Image"`
-			Status string            `json:"Status"`
-			State  string            `json:"State"`
-			Labels map[string]string `json:"Labels"`
-			Ports  []struct {
-				HostPort      int    `json:"host_port"`
-				ContainerPort int    `json:"container_port"`
-				Protocol      string `json:"protocol"`
-			} `json:"Ports"`
-		}
-
-		var entries []podmanEntry
-		if err := json.Unmarshal(output, &entries); err != nil {
-			return nil, fmt.Errorf("parsing podman compose output: %w", err)
-		}
-
-		for _, e := range entries {
-
-			projectName := e.Labels["io.podman.compose.project"]
-
-			if projectName == "" {
-				if unit, ok := e.Labels["PODMAN_SYSTEMD_UNIT"]; ok {
-					//removing the .service suffix
-					projectName = strings.TrimSuffix(unit, ".service")
-				}
-			}
-
-			serviceName := e.Labels["com.docker.compose.service"]
-			// containerNumber := e.Labels["io.podman.compose.container-number"]
-			configFile := e.Labels["com.docker.compose.project.config_files"]
-			workingDir := e.Labels["com.docker.compose.project.working_dir"]
-
-			if projectName == "" {
-				continue
-			}
-
-			ports := ""
-			if len(e.Ports) > 0 {
-				var portStrs []string
-				for _, p := range e.Ports {
-					if p.HostPort > 0 {
-						portStrs = append(portStrs, fmt.Sprintf("0.0.0.0:%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol))
-					}
-				}
-				ports = strings.Join(portStrs, ", ")
-			}
-
-			state := strings.ToLower(e.State)
-
-			container := Container{
-				ID:             e.Id,
-				Names:          e.Names,
-				Image:          e.Image,
-				Status:         e.Status,
-				State:          state,
-				Ports:          ports,
-				ComposeProject: projectName,
-				ComposeService: serviceName,
-				// ComposeNumber:  containerNumber,
-				ComposeDirectory:     workingDir,
-				ComposeFileDirectory: (workingDir + "/" + configFile),
-			}
-
-			if state == "running" {
-				runningIDs = append(runningIDs, e.Id)
-			}
-
-			project, exists := projects[projectName]
-			if !exists {
-				project = &ComposeProject{
-					Name:       projectName,
-					Containers: []Container{},
-					ConfigFile: configFile,
-					WorkingDir: workingDir,
-				}
-				projects[projectName] = project
-			}
-
-			project.Containers = append(project.Containers, container)
-		}
-	} else {
-		type dockerEntry struct {
-			ID        string `json:"ID"`
-			Names     string `json:"Names"`
-			Image     string `json:"Image"`
-			Status    string `json:"Status"`
-			State     string `json:"State"`
-			Ports     string `json:"Ports"`
-			Labels    string `json:"Labels"`
-			CreatedAt string `json:"CreatedAt"`
-		}
-
-		scanner := bufio.NewScanner(strings.NewReader(string(output)))
-
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" {
-				continue
-			}
-
-			var e dockerEntry
-			if err := json.Unmarshal([]byte(line), &e); err != nil {
-				continue // Skip malformed entries
-			}
-
-			labels := parseLabels(e.Labels)
-
-			projectName := labels["com.docker.compose.project"]
-			serviceName := labels["com.docker.compose.service"]
-			containerNumber := labels["com.docker.compose.container-number"]
-
-			if projectName == "" {
-				continue
-			}
-
-			names := []string{}
-			if e.Names != "" {
-				for _, n := range strings.Split(e.Names, ",") {
-					names = append(names, strings.TrimSpace(n))
-				}
-			}
-
-			st := strings.ToLower(strings.TrimSpace(e.Status))
-			state := "unknown"
-			if strings.HasPrefix(st, "up") {
-				state = "running"
-			} else if strings.HasPrefix(st, "paused") || strings.Contains(st, "paused") {
-				state = "paused"
-			} else if strings.Contains(st, "restarting") {
-				state = "restarting"
-			} else if strings.HasPrefix(st, "exited") || strings.Contains(st, "exited") || strings.Contains(st, "dead") {
-				state = "exited"
-			} else if strings.HasPrefix(st, "created") {
-				state = "created"
-			}
-
-			container := Container{
-				ID:                   e.ID,
-				Names:                names,
-				Image:                e.Image,
-				Status:               e.Status,
-				State:                state,
-				Ports:                e.Ports,
-				ComposeProject:       projectName,
-				ComposeService:       serviceName,
-				ComposeNumber:        containerNumber,
-				ComposeDirectory:     labels["com.docker.compose.project.working_dir"],
-				ComposeFileDirectory: labels["com.docker.compose.project.config_files"],
-			}
-
-			if state == "running" {
-				runningIDs = append(runningIDs, e.ID)
-			}
-
-			// Get or create project
-			project, exists := projects[projectName]
-			if !exists {
-				project = &ComposeProject{
-					Name:       projectName,
-					Containers: []Container{},
-					ConfigFile: labels["com.docker.compose.project.config_files"],
-					WorkingDir: labels["com.docker.compose.project.working_dir"],
-				}
-				projects[projectName] = project
-			}
-
-			// Add container to project
-			project.Containers = append(project.Containers, container)
-		}
-
-		// Check scanner errors
-		if err := scanner.Err(); err != nil {
-			return nil, err
-		}
+		return nil, cliError(err)
 	}
+	return strings.Fields(string(output)), nil
+}
 
-	if len(runningIDs) > 0 {
-		statsMap, err := GetAllContainerStats(runningIDs)
-		if err == nil {
-
-			for _, project := range projects {
-				for i := range project.Containers {
-					if stats, ok := statsMap[project.Containers[i].ID]; ok {
-						project.Containers[i].CPU = stats.CPU
-						project.Containers[i].Memory = stats.Memory
-						project.Containers[i].NetIO = stats.NetIO
-						project.Containers[i].BlockIO = stats.BlockIO
-					}
-				}
-			}
-		}
-	}
+// ============================================================================
+// Helpers
+// ============================================================================
 
-	// Calculate project status
+// setProjectStatus works out all running / some stopped / all stopped per project
+func setProjectStatus(projects map[string]*ComposeProject) {
 	for _, project := range projects {
 		running := 0
 		total := len(project.Containers)
@@ -668,8 +182,73 @@ func FetchComposeProjects() (map[string]*ComposeProject, error) {
 			project.Status = SomeStopped
 		}
 	}
+}
+
+// composeProjectName finds the project a container belongs to.
+// docker compose and podman-compose label it differently, quadlets only have the systemd unit.
+func composeProjectName(labels map[string]string) string {
+	if name := labels["com.docker.compose.project"]; name != "" {
+		return name
+	}
+	if name := labels["io.podman.compose.project"]; name != "" {
+		return name
+	}
+	if unit, ok := labels["PODMAN_SYSTEMD_UNIT"]; ok {
+		return strings.TrimSuffix(unit, ".service")
+	}
+	return ""
+}
+
+// groupComposeProjects buckets containers that carry a project name into projects
+func groupComposeProjects(containers []Container) map[string]*ComposeProject {
+	projects := make(map[string]*ComposeProject)
+	for _, c := range containers {
+		if c.ComposeProject == "" {
+			continue
+		}
+		project, exists := projects[c.ComposeProject]
+		if !exists {
+			project = &ComposeProject{
+				Name:       c.ComposeProject,
+				Containers: []Container{},
+				ConfigFile: c.Labels["com.docker.compose.project.config_files"],
+				WorkingDir: c.ComposeDirectory,
+			}
+			projects[c.ComposeProject] = project
+		}
+		project.Containers = append(project.Containers, c)
+	}
+	setProjectStatus(projects)
+	return projects
+}
 
-	return projects, nil
+// parseState turns docker's human status ("Up 2 hours", "Exited (0) ...") into a state
+func parseState(status string) string {
+	st := strings.ToLower(strings.TrimSpace(status))
+	state := "unknown"
+	if strings.HasPrefix(st, "up") {
+		state = "running"
+	} else if strings.HasPrefix(st, "paused") || strings.Contains(st, "paused") {
+		state = "paused"
+	} else if strings.Contains(st, "restarting") {
+		state = "restarting"
+	} else if strings.HasPrefix(st, "exited") || strings.Contains(st, "exited") || strings.Contains(st, "dead") {
+		state = "exited"
+	} else if strings.HasPrefix(st, "created") {
+		state = "created"
+	}
+	return state
+}
+
+// splitNames splits docker's comma separated Names column
+func splitNames(s string) []string {
+	names := []string{}
+	if s != "" {
+		for _, n := range strings.Split(s, ",") {
+			names = append(names, strings.TrimSpace(n))
+		}
+	}
+	return names
 }
 
 // Handles edge cases like commas in values and empty strings
@@ -698,3 +277,14 @@ func parseLabels(labelsStr string) map[string]string {
 
 	return labels
 }
+
+// formatPodmanPorts renders podman's structured ports like docker's string column
+func formatPodmanPorts(ports []podmanPort) string {
+	var portStrs []string
+	for _, p := range ports {
+		if p.HostPort > 0 {
+			portStrs = append(portStrs, fmt.Sprintf("0.0.0.0:%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol))
+		}
+	}
+	return strings.Join(portStrs, ", ")
+}
@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Compose project actions
+// ============================================================================
+
+// ComposeAction is a command run against a whole compose project
+type ComposeAction string
+
+const (
+	ComposeUp       ComposeAction = "up"
+	ComposeStop     ComposeAction = "stop"
+	ComposeRestart  ComposeAction = "restart"
+	ComposeDown     ComposeAction = "down"
+	ComposeDownAll  ComposeAction = "down-volumes" // down, named and anonymous volumes too
+	ComposePull     ComposeAction = "pull"
+	ComposeRecreate ComposeAction = "recreate"
+)
+
+// Args is the compose subcommand for the action
+func (a ComposeAction) Args() []string {
+	switch a {
+	case ComposeUp:
+		return []string{"up", "-d"}
+	case ComposeRecreate:
+		return []string{"up", "-d", "--force-recreate"}
+	case ComposeDownAll:
+		return []string{"down", "--volumes"}
+	}
+	return []string{string(a)}
+}
+
+// needsFile is true for actions that have to read the compose file. stop,
+// restart and down only need the project name.
+func (a ComposeAction) needsFile() bool {
+	return a == ComposeUp || a == ComposeRecreate || a == ComposePull
+}
+
+// ComposeOutput is a line of compose output. the last one carries Err when
+// the command failed.
+type ComposeOutput struct {
+	Text string
+	Err  error
+}
+
+// composeFiles turns the config_files label into paths. docker compose records
+// absolute paths, podman-compose records them as given on its command line so
+// relative ones are resolved against the working dir.
+func composeFiles(p ComposeProject) []string {
+	var files []string
+	for _, f := range strings.Split(p.ConfigFile, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !filepath.IsAbs(f) && p.WorkingDir != "" {
+			f = filepath.Join(p.WorkingDir, f)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// composeArgs builds `-p <name> [-f <file>]... <action>`
+func composeArgs(p ComposeProject, action ComposeAction) ([]string, error) {
+	files := composeFiles(p)
+	if len(files) == 0 && action.needsFile() {
+		return nil, fmt.Errorf("no compose file recorded for project %s", p.Name)
+	}
+	args := []string{"-p", p.Name}
+	for _, f := range files {
+		args = append(args, "-f", f)
+	}
+	return append(args, action.Args()...), nil
+}
+
+// composeCommand picks the compose frontend for a runtime binary: the docker
+// compose plugin, or podman-compose falling back to `podman compose`
+func composeCommand(bin string) (string, []string) {
+	if strings.Contains(filepath.Base(bin), "podman") {
+		if path, err := exec.LookPath("podman-compose"); err == nil {
+			return path, nil
+		}
+	}
+	return bin, []string{"compose"}
+}
+
+// runCompose starts the action and streams stdout and stderr, compose prints
+// most of its progress on stderr. env is added to the environment.
+func runCompose(ctx context.Context, bin string, env []string, p ComposeProject, action ComposeAction) (<-chan ComposeOutput, error) {
+	args, err := composeArgs(p, action)
+	if err != nil {
+		return nil, err
+	}
+	name, prefix := composeCommand(bin)
+	cmd := exec.CommandContext(ctx, name, append(prefix, args...)...)
+	// relative paths inside the compose file (build contexts, env files) are
+	// relative to the project dir, which may not exist on this machine
+	if st, err := os.Stat(p.WorkingDir); err == nil && st.IsDir() {
+		cmd.Dir = p.WorkingDir
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan ComposeOutput)
+	var wg sync.WaitGroup
+	pipe := func(rd io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(rd)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			select {
+			case ch <- ComposeOutput{Text: line}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	wg.Add(2)
+	go pipe(stdout)
+	go pipe(stderr)
+	go func() {
+		wg.Wait()
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			ch <- ComposeOutput{Err: fmt.Errorf("compose %s %s: %v", action, p.Name, err)}
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (r cliRuntime) Compose(ctx context.Context, project ComposeProject, action ComposeAction) (<-chan ComposeOutput, error) {
+	return runCompose(ctx, r.bin, r.env, project, action)
+}
+
+// Compose shells out to the CLI, the Engine API has no compose. docker compose
+// is pointed at our socket so it talks to the same daemon we list.
+func (c *EngineClient) Compose(ctx context.Context, project ComposeProject, action ComposeAction) (<-chan ComposeOutput, error) {
+	return runCompose(ctx, c.name, c.cliEnv(), project, action)
+}
@@ -0,0 +1,174 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DockerCLI shells out to the docker binary, used when the API socket isn't reachable
+type DockerCLI struct {
+	cliRuntime
+}
+
+func NewDockerCLI() *DockerCLI {
+	return &DockerCLI{cliRuntime{bin: "docker"}}
+}
+
+// a line of `docker ps --format {{json .}}`
+type dockerPsEntry struct {
+	ID        string `json:"ID"`
+	Names     string `json:"Names"`
+	Image     string `json:"Image"`
+	Status    string `json:"Status"`
+	State     string `json:"State"`
+	Ports     string `json:"Ports"`
+	Labels    string `json:"Labels"`
+	CreatedAt string `json:"CreatedAt"`
+}
+
+func (e dockerPsEntry) toContainer() Container {
+	labels := parseLabels(e.Labels)
+	return Container{
+		ID:                   e.ID,
+		Names:                splitNames(e.Names),
+		Image:                e.Image,
+		Status:               e.Status,
+		State:                parseState(e.Status),
+		Health:               ParseHealth(e.Status),
+		Ports:                e.Ports,
+		Created:              parseCreatedAt(e.CreatedAt),
+		Labels:               labels,
+		ComposeProject:       labels["com.docker.compose.project"],
+		ComposeService:       labels["com.docker.compose.service"],
+		ComposeNumber:        labels["com.docker.compose.container-number"],
+		ComposeDirectory:     labels["com.docker.compose.project.working_dir"],
+		ComposeFileDirectory: labels["com.docker.compose.project.config_files"],
+	}
+}
+
+// parseCreatedAt reads the CreatedAt of `docker ps`, "2024-05-01 10:00:00 +0200 CEST"
+func parseCreatedAt(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05 -0700 MST", s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ps runs `docker ps -a` with extra args and parses the newline-delimited JSON
+func (r *DockerCLI) ps(ctx context.Context, extra ...string) ([]Container, error) {
+	args := append([]string{"ps", "--all", "--format", "{{json .}}"}, extra...)
+	output, err := r.command(ctx, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Container
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var e dockerPsEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("parsing docker output: %w", err)
+		}
+		out = append(out, e.toContainer())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListContainers lists every container, stats come from GetAllContainerStats
+func (r *DockerCLI) ListContainers() ([]Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return r.ps(ctx)
+}
+
+// GetAllContainerStats fetches stats for multiple containers in a single docker stats call
+func (r *DockerCLI) GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
+	if len(containerIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := append([]string{"stats", "--no-stream", "--format", "{{json .}}"}, containerIDs...)
+	output, err := r.command(ctx, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStatsLines(output, func(id string) string { return id }), nil
+}
+
+// FetchComposeProjects fetches all compose projects with their containers
+func (r *DockerCLI) FetchComposeProjects() (map[string]*ComposeProject, error) {
+	// 30 sec timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// docker uses com.docker.compose labels
+	containers, err := r.ps(ctx, "--filter", "label=com.docker.compose.project")
+	if err != nil {
+		return nil, err
+	}
+
+	return groupComposeProjects(containers), nil
+}
+
+// parseStatsLines reads `stats --format` JSON lines, mapID lets podman map short IDs back.
+// the CLIs print human sizes ("12.5MiB / 1.94GiB", "1.2kB / 648B"), they're turned
+// back into bytes here so the rest of the app only sees numbers.
+func parseStatsLines(output []byte, mapID func(string) string) map[string]ContainerStats {
+	type statsEntry struct {
+		ID       string `json:"ID"`
+		CPUPerc  string `json:"CPUPerc"`
+		MemUsage string `json:"MemUsage"`
+		NetIO    string `json:"NetIO"`
+		BlockIO  string `json:"BlockIO"`
+		PIDs     string `json:"PIDs"`
+	}
+
+	statsMap := make(map[string]ContainerStats)
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var s statsEntry
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			continue // skip weird lines
+		}
+
+		id := mapID(s.ID)
+		cpu, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s.CPUPerc), "%"), 64)
+		pids, _ := strconv.ParseUint(strings.TrimSpace(s.PIDs), 10, 64)
+		stats := ContainerStats{ID: id, CPU: cpu / 100, PIDs: pids}
+		stats.MemUsed, stats.MemLimit = parseSizePair(s.MemUsage)
+		stats.NetRx, stats.NetTx = parseSizePair(s.NetIO)
+		stats.BlockRead, stats.BlockWrite = parseSizePair(s.BlockIO)
+		statsMap[id] = stats
+	}
+	return statsMap
+}
+
+// parseSizePair reads "<size> / <size>"
+func parseSizePair(s string) (uint64, uint64) {
+	a, b, _ := strings.Cut(s, "/")
+	return uint64(parseHumanSize(a)), uint64(parseHumanSize(b))
+}
@@ -0,0 +1,707 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubh-io/dockmate/internal/config"
+)
+
+// ============================================================================
+// Engine API client
+// ============================================================================
+
+// EngineClient talks to the Docker Engine API over a unix socket (or plain tcp).
+// Podman's Docker-compatible socket (podman.sock) speaks the same API.
+type EngineClient struct {
+	name    string // "docker" or "podman", whoever owns the socket
+	network string // "unix" or "tcp"
+	socket  string // socket path, or host:port for tcp
+	http    *http.Client
+
+	cpuMu   sync.Mutex
+	lastCPU map[string]cpuSample // previous one-shot sample per container
+}
+
+// how many stats requests we keep in flight at once
+const engineStatsParallelism = 16
+
+// how long one container's stats call gets, each call has its own
+const engineStatsTimeout = 5 * time.Second
+
+// NewEngineClient returns a client for the unix socket at path (unix:// prefix allowed)
+func NewEngineClient(socket string) *EngineClient {
+	return newEngineClient("unix", strings.TrimPrefix(socket, "unix://"))
+}
+
+// newEngineClient dials addr on network ("unix" or "tcp") for every request
+func newEngineClient(network, addr string) *EngineClient {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+		MaxIdleConns:        engineStatsParallelism,
+		MaxIdleConnsPerHost: engineStatsParallelism,
+		IdleConnTimeout:     30 * time.Second,
+	}
+
+	return &EngineClient{
+		name:    "docker",
+		network: network,
+		socket:  addr,
+		http:    &http.Client{Transport: transport},
+		lastCPU: map[string]cpuSample{},
+	}
+}
+
+// Name returns the CLI binary matching this socket, used for interactive exec
+func (c *EngineClient) Name() string {
+	return c.name
+}
+
+// Socket returns the socket path this client dials
+func (c *EngineClient) Socket() string {
+	return c.socket
+}
+
+// cliEnv points the CLI commands we start (compose, exec) at the same daemon.
+// a local podman is left alone, CONTAINER_HOST would switch podman to remote mode.
+func (c *EngineClient) cliEnv() []string {
+	switch {
+	case c.name == "docker":
+		return []string{"DOCKER_HOST=" + c.network + "://" + c.socket}
+	case c.network == "tcp":
+		return []string{"CONTAINER_HOST=tcp://" + c.socket}
+	}
+	return nil
+}
+
+// ExecCommand is `exec -it` through the matching CLI
+func (c *EngineClient) ExecCommand(containerID string, cmd ...string) *exec.Cmd {
+	return cliCommand(c.name, c.cliEnv(), append([]string{"exec", "-it", containerID}, cmd...)...)
+}
+
+// the host part is ignored by our dialer, it just has to be a valid URL
+func (c *EngineClient) url(path string, query url.Values) string {
+	u := "http://docker" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends a request and returns the response if the status is 2xx.
+// engine errors come back as {"message": "..."} so we surface that text.
+func (c *EngineClient) do(ctx context.Context, method, path string, query url.Values) (*http.Response, error) {
+	return c.doBody(ctx, method, path, query, nil)
+}
+
+// doBody is do with a JSON request body, body is marshalled when not nil
+func (c *EngineClient) doBody(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path, query), reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	// 304 is "already started/stopped", not worth an error
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(errBody, &apiErr) == nil && apiErr.Message != "" {
+		return nil, fmt.Errorf("%s %s: %s", method, path, apiErr.Message)
+	}
+	return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+}
+
+// getJSON decodes the response body of a GET into out
+func (c *EngineClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	resp, err := c.do(ctx, http.MethodGet, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Ping checks that something API-shaped is listening on the socket
+func (c *EngineClient) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, err := c.do(ctx, http.MethodGet, "/_ping", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ============================================================================
+// Containers
+// ============================================================================
+
+// what /containers/json gives us per container
+type engineContainer struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+	Mounts []struct {
+		Type string `json:"Type"`
+		Name string `json:"Name"`
+	} `json:"Mounts"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress         string `json:"IPAddress"`
+			IPPrefixLen       int    `json:"IPPrefixLen"`
+			GlobalIPv6Address string `json:"GlobalIPv6Address"`
+			MacAddress        string `json:"MacAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// docker ps shows 12 chars, keep the same so IDs look the same on every backend
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// formatEnginePorts renders ports the same way `docker ps` does
+func formatEnginePorts(e engineContainer) string {
+	var parts []string
+	for _, p := range e.Ports {
+		if p.PublicPort > 0 {
+			ip := p.IP
+			if strings.Contains(ip, ":") {
+				ip = "[" + ip + "]"
+			}
+			parts = append(parts, fmt.Sprintf("%s:%d->%d/%s", ip, p.PublicPort, p.PrivatePort, p.Type))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d/%s", p.PrivatePort, p.Type))
+		}
+	}
+	// the API doesn't promise any order
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// engineIP is the address on the first network by name, so it doesn't jump
+// around between refreshes
+func engineIP(e engineContainer) string {
+	names := make([]string, 0, len(e.NetworkSettings.Networks))
+	for name, n := range e.NetworkSettings.Networks {
+		if n.IPAddress != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return e.NetworkSettings.Networks[names[0]].IPAddress
+}
+
+func (e engineContainer) toContainer() Container {
+	names := make([]string, 0, len(e.Names))
+	for _, n := range e.Names {
+		names = append(names, strings.TrimPrefix(n, "/"))
+	}
+
+	labels := e.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	var created time.Time
+	if e.Created > 0 {
+		created = time.Unix(e.Created, 0)
+	}
+
+	return Container{
+		ID:                   shortID(e.ID),
+		Names:                names,
+		Image:                e.Image,
+		ImageID:              shortID(e.ImageID),
+		Status:               e.Status,
+		State:                strings.ToLower(e.State),
+		Health:               ParseHealth(e.Status),
+		Ports:                formatEnginePorts(e),
+		Created:              created,
+		IPAddress:            engineIP(e),
+		Labels:               labels,
+		ComposeProject:       composeProjectName(labels),
+		ComposeService:       labels["com.docker.compose.service"],
+		ComposeNumber:        labels["com.docker.compose.container-number"],
+		ComposeDirectory:     labels["com.docker.compose.project.working_dir"],
+		ComposeFileDirectory: labels["com.docker.compose.project.config_files"],
+	}
+}
+
+// listEngineContainers fetches all containers (running + stopped) without stats
+func (c *EngineClient) listEngineContainers(ctx context.Context) ([]Container, error) {
+	var entries []engineContainer
+	if err := c.getJSON(ctx, "/containers/json", url.Values{"all": {"1"}}, &entries); err != nil {
+		return nil, err
+	}
+
+	out := make([]Container, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.toContainer())
+	}
+	return out, nil
+}
+
+// ListContainers returns every container, without stats. a stats call per
+// running container takes a second or two, GetAllContainerStats is separate
+func (c *EngineClient) ListContainers() ([]Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	containers, err := c.listEngineContainers(ctx)
+	if err == nil {
+		c.forgetCPU(containers)
+	}
+	return containers, err
+}
+
+// FetchComposeProjects groups compose-managed containers by project
+func (c *EngineClient) FetchComposeProjects() (map[string]*ComposeProject, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	all, err := c.listEngineContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var composed []Container
+	for _, ct := range all {
+		if ct.ComposeProject != "" {
+			composed = append(composed, ct)
+		}
+	}
+	return groupComposeProjects(composed), nil
+}
+
+// DoAction runs a lifecycle action (start, stop, kill, rename...) against a container.
+// podman's compat API takes the same endpoints and parameters.
+func (c *EngineClient) DoAction(action ContainerAction, containerID string) error {
+	if err := action.Validate(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout(action))
+	defer cancel()
+
+	id := url.PathEscape(containerID)
+	query := url.Values{}
+
+	var resp *http.Response
+	var err error
+	switch action.Kind {
+	case "rm", "remove":
+		resp, err = c.do(ctx, http.MethodDelete, "/containers/"+id, nil)
+	default:
+		switch {
+		case action.Timeout != nil:
+			query.Set("t", strconv.Itoa(*action.Timeout))
+		case action.Signal != "":
+			query.Set("signal", action.Signal)
+		case action.Kind == "rename":
+			query.Set("name", action.Name)
+		}
+		resp, err = c.do(ctx, http.MethodPost, "/containers/"+id+"/"+action.Kind, query)
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *EngineClient) RemoveContainer(containerID string, opts RemoveOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := url.Values{}
+	if opts.Force {
+		query.Set("force", "1")
+	}
+	if opts.Volumes {
+		query.Set("v", "1")
+	}
+	resp, err := c.do(ctx, http.MethodDelete, "/containers/"+url.PathEscape(containerID), query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Inspect returns the full inspect document for a container
+func (c *EngineClient) Inspect(containerID string) (*ContainerInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var raw inspectJSON
+	if err := c.getJSON(ctx, "/containers/"+url.PathEscape(containerID)+"/json", nil, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toInspect(), nil
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+// the bits of /containers/{id}/stats we actually use
+type engineStats struct {
+	ID       string `json:"id"`
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PreCPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current uint64 `json:"current"`
+	} `json:"pids_stats"`
+}
+
+// cpuPercent uses the same formula as `docker stats`
+func (s engineStats) cpuPercent() float64 {
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+
+	online := float64(s.CPUStats.OnlineCPUs)
+	if online == 0 {
+		online = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if systemDelta > 0 && cpuDelta > 0 {
+		return (cpuDelta / systemDelta) * online * 100
+	}
+	return 0
+}
+
+// memUsed subtracts page cache like the CLI does (cgroup v1 and v2 name it differently)
+func (s engineStats) memUsed() uint64 {
+	used := s.MemoryStats.Usage
+	if v, ok := s.MemoryStats.Stats["total_inactive_file"]; ok && v < used {
+		used -= v
+	} else if v, ok := s.MemoryStats.Stats["inactive_file"]; ok && v < used {
+		used -= v
+	}
+	return used
+}
+
+func (s engineStats) netIO() (rx, tx uint64) {
+	for _, n := range s.Networks {
+		rx += n.RxBytes
+		tx += n.TxBytes
+	}
+	return rx, tx
+}
+
+func (s engineStats) blockIO() (read, write uint64) {
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += e.Value
+		case "write":
+			write += e.Value
+		}
+	}
+	return read, write
+}
+
+// humanSize mirrors docker's units.HumanSizeWithPrecision(size, 3) so
+// the strings look identical to what the CLI prints
+func humanSize(size float64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for size >= 1000 && i < len(units)-1 {
+		size /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.3g%s", size, units[i])
+}
+
+func (s engineStats) toContainerStats(id string) ContainerStats {
+	rx, tx := s.netIO()
+	read, write := s.blockIO()
+	return ContainerStats{
+		ID:         id,
+		CPU:        s.cpuPercent() / 100,
+		MemUsed:    s.memUsed(),
+		MemLimit:   s.MemoryStats.Limit,
+		NetRx:      rx,
+		NetTx:      tx,
+		BlockRead:  read,
+		BlockWrite: write,
+		PIDs:       s.PidsStats.Current,
+	}
+}
+
+// cpuSample is the cpu counters of one stats call
+type cpuSample struct {
+	total, system uint64
+}
+
+// containerStats grabs a single sample for one container. one-shot=true
+// answers right away instead of waiting a second for a second sample, so
+// precpu_stats comes back empty and the previous call's counters stand in.
+// the first sample of a container averages over its lifetime
+func (c *EngineClient) containerStats(ctx context.Context, id string) (ContainerStats, error) {
+	var s engineStats
+	err := c.getJSON(ctx, "/containers/"+url.PathEscape(id)+"/stats", url.Values{"stream": {"false"}, "one-shot": {"true"}}, &s)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+
+	c.cpuMu.Lock()
+	if s.PreCPUStats.SystemUsage == 0 {
+		prev := c.lastCPU[id]
+		s.PreCPUStats.CPUUsage.TotalUsage = prev.total
+		s.PreCPUStats.SystemUsage = prev.system
+	}
+	c.lastCPU[id] = cpuSample{s.CPUStats.CPUUsage.TotalUsage, s.CPUStats.SystemUsage}
+	c.cpuMu.Unlock()
+
+	return s.toContainerStats(id), nil
+}
+
+// forgetCPU drops the cpu samples of containers that aren't running any more
+func (c *EngineClient) forgetCPU(containers []Container) {
+	running := make(map[string]bool, len(containers))
+	for _, ct := range containers {
+		running[ct.ID] = ct.State == "running"
+	}
+	c.cpuMu.Lock()
+	defer c.cpuMu.Unlock()
+	for id := range c.lastCPU {
+		if !running[id] {
+			delete(c.lastCPU, id)
+		}
+	}
+}
+
+// GetAllContainerStats fetches stats for multiple containers concurrently.
+// the engine has no batch endpoint so we fan out with a small worker limit,
+// every call with its own timeout so a long queue doesn't run out of time.
+// when some fail the rest come back with a *StatsError naming the missing ones
+func (c *EngineClient) GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
+	if len(containerIDs) == 0 {
+		return nil, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failed   = map[string]error{}
+		sem      = make(chan struct{}, engineStatsParallelism)
+		statsMap = make(map[string]ContainerStats, len(containerIDs))
+	)
+
+	for _, id := range containerIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), engineStatsTimeout)
+			defer cancel()
+			s, err := c.containerStats(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[id] = err
+				return
+			}
+			statsMap[id] = s
+		}(id)
+	}
+	wg.Wait()
+
+	return statsResult(statsMap, failed)
+}
+
+// ============================================================================
+// Logs
+// ============================================================================
+
+// GetLogs returns the last 100 lines of a container's stdout and stderr
+func (c *EngineClient) GetLogs(containerID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := url.Values{
+		"stdout": {"1"},
+		"stderr": {"1"},
+		"tail":   {"100"},
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(containerID)+"/logs", query)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	scanner := bufio.NewScanner(bytes.NewReader(demuxLogs(body)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, scanner.Err()
+}
+
+// demuxLogs strips the 8 byte frame headers docker adds when a container has no TTY.
+// TTY containers send a raw stream, which we return untouched.
+func demuxLogs(b []byte) []byte {
+	if !isMultiplexed(b) {
+		return b
+	}
+
+	var out bytes.Buffer
+	for len(b) >= 8 {
+		size := int(binary.BigEndian.Uint32(b[4:8]))
+		b = b[8:]
+		if size > len(b) {
+			size = len(b)
+		}
+		out.Write(b[:size])
+		b = b[size:]
+	}
+	return out.Bytes()
+}
+
+// frame header is [stream, 0, 0, 0, size(4 bytes)] with stream being 0, 1 or 2
+func isMultiplexed(b []byte) bool {
+	if len(b) < 8 {
+		return false
+	}
+	return b[0] <= 2 && b[1] == 0 && b[2] == 0 && b[3] == 0
+}
+
+// ============================================================================
+// Socket discovery
+// ============================================================================
+
+// DefaultSocketPath picks the socket for a runtime when the config doesn't set one.
+// honours DOCKER_HOST / CONTAINER_HOST when they point at a unix socket.
+func DefaultSocketPath(runtime string) string {
+	if runtime == "podman" {
+		if host := os.Getenv("CONTAINER_HOST"); strings.HasPrefix(host, "unix://") {
+			return strings.TrimPrefix(host, "unix://")
+		}
+		// rootless podman lives under the user's runtime dir
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			rootless := filepath.Join(dir, "podman", "podman.sock")
+			if _, err := os.Stat(rootless); err == nil {
+				return rootless
+			}
+		}
+		return "/run/podman/podman.sock"
+	}
+
+	if host := os.Getenv("DOCKER_HOST"); strings.HasPrefix(host, "unix://") {
+		return strings.TrimPrefix(host, "unix://")
+	}
+	return "/var/run/docker.sock"
+}
+
+// socketPath returns the socket we should try for the given config
+func socketPath(cfg *config.Config) string {
+	if s := strings.TrimSpace(cfg.Runtime.Socket); s != "" {
+		return strings.TrimPrefix(s, "unix://")
+	}
+	return DefaultSocketPath(strings.TrimSpace(strings.ToLower(cfg.Runtime.Type)))
+}
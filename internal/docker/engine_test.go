@@ -0,0 +1,680 @@
+package docker
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSocketServer serves handler on a unix socket in a temp dir and returns its path
+func newSocketServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	// unix socket paths have a ~100 char limit, t.TempDir() can be too long on macOS
+	dir, err := os.MkdirTemp("", "dm")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "engine.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return socket
+}
+
+const containersJSON = `[
+  {
+    "Id": "4f1c2d3e4b5a69788796a5b4c3d2e1f0aabbccddeeff00112233445566778899",
+    "Names": ["/web"],
+    "Image": "nginx:latest",
+    "State": "running",
+    "Status": "Up 5 minutes (unhealthy)",
+    "Created": 1714557600,
+    "NetworkSettings": {"Networks": {"shop_default": {"IPAddress": "172.18.0.2"}, "bridge": {"IPAddress": ""}}},
+    "Labels": {
+      "com.docker.compose.project": "shop",
+      "com.docker.compose.service": "web",
+      "com.docker.compose.project.working_dir": "/srv/shop",
+      "com.docker.compose.project.config_files": "/srv/shop/compose.yml"
+    },
+    "Ports": [{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"}]
+  },
+  {
+    "Id": "aa11bb22cc33dd44",
+    "Names": ["/old-job"],
+    "Image": "busybox",
+    "State": "exited",
+    "Status": "Exited (0) 2 hours ago",
+    "Labels": {},
+    "Ports": []
+  }
+]`
+
+const statsJSON = `{
+  "cpu_stats": {"cpu_usage": {"total_usage": 300}, "system_cpu_usage": 2000, "online_cpus": 2},
+  "precpu_stats": {"cpu_usage": {"total_usage": 100}, "system_cpu_usage": 1000},
+  "memory_stats": {"usage": 600, "limit": 1000, "stats": {"inactive_file": 100}},
+  "networks": {"eth0": {"rx_bytes": 1500, "tx_bytes": 500}},
+  "blkio_stats": {"io_service_bytes_recursive": [{"op": "Read", "value": 2000000}, {"op": "Write", "value": 0}]},
+  "pids_stats": {"current": 7}
+}`
+
+func engineMux(t *testing.T) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("all"))
+		w.Write([]byte(containersJSON))
+	})
+	mux.HandleFunc("GET /containers/{id}/stats", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "4f1c2d3e4b5a", r.PathValue("id"))
+		w.Write([]byte(statsJSON))
+	})
+	return mux
+}
+
+func TestEngineListContainers(t *testing.T) {
+	c := NewEngineClient("unix://" + newSocketServer(t, engineMux(t)))
+	require.NoError(t, c.Ping())
+
+	containers, err := c.ListContainers()
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+
+	web := containers[0]
+	assert.Equal(t, "4f1c2d3e4b5a", web.ID)
+	assert.Equal(t, []string{"web"}, web.Names)
+	assert.Equal(t, "running", web.State)
+	assert.Equal(t, HealthUnhealthy, web.Health)
+	assert.Equal(t, "0.0.0.0:8080->80/tcp", web.Ports)
+	assert.Equal(t, "shop", web.ComposeProject)
+	assert.Equal(t, "web", web.ComposeService)
+	assert.Equal(t, time.Unix(1714557600, 0), web.Created)
+	assert.Equal(t, "172.18.0.2", web.IPAddress)
+
+	// the list doesn't wait on a stats call per container
+	assert.Nil(t, web.Stats)
+	assert.Equal(t, "exited", containers[1].State)
+	assert.True(t, containers[1].Created.IsZero())
+	assert.Equal(t, HealthNone, containers[1].Health)
+	assert.Empty(t, containers[1].IPAddress)
+}
+
+func TestEngineContainerStats(t *testing.T) {
+	c := NewEngineClient("unix://" + newSocketServer(t, engineMux(t)))
+	require.NoError(t, c.Ping())
+
+	all, err := c.GetAllContainerStats([]string{"4f1c2d3e4b5a"})
+	require.NoError(t, err)
+	stats, ok := all["4f1c2d3e4b5a"]
+	require.True(t, ok)
+
+	// (200/1000) * 2 cpus, 600 used minus 100 page cache
+	assert.InDelta(t, 0.4, stats.CPU, 1e-9)
+	assert.Equal(t, uint64(500), stats.MemUsed)
+	assert.Equal(t, uint64(1000), stats.MemLimit)
+	assert.Equal(t, 50.0, stats.MemPercent())
+	assert.Equal(t, [2]uint64{1500, 500}, [2]uint64{stats.NetRx, stats.NetTx})
+	assert.Equal(t, [2]uint64{2000000, 0}, [2]uint64{stats.BlockRead, stats.BlockWrite})
+	assert.Equal(t, uint64(7), stats.PIDs)
+}
+
+func TestEngineStatsOneShot(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("OK")) })
+	mux.HandleFunc("GET /containers/{id}/stats", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("one-shot"))
+		if r.PathValue("id") == "stuck" {
+			http.Error(w, `{"message": "timed out"}`, http.StatusInternalServerError)
+			return
+		}
+		// no precpu_stats on a one-shot sample
+		calls++
+		fmt.Fprintf(w, `{"cpu_stats": {"cpu_usage": {"total_usage": %d}, "system_cpu_usage": %d, "online_cpus": 2}}`,
+			calls*calls*100, calls*1000)
+	})
+	c := NewEngineClient("unix://" + newSocketServer(t, mux))
+
+	all, err := c.GetAllContainerStats([]string{"web", "stuck"})
+	var partial *StatsError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, []string{"stuck"}, slices.Collect(maps.Keys(partial.Failed)))
+	assert.Contains(t, err.Error(), "no stats for 1 containers, stuck:")
+	// the first sample averages over the container's lifetime: 100 of 1000 on 2 cpus
+	assert.InDelta(t, 0.2, all["web"].CPU, 1e-9)
+
+	// the next one is against the previous call: 400-100 of 2000-1000
+	all, err = c.GetAllContainerStats([]string{"web"})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.6, all["web"].CPU, 1e-9)
+
+	// every call failing is a plain error
+	_, err = c.GetAllContainerStats([]string{"stuck"})
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &partial))
+}
+
+func TestParseStatsLines(t *testing.T) {
+	// docker prints memory in IEC units and io in SI units, podman uses SI for both
+	out := []byte(`{"ID":"4f1c2d3e4b5a","CPUPerc":"150.25%","MemUsage":"12.5MiB / 1GiB","NetIO":"1.2kB / 648B","BlockIO":"2MB / 0B","PIDs":"12"}
+{"ID":"9a8b7c","CPUPerc":"0.00%","MemUsage":"20MB / 8GB","NetIO":"0B / 0B","BlockIO":"--","PIDs":"1"}
+not json
+`)
+	stats := parseStatsLines(out, func(id string) string { return id })
+	require.Len(t, stats, 2)
+
+	web := stats["4f1c2d3e4b5a"]
+	assert.InDelta(t, 1.5025, web.CPU, 1e-9)
+	assert.Equal(t, uint64(12.5*(1<<20)), web.MemUsed)
+	assert.Equal(t, uint64(1<<30), web.MemLimit)
+	assert.Equal(t, uint64(1200), web.NetRx)
+	assert.Equal(t, uint64(648), web.NetTx)
+	assert.Equal(t, uint64(2000000), web.BlockRead)
+	assert.Equal(t, uint64(12), web.PIDs)
+
+	podman := stats["9a8b7c"]
+	assert.Equal(t, uint64(20000000), podman.MemUsed)
+	assert.Equal(t, uint64(8000000000), podman.MemLimit)
+	assert.Zero(t, podman.BlockRead)
+}
+
+func TestEngineFetchComposeProjects(t *testing.T) {
+	c := NewEngineClient(newSocketServer(t, engineMux(t)))
+
+	projects, err := c.FetchComposeProjects()
+	require.NoError(t, err)
+	require.Contains(t, projects, "shop")
+
+	shop := projects["shop"]
+	assert.Equal(t, AllRunning, shop.Status)
+	assert.Equal(t, "/srv/shop", shop.WorkingDir)
+	assert.Len(t, shop.Containers, 1)
+}
+
+func TestEngineDoAction(t *testing.T) {
+	var got []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.PathValue("action")+" "+r.PathValue("id")+" "+r.URL.RawQuery)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /containers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, "rm "+r.PathValue("id"))
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message": "container is running"}`))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	timeout := 30
+	require.NoError(t, c.DoAction(Action("stop"), "abc"))
+	require.NoError(t, c.DoAction(Action("start"), "abc"))
+	require.NoError(t, c.DoAction(ContainerAction{Kind: "stop", Timeout: &timeout}, "abc"))
+	require.NoError(t, c.DoAction(ContainerAction{Kind: "kill", Signal: "SIGHUP"}, "abc"))
+	require.NoError(t, c.DoAction(ContainerAction{Kind: "rename", Name: "web-old"}, "abc"))
+
+	err := c.DoAction(Action("rm"), "abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "container is running")
+
+	// bad names never reach the daemon
+	require.Error(t, c.DoAction(ContainerAction{Kind: "rename", Name: "no spaces"}, "abc"))
+
+	assert.Equal(t, []string{"stop abc ", "start abc ", "stop abc t=30", "kill abc signal=SIGHUP", "rename abc name=web-old", "rm abc"}, got)
+}
+
+func TestContainerActionCLIArgs(t *testing.T) {
+	timeout := 5
+	stop := ContainerAction{Kind: "stop", Timeout: &timeout}
+	assert.Equal(t, []string{"stop", "-t", "5", "abc"}, stop.cliArgs("docker", "abc"))
+	assert.Equal(t, []string{"stop", "--time", "5", "abc"}, stop.cliArgs("podman", "abc"))
+	assert.Equal(t, []string{"kill", "--signal", "SIGUSR1", "abc"}, ContainerAction{Kind: "kill", Signal: "SIGUSR1"}.cliArgs("podman", "abc"))
+	assert.Equal(t, []string{"rename", "abc", "web-2"}, ContainerAction{Kind: "rename", Name: "web-2"}.cliArgs("docker", "abc"))
+	assert.Equal(t, []string{"rm", "abc"}, Action("remove").cliArgs("docker", "abc"))
+	assert.Equal(t, "stop -t 5", stop.String())
+}
+
+// frame builds one multiplexed log frame
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestEngineGetLogs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "100", r.URL.Query().Get("tail"))
+		w.Write(frame(1, "hello\n"))
+		w.Write(frame(2, "oops\n\n"))
+		w.Write(frame(1, "bye\n"))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	lines, err := c.GetLogs("abc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello", "oops", "bye"}, lines)
+}
+
+func TestEngineEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("filters"), "container")
+		w.Write([]byte(`{"Type":"container","Action":"die","Actor":{"ID":"a1b2c3d4e5f6a1b2c3d4","Attributes":{"name":"web","exitCode":"137","com.docker.compose.project":"shop"}},"timeNano":1700000000000000000}
+{"Type":"network","Action":"connect","Actor":{"ID":"net1"}}
+{"Type":"container","Action":"health_status: unhealthy","Actor":{"ID":"0f9e8d7c6b5a","Attributes":{"name":"db"}}}
+`))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	ch, err := c.Events(context.Background())
+	require.NoError(t, err)
+
+	var got []Event
+	for ev := range ch {
+		got = append(got, ev)
+	}
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "die", got[0].Action)
+	assert.Equal(t, "a1b2c3d4e5f6", got[0].ContainerID)
+	assert.Equal(t, "web", got[0].Name)
+	assert.Equal(t, "137", got[0].ExitCode)
+	assert.Equal(t, map[string]string{"com.docker.compose.project": "shop"}, got[0].Labels)
+
+	assert.Equal(t, "health_status", got[1].Action)
+	assert.Equal(t, HealthUnhealthy, got[1].Health)
+}
+
+func TestPodmanEvents(t *testing.T) {
+	parse := func(line string) (Event, bool) {
+		var raw podmanEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &raw))
+		return raw.toEvent()
+	}
+
+	ev, ok := parse(`{"ID":"9a8b7c6d5e4f","Image":"docker.io/library/redis:7","Name":"cache","Status":"died","Time":"2024-05-01T10:00:00.5Z","Type":"container","ContainerExitCode":137,"Attributes":{"com.docker.compose.project":"shop"}}`)
+	require.True(t, ok)
+	assert.Equal(t, Event{
+		Action:      "die",
+		ContainerID: "9a8b7c6d5e4f",
+		Name:        "cache",
+		Image:       "docker.io/library/redis:7",
+		ExitCode:    "137",
+		Labels:      map[string]string{"com.docker.compose.project": "shop"},
+		Time:        time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC),
+	}, ev)
+
+	ev, _ = parse(`{"ID":"9a8b7c6d5e4f","Status":"remove","Type":"container"}`)
+	assert.Equal(t, "destroy", ev.Action)
+	ev, _ = parse(`{"ID":"9a8b7c6d5e4f","Status":"cleanup","Type":"container"}`)
+	assert.Equal(t, "cleanup", ev.Action)
+	ev, _ = parse(`{"ID":"9a8b7c6d5e4f","Status":"health_status","health_status":"unhealthy","Type":"container"}`)
+	assert.Equal(t, "health_status", ev.Action)
+	assert.Equal(t, HealthUnhealthy, ev.Health)
+	ev, _ = parse(`{"ID":"9a8b7c6d5e4f","Status":"start","Type":"container"}`)
+	assert.Equal(t, "start", ev.Action)
+
+	_, ok = parse(`{"ID":"abc","Status":"pull","Type":"image"}`)
+	assert.False(t, ok)
+}
+
+func TestEngineStreamLogs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "1", q.Get("follow"))
+		assert.Equal(t, "1", q.Get("timestamps"))
+		assert.Equal(t, "50", q.Get("tail"))
+		// a line split across two frames
+		w.Write(frame(1, "2024-05-01T10:00:00.5Z hel"))
+		w.Write(frame(1, "lo\n"))
+		w.Write(frame(2, "2024-05-01T10:00:01Z boom\n"))
+		// the last line has no newline, it still comes out at the end
+		w.Write(frame(1, "2024-05-01T10:00:02Z bye"))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	ch, err := c.StreamLogs(context.Background(), "abc", LogOptions{Follow: true, Tail: 50})
+	require.NoError(t, err)
+
+	var got []LogLine
+	for l := range ch {
+		got = append(got, l)
+	}
+	require.Len(t, got, 3)
+	assert.Equal(t, LogLine{Time: time.Date(2024, 5, 1, 10, 0, 0, 5e8, time.UTC), Stream: "stdout", Text: "hello"}, got[0])
+	assert.Equal(t, "stderr", got[1].Stream)
+	assert.Equal(t, "boom", got[1].Text)
+	assert.Equal(t, "bye", got[2].Text)
+}
+
+func TestEngineStreamLogsLargeFrames(t *testing.T) {
+	// a frame past the line limit made of short lines is fine, one line past it is not
+	short := strings.Repeat(strings.Repeat("x", 99)+"\n", maxLogLine/100+10)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(frame(1, short))
+		w.Write(frame(1, strings.Repeat("y", maxLogLine+1)+"\n"))
+		w.Write(frame(1, "never\n"))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	ch, err := c.StreamLogs(context.Background(), "abc", LogOptions{})
+	require.NoError(t, err)
+
+	var got []LogLine
+	for l := range ch {
+		got = append(got, l)
+	}
+	require.Len(t, got, maxLogLine/100+11)
+	assert.Equal(t, strings.Repeat("x", 99), got[0].Text)
+	last := got[len(got)-1]
+	assert.Equal(t, "stderr", last.Stream)
+	assert.Contains(t, last.Text, "log stream stopped")
+}
+
+func TestEngineStreamLogsFrameSize(t *testing.T) {
+	// the header claims 4GiB and the body ends early, nothing that size gets allocated
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		hdr := []byte{1, 0, 0, 0, 0xff, 0xff, 0xff, 0xff}
+		w.Write(append(hdr, "2024-05-01T10:00:00Z cut\nshort"...))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	ch, err := c.StreamLogs(context.Background(), "abc", LogOptions{})
+	require.NoError(t, err)
+
+	var got []string
+	for l := range ch {
+		got = append(got, l.Text)
+	}
+	assert.Equal(t, []string{"cut", "short"}, got)
+}
+
+func TestEngineStreamLogsRawStream(t *testing.T) {
+	// a TTY container, one line past the scanner's default 64k and one past the limit
+	long := strings.Repeat("x", 100*1024)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("2024-05-01T10:00:00Z " + long + "\n"))
+		w.Write([]byte(strings.Repeat("y", maxLogLine+1) + "\n"))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	ch, err := c.StreamLogs(context.Background(), "abc", LogOptions{})
+	require.NoError(t, err)
+
+	var got []LogLine
+	for l := range ch {
+		got = append(got, l)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, long, got[0].Text)
+	assert.Equal(t, "stderr", got[1].Stream)
+	assert.Contains(t, got[1].Text, "log stream stopped")
+}
+
+func TestEngineInspect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"Id": "a1b2c3d4e5f6a1b2c3d4", "Name": "/web", "RestartCount": 2,
+			"State": {"Status": "running", "Pid": 42, "StartedAt": "2024-05-01T10:00:00Z", "FinishedAt": "0001-01-01T00:00:00Z",
+				"Health": {"Status": "unhealthy", "FailingStreak": 3, "Log": [
+					{"Start": "2024-05-01T10:00:30Z", "End": "2024-05-01T10:00:31Z", "ExitCode": 0, "Output": "ok"},
+					{"Start": "2024-05-01T10:01:00Z", "End": "2024-05-01T10:01:01Z", "ExitCode": 1, "Output": "curl: (7) refused\n"}]}},
+			"Config": {"Image": "nginx", "Env": ["A=1"], "Entrypoint": ["/docker-entrypoint.sh"], "Cmd": ["nginx"],
+				"Healthcheck": {"Test": ["CMD", "curl", "-f", "localhost"], "Interval": 30000000000, "Retries": 3}},
+			"HostConfig": {"RestartPolicy": {"Name": "on-failure", "MaximumRetryCount": 5}},
+			"Mounts": [{"Type": "bind", "Source": "/srv", "Destination": "/usr/share/nginx/html", "RW": false}],
+			"NetworkSettings": {"Networks": {
+				"z_net": {"IPAddress": "10.0.0.2", "IPPrefixLen": 24},
+				"a_net": {"IPAddress": "172.18.0.2", "IPPrefixLen": 16, "Gateway": "172.18.0.1", "Aliases": ["web"]}}}
+		}`))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	in, err := c.Inspect("web")
+	require.NoError(t, err)
+	assert.Equal(t, "web", in.Name)
+	assert.Equal(t, 42, in.Pid)
+	assert.True(t, in.FinishedAt.IsZero())
+	assert.Equal(t, "on-failure", in.RestartPolicy)
+	assert.Equal(t, 5, in.RestartMaxRetries)
+	assert.Equal(t, []string{"/docker-entrypoint.sh"}, in.Entrypoint)
+	require.NotNil(t, in.Healthcheck)
+	assert.Equal(t, 30*time.Second, in.Healthcheck.Interval)
+	require.NotNil(t, in.Health)
+	assert.Equal(t, "curl: (7) refused", in.Health.LastOutput)
+	assert.Equal(t, HealthUnhealthy, in.Health.Status)
+	assert.Equal(t, 3, in.Health.FailingStreak)
+	require.Len(t, in.Health.Probes, 2)
+	assert.Equal(t, HealthProbe{
+		Start: time.Date(2024, 5, 1, 10, 0, 30, 0, time.UTC), End: time.Date(2024, 5, 1, 10, 0, 31, 0, time.UTC), Output: "ok",
+	}, in.Health.Probes[0])
+	assert.Equal(t, 1, in.Health.Probes[1].ExitCode)
+	assert.Equal(t, []Mount{{Type: "bind", Source: "/srv", Destination: "/usr/share/nginx/html"}}, in.Mounts)
+	require.Len(t, in.Networks, 2)
+	assert.Equal(t, "a_net", in.Networks[0].Name)
+	assert.Equal(t, "172.18.0.2/16", in.Networks[0].IPAddress)
+}
+
+func TestEngineImages(t *testing.T) {
+	mux := engineMux(t)
+	mux.HandleFunc("GET /images/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Id": "sha256:9f8e7d6c5b4a39281706f5e4", "RepoTags": ["nginx:latest", "nginx:1.27"], "Size": 192000000, "Created": 1714557600},
+			{"Id": "sha256:0a0b0c0d0e0f1a1b1c1d", "RepoTags": ["<none>:<none>"], "Size": 64000000, "Created": 1714557600}
+		]`))
+	})
+	mux.HandleFunc("POST /images/prune", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `{"dangling":["true"]}`, r.URL.Query().Get("filters"))
+		w.Write([]byte(`{"ImagesDeleted": [{"Untagged": "x"}, {"Deleted": "sha256:0a0b0c0d0e0f1a1b1c1d"}], "SpaceReclaimed": 64000000}`))
+	})
+	mux.HandleFunc("POST /images/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "redis", r.URL.Query().Get("fromImage"))
+		assert.Equal(t, "latest", r.URL.Query().Get("tag"))
+		w.Write([]byte(`{"status": "Pulling from library/redis", "id": "latest"}
+{"status": "Downloading", "id": "aaaaaaaaaaaa", "progressDetail": {"current": 512, "total": 1024}}
+{"error": "unauthorized"}
+`))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	images, err := c.ListImages()
+	require.NoError(t, err)
+	require.Len(t, images, 3)
+	// one row per tag, sorted, dangling last
+	assert.Equal(t, "nginx:1.27", images[0].Ref())
+	assert.Equal(t, "nginx:latest", images[1].Ref())
+	assert.Equal(t, "9f8e7d6c5b4a", images[1].ID)
+	assert.Equal(t, []string{"web"}, images[1].Containers)
+	assert.Empty(t, images[0].Containers)
+	assert.True(t, images[2].Dangling)
+
+	report, err := c.PruneImages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0a0b0c0d0e0f"}, report.Deleted)
+	assert.Equal(t, int64(64000000), report.SpaceReclaimed)
+
+	ch, err := c.PullImage(context.Background(), "redis")
+	require.NoError(t, err)
+	var got []PullProgress
+	for p := range ch {
+		got = append(got, p)
+	}
+	require.Len(t, got, 3)
+	assert.Equal(t, PullProgress{ID: "aaaaaaaaaaaa", Status: "Downloading", Current: 512, Total: 1024}, got[1])
+	assert.Equal(t, "unauthorized", got[2].Error)
+}
+
+func TestEngineVolumes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /system/df", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "volume", r.URL.Query().Get("type"))
+		w.Write([]byte(`{"Volumes": [
+			{"Name": "shop_data", "Driver": "local", "Mountpoint": "/var/lib/docker/volumes/shop_data/_data", "UsageData": {"Size": 2048, "RefCount": 2}},
+			{"Name": "cache", "Driver": "local", "UsageData": {"Size": -1, "RefCount": 0}}
+		]}`))
+	})
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Id": "a1b2c3d4e5f6a1b2", "Names": ["/db"], "State": "running", "Mounts": [{"Type": "volume", "Name": "shop_data"}, {"Type": "bind", "Name": ""}]},
+			{"Id": "0f9e8d7c6b5a0f9e", "Names": ["/backup"], "State": "exited", "Mounts": [{"Type": "volume", "Name": "shop_data"}]}
+		]`))
+	})
+	mux.HandleFunc("POST /volumes/prune", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `{"all":["true"]}`, r.URL.Query().Get("filters"))
+		w.Write([]byte(`{"VolumesDeleted": ["cache"], "SpaceReclaimed": 0}`))
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	volumes, err := c.ListVolumes()
+	require.NoError(t, err)
+	require.Len(t, volumes, 2)
+	assert.Equal(t, "cache", volumes[0].Name)
+	assert.Equal(t, int64(-1), volumes[0].Size)
+	assert.False(t, volumes[0].InUse())
+	assert.Equal(t, int64(2048), volumes[1].Size)
+	assert.Equal(t, []VolumeUser{
+		{ID: "a1b2c3d4e5f6", Name: "db", State: "running"},
+		{ID: "0f9e8d7c6b5a", Name: "backup", State: "exited"},
+	}, volumes[1].Containers)
+
+	report, err := c.PruneVolumes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache"}, report.Deleted)
+}
+
+func TestParseVolumeCLIOutput(t *testing.T) {
+	df := `Images space usage:
+
+REPOSITORY   TAG       IMAGE ID       CREATED       SIZE      SHARED SIZE   UNIQUE SIZE   CONTAINERS
+nginx        latest    a1b2c3d4e5f6   2 weeks ago   192MB     0B            192MB         1
+
+Local Volumes space usage:
+
+VOLUME NAME   LINKS     SIZE
+shop_data     2         12.5MB
+cache         0         0B
+
+Build cache usage: 0B
+`
+	assert.Equal(t, map[string]int64{"shop_data": 12500000, "cache": 0}, parseDfVolumeSizes(df))
+
+	report := parseVolumePruneOutput("Deleted Volumes:\ncache\nold_logs\n\nTotal reclaimed space: 1.5kB\n")
+	assert.Equal(t, []string{"cache", "old_logs"}, report.Deleted)
+	assert.Equal(t, int64(1500), report.SpaceReclaimed)
+}
+
+func TestEngineNetworks(t *testing.T) {
+	var connected map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /networks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Id": "9a8b7c6d5e4f3a2b", "Name": "bridge", "Driver": "bridge", "Scope": "local", "IPAM": {"Config": [{"Subnet": "172.17.0.0/16", "Gateway": "172.17.0.1"}]}},
+			{"Id": "1f2e3d4c5b6a7980", "Name": "shop_default", "Driver": "bridge", "Scope": "local", "IPAM": {"Config": [{"Subnet": "172.18.0.0/16", "Gateway": "172.18.0.1"}]}}
+		]`))
+	})
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Id": "a1b2c3d4e5f6a1b2", "Names": ["/web"], "State": "running", "NetworkSettings": {"Networks": {
+				"shop_default": {"IPAddress": "172.18.0.2", "IPPrefixLen": 16, "MacAddress": "02:42:ac:12:00:02"}}}},
+			{"Id": "0f9e8d7c6b5a0f9e", "Names": ["/api"], "State": "exited", "NetworkSettings": {"Networks": {
+				"shop_default": {"IPAddress": "", "IPPrefixLen": 0}}}}
+		]`))
+	})
+	mux.HandleFunc("POST /networks/{id}/connect", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "shop_default", r.PathValue("id"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&connected))
+		w.WriteHeader(http.StatusOK)
+	})
+	c := NewEngineClient(newSocketServer(t, mux))
+
+	networks, err := c.ListNetworks()
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	// user networks before the builtin ones
+	shop := networks[0]
+	assert.Equal(t, "shop_default", shop.Name)
+	assert.Equal(t, "1f2e3d4c5b6a", shop.ID)
+	assert.Equal(t, "172.18.0.0/16", shop.Subnet)
+	assert.Equal(t, "172.18.0.1", shop.Gateway)
+	assert.Equal(t, []NetworkAttachment{
+		{ContainerID: "0f9e8d7c6b5a", Name: "api", State: "exited"},
+		{ContainerID: "a1b2c3d4e5f6", Name: "web", State: "running", IPAddress: "172.18.0.2/16", MacAddress: "02:42:ac:12:00:02"},
+	}, shop.Containers)
+	assert.Empty(t, networks[1].Containers)
+
+	require.NoError(t, c.ConnectNetwork("shop_default", "a1b2c3d4e5f6"))
+	assert.Equal(t, map[string]any{"Container": "a1b2c3d4e5f6"}, connected)
+}
+
+func TestParsePodmanNetworkInspect(t *testing.T) {
+	// netavark output, lowercase keys and top level subnets
+	var raws []networkJSON
+	require.NoError(t, json.Unmarshal([]byte(`[{"name": "podman", "id": "2f259bab93aaaaa1", "driver": "bridge",
+		"subnets": [{"subnet": "10.88.0.0/16", "gateway": "10.88.0.1"}], "internal": false}]`), &raws))
+	require.Len(t, raws, 1)
+	assert.Equal(t, Network{ID: "2f259bab93aa", Name: "podman", Driver: "bridge", Subnet: "10.88.0.0/16", Gateway: "10.88.0.1"}, raws[0].toNetwork())
+}
+
+func TestComposeArgs(t *testing.T) {
+	// docker compose records absolute paths, podman-compose relative ones
+	p := ComposeProject{Name: "shop", WorkingDir: "/srv/shop", ConfigFile: "/srv/shop/compose.yaml,override.yaml"}
+	args, err := composeArgs(p, ComposeRecreate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-p", "shop", "-f", "/srv/shop/compose.yaml", "-f", "/srv/shop/override.yaml",
+		"up", "-d", "--force-recreate"}, args)
+
+	// without a file only the project name is known, enough for stop but not up
+	p.ConfigFile = ""
+	args, err = composeArgs(p, ComposeStop)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-p", "shop", "stop"}, args)
+	_, err = composeArgs(p, ComposeUp)
+	assert.Error(t, err)
+}
+
+func TestDemuxLogsRawStream(t *testing.T) {
+	// TTY containers aren't multiplexed
+	raw := []byte("plain output\nmore\n")
+	assert.Equal(t, raw, demuxLogs(raw))
+}
+
+func TestDefaultSocketPath(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///tmp/custom.sock")
+	assert.Equal(t, "/tmp/custom.sock", DefaultSocketPath("docker"))
+
+	t.Setenv("DOCKER_HOST", "tcp://10.0.0.1:2375")
+	assert.Equal(t, "/var/run/docker.sock", DefaultSocketPath("docker"))
+
+	t.Setenv("CONTAINER_HOST", "unix:///run/user/1000/podman/podman.sock")
+	assert.Equal(t, "/run/user/1000/podman/podman.sock", DefaultSocketPath("podman"))
+}
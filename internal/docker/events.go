@@ -0,0 +1,196 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Event stream
+// ============================================================================
+
+// docker/engine event shape, shared by /events and `docker events --format {{json .}}`
+type engineEvent struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+	TimeNano int64 `json:"timeNano"`
+}
+
+// attributes that are metadata, not labels
+var eventMetaAttributes = map[string]bool{"name": true, "image": true, "exitCode": true, "signal": true}
+
+func (e engineEvent) toEvent() (Event, bool) {
+	if e.Type != "container" || e.Actor.ID == "" {
+		return Event{}, false
+	}
+
+	// "health_status: healthy", "exec_start: sh -c ..." - keep the verb, health goes in its own field
+	action, detail, _ := strings.Cut(e.Action, ":")
+	action = strings.TrimSpace(action)
+
+	attrs := e.Actor.Attributes
+	labels := map[string]string{}
+	for k, v := range attrs {
+		if !eventMetaAttributes[k] {
+			labels[k] = v
+		}
+	}
+
+	ev := Event{
+		Action:      action,
+		ContainerID: shortID(e.Actor.ID),
+		Name:        attrs["name"],
+		Image:       attrs["image"],
+		ExitCode:    attrs["exitCode"],
+		Labels:      labels,
+		Time:        time.Unix(0, e.TimeNano),
+	}
+	if action == "health_status" {
+		ev.Health = Health(strings.TrimSpace(detail))
+	}
+	return ev, true
+}
+
+// podman's `events --format json` uses its own flat shape
+type podmanEvent struct {
+	ID                string            `json:"ID"`
+	Name              string            `json:"Name"`
+	Image             string            `json:"Image"`
+	Status            string            `json:"Status"`
+	Type              string            `json:"Type"`
+	Time              string            `json:"Time"`
+	ContainerExitCode *int              `json:"ContainerExitCode"`
+	HealthStatus      string            `json:"health_status"`
+	Attributes        map[string]string `json:"Attributes"`
+}
+
+// podman statuses that docker names differently. cleanup follows died and
+// has nothing new, it's left as is like exec_died or mount
+var podmanActions = map[string]string{
+	"died":   "die",
+	"remove": "destroy",
+}
+
+func (e podmanEvent) toEvent() (Event, bool) {
+	if e.Type != "container" || e.ID == "" {
+		return Event{}, false
+	}
+
+	action := e.Status
+	if a, ok := podmanActions[action]; ok {
+		action = a
+	}
+	ev := Event{
+		Action:      action,
+		ContainerID: e.ID,
+		Name:        e.Name,
+		Image:       e.Image,
+		Labels:      e.Attributes,
+		Health:      Health(e.HealthStatus),
+		Time:        parseTime(e.Time),
+	}
+	if e.ContainerExitCode != nil {
+		ev.ExitCode = strconv.Itoa(*e.ContainerExitCode)
+	}
+	return ev, true
+}
+
+// Events subscribes to container events on /events until ctx is cancelled.
+// the channel is closed when the stream ends for any reason.
+func (c *EngineClient) Events(ctx context.Context) (<-chan Event, error) {
+	query := url.Values{"filters": {`{"type":["container"]}`}}
+	resp, err := c.do(ctx, http.MethodGet, "/events", query)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var raw engineEvent
+			if err := dec.Decode(&raw); err != nil {
+				return
+			}
+			ev, ok := raw.toEvent()
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// streamEvents runs `<bin> events ...` and parses each line with parse
+func (r cliRuntime) streamEvents(ctx context.Context, args []string, parse func([]byte) (Event, bool)) (<-chan Event, error) {
+	cmd := r.command(ctx, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			ev, ok := parse(scanner.Bytes())
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Events follows `docker events` until ctx is cancelled
+func (r *DockerCLI) Events(ctx context.Context) (<-chan Event, error) {
+	args := []string{"events", "--filter", "type=container", "--format", "{{json .}}"}
+	return r.streamEvents(ctx, args, func(line []byte) (Event, bool) {
+		var raw engineEvent
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return Event{}, false
+		}
+		return raw.toEvent()
+	})
+}
+
+// Events follows `podman events` until ctx is cancelled
+func (r *PodmanCLI) Events(ctx context.Context) (<-chan Event, error) {
+	args := []string{"events", "--filter", "type=container", "--format", "json"}
+	return r.streamEvents(ctx, args, func(line []byte) (Event, bool) {
+		var raw podmanEvent
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return Event{}, false
+		}
+		return raw.toEvent()
+	})
+}
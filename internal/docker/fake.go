@@ -0,0 +1,641 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Fake runtime (tests)
+// ============================================================================
+
+// FakeRuntime is an in-memory Runtime for tests. It holds a scripted container
+// list and reacts to actions the way a daemon would (stop -> exited, rm -> gone),
+// so TUI flows can run headless without docker installed.
+type FakeRuntime struct {
+	mu         sync.Mutex
+	containers []Container
+	stats      map[string]ContainerStats
+	logs       map[string][]LogLine
+	inspects   map[string]*ContainerInspect
+	errs       map[string]error // method name -> error to return
+	calls      []string         // "stop abc123" for every action
+	events     chan Event       // nil until someone subscribes
+	images     []Image
+	volumes    []Volume
+	networks   []Network
+}
+
+func NewFakeRuntime(containers ...Container) *FakeRuntime {
+	f := &FakeRuntime{
+		stats:    map[string]ContainerStats{},
+		logs:     map[string][]LogLine{},
+		inspects: map[string]*ContainerInspect{},
+		errs:     map[string]error{},
+	}
+	f.SetContainers(containers...)
+	return f
+}
+
+func (f *FakeRuntime) Name() string { return "fake" }
+
+// ExecCommand records the exec, the command itself is never run by tests
+func (f *FakeRuntime) ExecCommand(containerID string, cmd ...string) *exec.Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "exec "+containerID)
+	return exec.Command("true")
+}
+
+// SetContainers replaces the container list
+func (f *FakeRuntime) SetContainers(containers ...Container) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers = append([]Container(nil), containers...)
+}
+
+// SetStats scripts the stats returned for a container
+func (f *FakeRuntime) SetStats(s ContainerStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats[s.ID] = s
+}
+
+// SetLogs scripts the log lines returned for a container, all on stdout
+func (f *FakeRuntime) SetLogs(containerID string, lines ...string) {
+	logLines := make([]LogLine, len(lines))
+	for i, l := range lines {
+		logLines[i] = LogLine{Stream: "stdout", Text: l}
+	}
+	f.SetLogLines(containerID, logLines...)
+}
+
+// SetLogLines scripts log lines with their stream and timestamp
+func (f *FakeRuntime) SetLogLines(containerID string, lines ...LogLine) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs[containerID] = lines
+}
+
+// SetInspect scripts the inspect document returned for a container
+func (f *FakeRuntime) SetInspect(containerID string, in *ContainerInspect) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inspects[containerID] = in
+}
+
+// FailWith makes the named method ("ListContainers", "DoAction", ...) return err.
+// pass nil to clear it.
+func (f *FakeRuntime) FailWith(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// Calls returns every action performed so far
+func (f *FakeRuntime) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// snapshot copies the containers, callers must hold f.mu. stats stay out of
+// it like they do on the real backends
+func (f *FakeRuntime) snapshot() []Container {
+	out := make([]Container, len(f.containers))
+	copy(out, f.containers)
+	for i := range out {
+		// like the real backends, health comes out of the status
+		if out[i].Health == HealthNone {
+			out[i].Health = ParseHealth(out[i].Status)
+		}
+	}
+	return out
+}
+
+func (f *FakeRuntime) ListContainers() ([]Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["ListContainers"]; err != nil {
+		return nil, err
+	}
+	return f.snapshot(), nil
+}
+
+func (f *FakeRuntime) GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["GetAllContainerStats"]; err != nil {
+		return nil, err
+	}
+	out := make(map[string]ContainerStats)
+	for _, id := range containerIDs {
+		if s, ok := f.stats[id]; ok {
+			out[id] = s
+		}
+	}
+	return out, nil
+}
+
+func (f *FakeRuntime) GetLogs(containerID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["GetLogs"]; err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, l := range f.logs[containerID] {
+		out = append(out, l.Text)
+	}
+	return out, nil
+}
+
+// StreamLogs replays the scripted lines inside the since/until window. when
+// following, the stream then stays open until ctx is cancelled.
+func (f *FakeRuntime) StreamLogs(ctx context.Context, containerID string, opts LogOptions) (<-chan LogLine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["StreamLogs"]; err != nil {
+		return nil, err
+	}
+
+	var lines []LogLine
+	for _, l := range f.logs[containerID] {
+		if !opts.Since.IsZero() && l.Time.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && l.Time.After(opts.Until) {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if opts.Tail > 0 && len(lines) > opts.Tail {
+		lines = lines[len(lines)-opts.Tail:]
+	}
+
+	// buffered so the history is readable as soon as we return
+	ch := make(chan LogLine, len(lines))
+	for _, l := range lines {
+		ch <- l
+	}
+	if !opts.Follow {
+		close(ch)
+		return ch, nil
+	}
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// index returns the position of a container by ID, callers must hold f.mu
+func (f *FakeRuntime) index(containerID string) int {
+	for i, c := range f.containers {
+		if c.ID == containerID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *FakeRuntime) DoAction(action ContainerAction, containerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, action.String()+" "+containerID)
+	if err := f.errs["DoAction"]; err != nil {
+		return err
+	}
+	if err := action.Validate(); err != nil {
+		return err
+	}
+
+	i := f.index(containerID)
+	if i < 0 {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+
+	c := &f.containers[i]
+	switch action.Kind {
+	case "start", "restart", "unpause":
+		c.State, c.Status = "running", "Up Less than a second"
+	case "stop":
+		c.State, c.Status = "exited", "Exited (0) Less than a second ago"
+		delete(f.stats, containerID)
+	case "kill":
+		if c.State != "running" {
+			return fmt.Errorf("container %s is not running", containerID)
+		}
+		// only the signals that end a process without a handler stop it here
+		code, ok := map[string]int{"": 137, "SIGKILL": 137, "SIGTERM": 143, "SIGINT": 130, "SIGQUIT": 131}[action.Signal]
+		if ok {
+			c.State, c.Status = "exited", fmt.Sprintf("Exited (%d) Less than a second ago", code)
+			delete(f.stats, containerID)
+		}
+	case "pause":
+		c.State, c.Status = "paused", "Up Less than a second (Paused)"
+	case "rename":
+		c.Names = []string{"/" + strings.TrimPrefix(action.Name, "/")}
+	case "rm", "remove":
+		if c.State == "running" {
+			return fmt.Errorf("cannot remove running container %s", containerID)
+		}
+		f.containers = append(f.containers[:i], f.containers[i+1:]...)
+	}
+	return nil
+}
+
+// RemoveContainer refuses running containers unless forced, like the daemon
+func (f *FakeRuntime) RemoveContainer(containerID string, opts RemoveOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call := "rm"
+	if opts.Force {
+		call += " -f"
+	}
+	if opts.Volumes {
+		call += " -v"
+	}
+	f.calls = append(f.calls, call+" "+containerID)
+	if err := f.errs["RemoveContainer"]; err != nil {
+		return err
+	}
+
+	i := f.index(containerID)
+	if i < 0 {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	if f.containers[i].State == "running" && !opts.Force {
+		return fmt.Errorf("cannot remove running container %s, stop it first or force", containerID)
+	}
+	f.containers = append(f.containers[:i], f.containers[i+1:]...)
+	delete(f.stats, containerID)
+	return nil
+}
+
+func (f *FakeRuntime) FetchComposeProjects() (map[string]*ComposeProject, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["FetchComposeProjects"]; err != nil {
+		return nil, err
+	}
+	return groupComposeProjects(f.snapshot()), nil
+}
+
+// Compose applies the action to every container of the project and streams a
+// line per container, the way compose reports progress
+func (f *FakeRuntime) Compose(ctx context.Context, project ComposeProject, action ComposeAction) (<-chan ComposeOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "compose "+strings.Join(action.Args(), " ")+" "+project.Name)
+	if err := f.errs["Compose"]; err != nil {
+		return nil, err
+	}
+	if _, err := composeArgs(project, action); err != nil {
+		return nil, err
+	}
+
+	var out []ComposeOutput
+	kept := f.containers[:0]
+	for _, c := range f.containers {
+		if c.ComposeProject != project.Name {
+			kept = append(kept, c)
+			continue
+		}
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+		switch action {
+		case ComposeUp, ComposeRestart, ComposeRecreate:
+			c.State, c.Status = "running", "Up Less than a second"
+			out = append(out, ComposeOutput{Text: "Container " + name + " Started"})
+		case ComposeStop:
+			c.State, c.Status = "exited", "Exited (0) Less than a second ago"
+			out = append(out, ComposeOutput{Text: "Container " + name + " Stopped"})
+		case ComposeDown, ComposeDownAll:
+			out = append(out, ComposeOutput{Text: "Container " + name + " Removed"})
+			continue
+		case ComposePull:
+			out = append(out, ComposeOutput{Text: c.ComposeService + " Pulled"})
+		}
+		kept = append(kept, c)
+	}
+	f.containers = kept
+
+	ch := make(chan ComposeOutput, len(out))
+	for _, o := range out {
+		ch <- o
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (f *FakeRuntime) Inspect(containerID string) (*ContainerInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["Inspect"]; err != nil {
+		return nil, err
+	}
+	if in, ok := f.inspects[containerID]; ok {
+		cp := *in
+		return &cp, nil
+	}
+	i := f.index(containerID)
+	if i < 0 {
+		return nil, fmt.Errorf("no such container: %s", containerID)
+	}
+	c := f.containers[i]
+	name := ""
+	if len(c.Names) > 0 {
+		name = c.Names[0]
+	}
+	return &ContainerInspect{ID: c.ID, Name: name, Image: c.Image, State: c.State}, nil
+}
+
+func (f *FakeRuntime) Events(ctx context.Context) (<-chan Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["Events"]; err != nil {
+		return nil, err
+	}
+	f.events = make(chan Event, 16)
+	return f.events, nil
+}
+
+// Emit pushes an event to the current subscriber, if any
+func (f *FakeRuntime) Emit(ev Event) {
+	f.mu.Lock()
+	ch := f.events
+	f.mu.Unlock()
+	if ch != nil {
+		ch <- ev
+	}
+}
+
+// SetImages replaces the image list, Containers is filled in from the container list
+func (f *FakeRuntime) SetImages(images ...Image) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.images = append([]Image(nil), images...)
+}
+
+func (f *FakeRuntime) ListImages() ([]Image, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["ListImages"]; err != nil {
+		return nil, err
+	}
+	out := append([]Image(nil), f.images...)
+	attachImageUsers(out, f.containers)
+	sortImages(out)
+	return out, nil
+}
+
+func (f *FakeRuntime) InspectImage(id string) (*ImageInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["InspectImage"]; err != nil {
+		return nil, err
+	}
+	for _, img := range f.images {
+		if img.ID == id || img.Ref() == id {
+			return &ImageInspect{ID: img.ID, RepoTags: []string{img.Ref()}, Created: img.Created, Size: img.Size}, nil
+		}
+	}
+	return nil, fmt.Errorf("no such image: %s", id)
+}
+
+func (f *FakeRuntime) RemoveImage(id string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "rmi "+id)
+	if err := f.errs["RemoveImage"]; err != nil {
+		return err
+	}
+	for i, img := range f.images {
+		if img.ID != id && img.Ref() != id {
+			continue
+		}
+		if !force {
+			for _, c := range f.containers {
+				if usesImage(c, img) {
+					return fmt.Errorf("conflict: unable to remove %s, container %s is using it", id, c.ID)
+				}
+			}
+		}
+		f.images = append(f.images[:i], f.images[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("no such image: %s", id)
+}
+
+func (f *FakeRuntime) PruneImages() (PruneReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "image prune")
+	if err := f.errs["PruneImages"]; err != nil {
+		return PruneReport{}, err
+	}
+	var report PruneReport
+	kept := f.images[:0]
+	for _, img := range f.images {
+		if img.Dangling {
+			report.Deleted = append(report.Deleted, img.ID)
+			report.SpaceReclaimed += img.Size
+			continue
+		}
+		kept = append(kept, img)
+	}
+	f.images = kept
+	return report, nil
+}
+
+// PullImage reports a short scripted pull and adds the image if it's new
+func (f *FakeRuntime) PullImage(ctx context.Context, ref string) (<-chan PullProgress, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "pull "+ref)
+	if err := f.errs["PullImage"]; err != nil {
+		return nil, err
+	}
+
+	repo, tag := splitRepoTag(ref)
+	if tag == "" {
+		tag = "latest"
+	}
+	found := false
+	for _, img := range f.images {
+		if img.Repository == repo && img.Tag == tag {
+			found = true
+		}
+	}
+	if !found {
+		f.images = append(f.images, Image{ID: fmt.Sprintf("%012x", len(f.images)+1), Repository: repo, Tag: tag})
+	}
+
+	steps := []PullProgress{
+		{Status: "Pulling from " + repo},
+		{ID: "aaaaaaaaaaaa", Status: "Downloading", Current: 512, Total: 1024},
+		{ID: "aaaaaaaaaaaa", Status: "Pull complete"},
+		{Status: "Status: Downloaded newer image for " + repo + ":" + tag},
+	}
+	ch := make(chan PullProgress, len(steps))
+	for _, s := range steps {
+		ch <- s
+	}
+	close(ch)
+	return ch, nil
+}
+
+// SetVolumes replaces the volume list, Containers is kept as given
+func (f *FakeRuntime) SetVolumes(volumes ...Volume) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volumes = append([]Volume(nil), volumes...)
+}
+
+func (f *FakeRuntime) ListVolumes() ([]Volume, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["ListVolumes"]; err != nil {
+		return nil, err
+	}
+	out := append([]Volume(nil), f.volumes...)
+	sortVolumes(out)
+	return out, nil
+}
+
+func (f *FakeRuntime) RemoveVolume(name string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "volume rm "+name)
+	if err := f.errs["RemoveVolume"]; err != nil {
+		return err
+	}
+	for i, v := range f.volumes {
+		if v.Name != name {
+			continue
+		}
+		if v.InUse() && !force {
+			return fmt.Errorf("remove %s: volume is in use - [%s]", name, v.Containers[0].ID)
+		}
+		f.volumes = append(f.volumes[:i], f.volumes[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("no such volume: %s", name)
+}
+
+func (f *FakeRuntime) PruneVolumes() (PruneReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "volume prune")
+	if err := f.errs["PruneVolumes"]; err != nil {
+		return PruneReport{}, err
+	}
+	var report PruneReport
+	kept := f.volumes[:0]
+	for _, v := range f.volumes {
+		if !v.InUse() {
+			report.Deleted = append(report.Deleted, v.Name)
+			if v.Size > 0 {
+				report.SpaceReclaimed += v.Size
+			}
+			continue
+		}
+		kept = append(kept, v)
+	}
+	f.volumes = kept
+	return report, nil
+}
+
+// SetNetworks replaces the network list, Containers is kept as given and
+// updated by connect/disconnect
+func (f *FakeRuntime) SetNetworks(networks ...Network) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.networks = append([]Network(nil), networks...)
+}
+
+func (f *FakeRuntime) ListNetworks() ([]Network, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["ListNetworks"]; err != nil {
+		return nil, err
+	}
+	out := make([]Network, len(f.networks))
+	for i, n := range f.networks {
+		n.Containers = append([]NetworkAttachment(nil), n.Containers...)
+		out[i] = n
+	}
+	sortNetworks(out)
+	return out, nil
+}
+
+func (f *FakeRuntime) network(name string) *Network {
+	for i := range f.networks {
+		if f.networks[i].Name == name || f.networks[i].ID == name {
+			return &f.networks[i]
+		}
+	}
+	return nil
+}
+
+func (f *FakeRuntime) ConnectNetwork(network, containerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "network connect "+network+" "+containerID)
+	if err := f.errs["ConnectNetwork"]; err != nil {
+		return err
+	}
+	n := f.network(network)
+	if n == nil {
+		return fmt.Errorf("network %s not found", network)
+	}
+	i := f.index(containerID)
+	if i < 0 {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	c := f.containers[i]
+	for _, a := range n.Containers {
+		if a.ContainerID == c.ID {
+			return fmt.Errorf("container %s is already connected to network %s", c.ID, n.Name)
+		}
+	}
+	att := NetworkAttachment{ContainerID: c.ID, State: c.State}
+	if len(c.Names) > 0 {
+		att.Name = c.Names[0]
+	}
+	if c.State == "running" {
+		att.IPAddress = fmt.Sprintf("10.99.0.%d/24", len(n.Containers)+2)
+	}
+	n.Containers = append(n.Containers, att)
+	return nil
+}
+
+func (f *FakeRuntime) DisconnectNetwork(network, containerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "network disconnect "+network+" "+containerID)
+	if err := f.errs["DisconnectNetwork"]; err != nil {
+		return err
+	}
+	n := f.network(network)
+	if n == nil {
+		return fmt.Errorf("network %s not found", network)
+	}
+	for i, a := range n.Containers {
+		if a.ContainerID == containerID {
+			n.Containers = append(n.Containers[:i], n.Containers[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("container %s is not connected to network %s", containerID, network)
+}
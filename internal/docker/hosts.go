@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/config"
+)
+
+// ============================================================================
+// Hosts (named daemons from the config)
+// ============================================================================
+
+const (
+	LocalHost = "local" // the daemon from runtime.type / runtime.socket
+	AllHosts  = "all"   // every host in one list, see MultiRuntime
+)
+
+// HostNames lists what the host switcher offers: local, the configured hosts,
+// and all when there's more than one to show
+func HostNames(cfg *config.Config) []string {
+	names := []string{LocalHost}
+	for _, h := range cfg.Hosts {
+		names = append(names, h.Name)
+	}
+	if len(cfg.Hosts) > 0 {
+		names = append(names, AllHosts)
+	}
+	return names
+}
+
+// ResolveHost turns what was given to --host into a host: a configured name,
+// or a DOCKER_HOST style url used as is
+func ResolveHost(cfg *config.Config, name string) (config.HostConfig, error) {
+	if h, ok := cfg.FindHost(name); ok {
+		return h, nil
+	}
+	if strings.Contains(name, "://") {
+		return config.HostConfig{Name: name, Host: name, Runtime: cfg.Runtime.Type}, nil
+	}
+	return config.HostConfig{}, fmt.Errorf("unknown host %q, add it under hosts: in the config", name)
+}
+
+// OpenHost connects to a host by name, LocalHost and AllHosts included
+func OpenHost(cfg *config.Config, name string) (Runtime, error) {
+	switch name {
+	case "", LocalHost:
+		return NewRuntime(cfg), nil
+	case AllHosts:
+		hosts := []NamedRuntime{{Name: LocalHost, Runtime: NewRuntime(cfg)}}
+		for _, h := range cfg.Hosts {
+			rt, err := NewHostRuntime(cfg, h)
+			if err != nil {
+				return nil, fmt.Errorf("host %s: %w", h.Name, err)
+			}
+			hosts = append(hosts, NamedRuntime{Name: h.Name, Runtime: rt})
+		}
+		return NewMultiRuntime(hosts...), nil
+	}
+	h, err := ResolveHost(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewHostRuntime(cfg, h)
+}
+
+// NewHostRuntime picks a backend for a configured host. unix and plain tcp
+// urls use the Engine API when it answers, everything else (contexts, podman
+// connections, ssh, tls) goes through the CLI, which knows how to reach them.
+func NewHostRuntime(cfg *config.Config, h config.HostConfig) (Runtime, error) {
+	podman := strings.EqualFold(strings.TrimSpace(h.Runtime), "podman") || h.Connection != ""
+
+	switch {
+	case h.Connection != "":
+		return newCLI("podman", "CONTAINER_CONNECTION="+h.Connection), nil
+
+	case h.Context != "":
+		if podman {
+			return nil, fmt.Errorf("contexts are a docker thing, use connection: for podman")
+		}
+		return newCLI("docker", "DOCKER_CONTEXT="+h.Context), nil
+
+	case h.Host != "":
+		u, err := url.Parse(h.Host)
+		if err != nil {
+			return nil, fmt.Errorf("bad host url %q: %w", h.Host, err)
+		}
+		// podman reads CONTAINER_HOST, docker DOCKER_HOST
+		env, bin := "DOCKER_HOST="+h.Host, "docker"
+		if podman {
+			env, bin = "CONTAINER_HOST="+h.Host, "podman"
+		}
+
+		var c *EngineClient
+		switch u.Scheme {
+		case "unix":
+			c = newEngineClient("unix", u.Path)
+		case "tcp":
+			// a tls daemon won't answer plain http, the CLI picks up DOCKER_TLS_VERIFY and friends
+			c = newEngineClient("tcp", u.Host)
+		case "ssh":
+			return newCLI(bin, env), nil
+		default:
+			return nil, fmt.Errorf("unsupported host url %q, use unix://, tcp:// or ssh://", h.Host)
+		}
+		c.name = bin
+		if c.Ping() == nil {
+			return c, nil
+		}
+		return newCLI(bin, env), nil
+	}
+	return nil, fmt.Errorf("host %s needs a context, host or connection", h.Name)
+}
+
+// newCLI is the CLI backend for bin with env picking the daemon
+func newCLI(bin string, env ...string) Runtime {
+	if bin == "podman" {
+		cli := NewPodmanCLI()
+		cli.env = env
+		return cli
+	}
+	cli := NewDockerCLI()
+	cli.env = env
+	return cli
+}
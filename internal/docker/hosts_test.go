@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiRuntime(t *testing.T) {
+	local := NewFakeRuntime(Container{ID: "aaa", Names: []string{"web"}, State: "running"})
+	remote := NewFakeRuntime(Container{ID: "bbb", Names: []string{"db"}, State: "running"})
+	multi := NewMultiRuntime(NamedRuntime{"local", local}, NamedRuntime{"vm1", remote})
+
+	containers, err := multi.ListContainers()
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	assert.Equal(t, "local", containers[0].Host)
+	assert.Equal(t, "vm1", containers[1].Host)
+
+	// actions go to the host that listed the container
+	require.NoError(t, multi.DoAction(Action("stop"), "bbb"))
+	assert.Empty(t, local.Calls())
+	assert.Equal(t, []string{"stop bbb"}, remote.Calls())
+	assert.Error(t, multi.DoAction(Action("stop"), "zzz"), "never listed")
+
+	// network connects too, the network is the container host's
+	_ = multi.ConnectNetwork("shop_default", "bbb")
+	assert.Empty(t, local.Calls())
+	assert.Equal(t, "network connect shop_default bbb", remote.Calls()[1])
+
+	// a removed container is forgotten on the next listing
+	require.NoError(t, remote.RemoveContainer("bbb", RemoveOptions{Force: true}))
+	_, err = multi.ListContainers()
+	require.NoError(t, err)
+	assert.Error(t, multi.DoAction(Action("start"), "bbb"))
+	remote.SetContainers(Container{ID: "bbb", Names: []string{"db"}, State: "running"})
+
+	// one host down still lists the other
+	remote.FailWith("ListContainers", errors.New("connection refused"))
+	containers, err = multi.ListContainers()
+	require.NoError(t, err)
+	assert.Len(t, containers, 1)
+	assert.Equal(t, []string{"vm1"}, multi.Unreachable())
+
+	// every host down is an error
+	local.FailWith("ListContainers", errors.New("connection refused"))
+	_, err = multi.ListContainers()
+	assert.ErrorContains(t, err, "vm1: connection refused")
+}
+
+func TestNewHostRuntime(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	rt, err := NewHostRuntime(cfg, config.HostConfig{Name: "nas", Host: "ssh://me@nas"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOCKER_HOST=ssh://me@nas"}, rt.(*DockerCLI).env)
+
+	rt, err = NewHostRuntime(cfg, config.HostConfig{Name: "vm", Connection: "machine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CONTAINER_CONNECTION=machine"}, rt.(*PodmanCLI).env)
+
+	_, err = NewHostRuntime(cfg, config.HostConfig{Name: "x", Host: "http://example"})
+	assert.ErrorContains(t, err, "unsupported host url")
+
+	_, err = NewHostRuntime(cfg, config.HostConfig{Name: "x", Runtime: "podman", Context: "staging"})
+	assert.Error(t, err)
+
+	_, err = ResolveHost(cfg, "nope")
+	assert.ErrorContains(t, err, `unknown host "nope"`)
+}
@@ -0,0 +1,533 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Images
+// ============================================================================
+
+// image inspect document, the Engine API and both CLIs use this shape
+type imageInspectJSON struct {
+	ID           string   `json:"Id"`
+	RepoTags     []string `json:"RepoTags"`
+	RepoDigests  []string `json:"RepoDigests"`
+	Created      string   `json:"Created"`
+	Size         int64    `json:"Size"`
+	Architecture string   `json:"Architecture"`
+	Os           string   `json:"Os"`
+	Author       string   `json:"Author"`
+	Config       struct {
+		User         string              `json:"User"`
+		WorkingDir   string              `json:"WorkingDir"`
+		Entrypoint   []string            `json:"Entrypoint"`
+		Cmd          []string            `json:"Cmd"`
+		Env          []string            `json:"Env"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+		Labels       map[string]string   `json:"Labels"`
+	} `json:"Config"`
+	RootFS struct {
+		Layers []string `json:"Layers"`
+	} `json:"RootFS"`
+}
+
+func (raw imageInspectJSON) toImageInspect() *ImageInspect {
+	ports := make([]string, 0, len(raw.Config.ExposedPorts))
+	for p := range raw.Config.ExposedPorts {
+		ports = append(ports, p)
+	}
+	sort.Strings(ports)
+
+	return &ImageInspect{
+		ID:           shortID(raw.ID),
+		RepoTags:     raw.RepoTags,
+		RepoDigests:  raw.RepoDigests,
+		Created:      parseTime(raw.Created),
+		Size:         raw.Size,
+		Architecture: raw.Architecture,
+		Os:           raw.Os,
+		Author:       raw.Author,
+		User:         raw.Config.User,
+		WorkingDir:   raw.Config.WorkingDir,
+		Entrypoint:   raw.Config.Entrypoint,
+		Cmd:          raw.Config.Cmd,
+		Env:          raw.Config.Env,
+		ExposedPorts: ports,
+		Labels:       raw.Config.Labels,
+		Layers:       len(raw.RootFS.Layers),
+	}
+}
+
+// splitRepoTag splits "registry:5000/app:1.0" into repo and tag, the colon in the port isn't a tag
+func splitRepoTag(ref string) (repo, tag string) {
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i:], "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// expandRepoTags turns one image with many tags into one Image per tag, like `docker images`
+func expandRepoTags(base Image, repoTags []string) []Image {
+	var out []Image
+	for _, rt := range repoTags {
+		if rt == "<none>:<none>" {
+			continue
+		}
+		img := base
+		img.Repository, img.Tag = splitRepoTag(rt)
+		out = append(out, img)
+	}
+	if len(out) == 0 {
+		base.Repository, base.Tag, base.Dangling = "<none>", "<none>", true
+		out = append(out, base)
+	}
+	return out
+}
+
+// usesImage reports whether c runs img. the ID is exact when the backend
+// reports it, otherwise we compare the reference c was created from.
+func usesImage(c Container, img Image) bool {
+	if c.ImageID != "" {
+		return c.ImageID == img.ID
+	}
+	if c.Image == "" {
+		return false
+	}
+	if strings.HasPrefix(strings.TrimPrefix(c.Image, "sha256:"), img.ID) {
+		return true
+	}
+	if img.Dangling {
+		return false
+	}
+	if c.Image == img.Ref() {
+		return true
+	}
+	// "nginx" means "nginx:latest"
+	return img.Tag == "latest" && c.Image == img.Repository
+}
+
+// attachImageUsers fills Image.Containers from the container list
+func attachImageUsers(images []Image, containers []Container) {
+	for i := range images {
+		images[i].Containers = nil
+		for _, c := range containers {
+			if usesImage(c, images[i]) && len(c.Names) > 0 {
+				images[i].Containers = append(images[i].Containers, c.Names[0])
+			}
+		}
+	}
+}
+
+// sortImages orders by repo:tag with dangling images last
+func sortImages(images []Image) {
+	sort.SliceStable(images, func(i, j int) bool {
+		if images[i].Dangling != images[j].Dangling {
+			return !images[i].Dangling
+		}
+		return images[i].Ref() < images[j].Ref()
+	})
+}
+
+var sizeUnits = map[string]float64{
+	"b": 1, "kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+var sizePattern = regexp.MustCompile(`^([0-9.]+)\s*([A-Za-z]*)$`)
+
+// parseHumanSize reads the sizes the CLIs print ("13.3MB", "1.2 GB", "512B")
+func parseHumanSize(s string) int64 {
+	match := sizePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0
+	}
+	n, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0
+	}
+	unit := strings.ToLower(match[2])
+	if unit == "" {
+		unit = "b"
+	}
+	return int64(n * sizeUnits[unit])
+}
+
+// FormatBytes renders a byte count the way docker does (SI units)
+func FormatBytes(n int64) string {
+	return humanSize(float64(n))
+}
+
+// pullLayerPattern matches the "<layer>: <status>" lines docker pull prints without a tty
+var pullLayerPattern = regexp.MustCompile(`^([0-9a-f]{12}): (.+)$`)
+
+func parsePullLine(line string) PullProgress {
+	line = strings.TrimSpace(line)
+	if m := pullLayerPattern.FindStringSubmatch(line); m != nil {
+		return PullProgress{ID: m[1], Status: m[2]}
+	}
+	if strings.HasPrefix(strings.ToLower(line), "error") {
+		return PullProgress{Error: line}
+	}
+	return PullProgress{Status: line}
+}
+
+// cliError folds the CLI's stderr into the error, "exit status 1" alone says nothing
+func cliError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// ============================================================================
+// Engine API
+// ============================================================================
+
+type engineImage struct {
+	ID       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Size     int64    `json:"Size"`
+	Created  int64    `json:"Created"`
+}
+
+func (c *EngineClient) ListImages() ([]Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var entries []engineImage
+	if err := c.getJSON(ctx, "/images/json", nil, &entries); err != nil {
+		return nil, err
+	}
+	containers, err := c.listEngineContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Image
+	for _, e := range entries {
+		base := Image{ID: shortID(e.ID), Size: e.Size, Created: time.Unix(e.Created, 0)}
+		out = append(out, expandRepoTags(base, e.RepoTags)...)
+	}
+	attachImageUsers(out, containers)
+	sortImages(out)
+	return out, nil
+}
+
+func (c *EngineClient) InspectImage(id string) (*ImageInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var raw imageInspectJSON
+	if err := c.getJSON(ctx, "/images/"+url.PathEscape(id)+"/json", nil, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toImageInspect(), nil
+}
+
+// PullImage streams /images/create progress until the pull finishes or ctx is cancelled
+func (c *EngineClient) PullImage(ctx context.Context, ref string) (<-chan PullProgress, error) {
+	repo, tag := splitRepoTag(ref)
+	if tag == "" {
+		tag = "latest"
+	}
+	query := url.Values{"fromImage": {repo}, "tag": {tag}}
+	resp, err := c.do(ctx, http.MethodPost, "/images/create", query)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan PullProgress)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var msg struct {
+				ID             string `json:"id"`
+				Status         string `json:"status"`
+				Error          string `json:"error"`
+				ProgressDetail struct {
+					Current int64 `json:"current"`
+					Total   int64 `json:"total"`
+				} `json:"progressDetail"`
+			}
+			if err := dec.Decode(&msg); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					ch <- PullProgress{Error: err.Error()}
+				}
+				return
+			}
+			p := PullProgress{ID: msg.ID, Status: msg.Status, Error: msg.Error,
+				Current: msg.ProgressDetail.Current, Total: msg.ProgressDetail.Total}
+			select {
+			case ch <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (c *EngineClient) RemoveImage(id string, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := url.Values{}
+	if force {
+		query.Set("force", "1")
+	}
+	resp, err := c.do(ctx, http.MethodDelete, "/images/"+url.PathEscape(id), query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PruneImages removes dangling images only, tagged-but-unused ones stay
+func (c *EngineClient) PruneImages() (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	query := url.Values{"filters": {`{"dangling":["true"]}`}}
+	resp, err := c.do(ctx, http.MethodPost, "/images/prune", query)
+	if err != nil {
+		return PruneReport{}, err
+	}
+	defer resp.Body.Close()
+
+	var raw struct {
+		ImagesDeleted []struct {
+			Deleted  string `json:"Deleted"`
+			Untagged string `json:"Untagged"`
+		} `json:"ImagesDeleted"`
+		SpaceReclaimed int64 `json:"SpaceReclaimed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return PruneReport{}, err
+	}
+	report := PruneReport{SpaceReclaimed: raw.SpaceReclaimed}
+	for _, d := range raw.ImagesDeleted {
+		if d.Deleted != "" {
+			report.Deleted = append(report.Deleted, shortID(d.Deleted))
+		}
+	}
+	return report, nil
+}
+
+// ============================================================================
+// CLI
+// ============================================================================
+
+// a line of `docker images --format {{json .}}`
+type dockerImageEntry struct {
+	ID         string `json:"ID"`
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	Size       string `json:"Size"`
+	CreatedAt  string `json:"CreatedAt"`
+}
+
+func (r *DockerCLI) ListImages() ([]Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "images", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+
+	var out []Image
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e dockerImageEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("parsing docker output: %w", err)
+		}
+		// "2024-05-01 10:00:00 +0200 CEST"
+		created, _ := time.Parse("2006-01-02 15:04:05 -0700 MST", e.CreatedAt)
+		out = append(out, Image{
+			ID:         shortID(e.ID),
+			Repository: e.Repository,
+			Tag:        e.Tag,
+			Size:       parseHumanSize(e.Size),
+			Created:    created,
+			Dangling:   e.Repository == "<none>",
+		})
+	}
+
+	containers, err := r.ps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachImageUsers(out, containers)
+	sortImages(out)
+	return out, nil
+}
+
+// an entry of `podman images --format json`
+type podmanImageEntry struct {
+	ID       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Size     int64    `json:"Size"`
+	Created  int64    `json:"Created"`
+}
+
+func (r *PodmanCLI) ListImages() ([]Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "images", "--format", "json").Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+	var entries []podmanImageEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("parsing podman output: %w", err)
+	}
+
+	var out []Image
+	for _, e := range entries {
+		base := Image{ID: shortID(e.ID), Size: e.Size, Created: time.Unix(e.Created, 0)}
+		out = append(out, expandRepoTags(base, e.RepoTags)...)
+	}
+
+	containers, err := r.ps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachImageUsers(out, containers)
+	sortImages(out)
+	return out, nil
+}
+
+func (r cliRuntime) InspectImage(id string) (*ImageInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "image", "inspect", id).Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+	var raws []imageInspectJSON
+	if err := json.Unmarshal(output, &raws); err != nil {
+		return nil, fmt.Errorf("parsing inspect output: %w", err)
+	}
+	if len(raws) == 0 {
+		return nil, fmt.Errorf("no such image: %s", id)
+	}
+	return raws[0].toImageInspect(), nil
+}
+
+// PullImage runs `<bin> pull` and turns each output line into a progress update.
+// without a tty neither CLI prints byte counts, so Current/Total stay 0.
+func (r cliRuntime) PullImage(ctx context.Context, ref string) (<-chan PullProgress, error) {
+	cmd := r.command(ctx, "pull", ref)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	// podman reports progress on stderr
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan PullProgress)
+	var wg sync.WaitGroup
+	pipe := func(rd io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(rd)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			select {
+			case ch <- parsePullLine(scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	wg.Add(2)
+	go pipe(stdout)
+	go pipe(stderr)
+	go func() {
+		wg.Wait()
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			ch <- PullProgress{Error: fmt.Sprintf("pull %s: %v", ref, err)}
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (r cliRuntime) RemoveImage(id string, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := []string{"rmi"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := r.command(ctx, append(args, id)...).Output()
+	return cliError(err)
+}
+
+var (
+	// docker: "deleted: sha256:...", podman: just the id
+	pruneDeletedPattern = regexp.MustCompile(`(?i)^(?:deleted: )?(?:sha256:)?([0-9a-f]{12,64})$`)
+	pruneTotalPattern   = regexp.MustCompile(`(?i)^total reclaimed space: (.+)$`)
+)
+
+func parsePruneOutput(output string) PruneReport {
+	var report PruneReport
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := pruneDeletedPattern.FindStringSubmatch(line); m != nil {
+			report.Deleted = append(report.Deleted, shortID(m[1]))
+		} else if m := pruneTotalPattern.FindStringSubmatch(line); m != nil {
+			report.SpaceReclaimed = parseHumanSize(m[1])
+		}
+	}
+	return report
+}
+
+func (r cliRuntime) PruneImages() (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := r.command(ctx, "image", "prune", "--force").Output()
+	if err != nil {
+		return PruneReport{}, cliError(err)
+	}
+	return parsePruneOutput(string(output)), nil
+}
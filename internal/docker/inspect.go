@@ -0,0 +1,171 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// inspectJSON is the raw inspect document. The Engine API, `docker inspect`
+// and `podman inspect` all use this shape.
+type inspectJSON struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	Created      string `json:"Created"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status     string `json:"Status"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		Pid        int    `json:"Pid"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				Start    string `json:"Start"`
+				End      string `json:"End"`
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Image       string            `json:"Image"`
+		Hostname    string            `json:"Hostname"`
+		User        string            `json:"User"`
+		WorkingDir  string            `json:"WorkingDir"`
+		Env         []string          `json:"Env"`
+		Cmd         []string          `json:"Cmd"`
+		Entrypoint  []string          `json:"Entrypoint"`
+		Labels      map[string]string `json:"Labels"`
+		Healthcheck *struct {
+			Test     []string      `json:"Test"`
+			Interval time.Duration `json:"Interval"`
+			Timeout  time.Duration `json:"Timeout"`
+			Retries  int           `json:"Retries"`
+		} `json:"Healthcheck"`
+	} `json:"Config"`
+	HostConfig struct {
+		RestartPolicy struct {
+			Name              string `json:"Name"`
+			MaximumRetryCount int    `json:"MaximumRetryCount"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Name        string `json:"Name"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		Mode        string `json:"Mode"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress   string   `json:"IPAddress"`
+			Gateway     string   `json:"Gateway"`
+			MacAddress  string   `json:"MacAddress"`
+			GlobalIPv6  string   `json:"GlobalIPv6Address"`
+			Aliases     []string `json:"Aliases"`
+			NetworkID   string   `json:"NetworkID"`
+			IPPrefixLen int      `json:"IPPrefixLen"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// parseTime reads docker's RFC3339 timestamps, zero time on anything odd
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t
+}
+
+func (raw inspectJSON) toInspect() *ContainerInspect {
+	in := &ContainerInspect{
+		ID:           shortID(raw.ID),
+		Name:         strings.TrimPrefix(raw.Name, "/"),
+		Image:        raw.Config.Image,
+		Created:      parseTime(raw.Created),
+		State:        strings.ToLower(raw.State.Status),
+		ExitCode:     raw.State.ExitCode,
+		Error:        raw.State.Error,
+		OOMKilled:    raw.State.OOMKilled,
+		Pid:          raw.State.Pid,
+		StartedAt:    parseTime(raw.State.StartedAt),
+		FinishedAt:   parseTime(raw.State.FinishedAt),
+		RestartCount: raw.RestartCount,
+
+		Hostname:   raw.Config.Hostname,
+		User:       raw.Config.User,
+		WorkingDir: raw.Config.WorkingDir,
+		Entrypoint: raw.Config.Entrypoint,
+		Cmd:        raw.Config.Cmd,
+		Env:        raw.Config.Env,
+		Labels:     raw.Config.Labels,
+
+		RestartPolicy:     raw.HostConfig.RestartPolicy.Name,
+		RestartMaxRetries: raw.HostConfig.RestartPolicy.MaximumRetryCount,
+	}
+
+	if hc := raw.Config.Healthcheck; hc != nil && len(hc.Test) > 0 && hc.Test[0] != "NONE" {
+		in.Healthcheck = &Healthcheck{Test: hc.Test, Interval: hc.Interval, Timeout: hc.Timeout, Retries: hc.Retries}
+	}
+	if h := raw.State.Health; h != nil {
+		in.Health = &HealthState{Status: Health(h.Status), FailingStreak: h.FailingStreak}
+		if n := len(h.Log); n > 0 {
+			last := h.Log[n-1]
+			in.Health.LastExitCode = last.ExitCode
+			in.Health.LastOutput = strings.TrimSpace(last.Output)
+			in.Health.LastCheck = parseTime(last.Start)
+		}
+		for _, l := range h.Log {
+			in.Health.Probes = append(in.Health.Probes, HealthProbe{
+				Start: parseTime(l.Start), End: parseTime(l.End), ExitCode: l.ExitCode, Output: strings.TrimSpace(l.Output),
+			})
+		}
+	}
+
+	for _, mt := range raw.Mounts {
+		in.Mounts = append(in.Mounts, Mount{
+			Type: mt.Type, Name: mt.Name, Source: mt.Source,
+			Destination: mt.Destination, Mode: mt.Mode, ReadWrite: mt.RW,
+		})
+	}
+
+	// map order is random, keep networks stable for the UI
+	names := make([]string, 0, len(raw.NetworkSettings.Networks))
+	for name := range raw.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n := raw.NetworkSettings.Networks[name]
+		ip := n.IPAddress
+		if ip != "" && n.IPPrefixLen > 0 {
+			ip = fmt.Sprintf("%s/%d", ip, n.IPPrefixLen)
+		}
+		in.Networks = append(in.Networks, NetworkEndpoint{
+			Name: name, NetworkID: shortID(n.NetworkID), IPAddress: ip, IPv6Address: n.GlobalIPv6,
+			Gateway: n.Gateway, MacAddress: n.MacAddress, Aliases: n.Aliases,
+		})
+	}
+	return in
+}
+
+// parseInspectArray handles the CLI output, which is always a JSON array
+func parseInspectArray(output []byte) (*ContainerInspect, error) {
+	var raws []inspectJSON
+	if err := json.Unmarshal(output, &raws); err != nil {
+		return nil, fmt.Errorf("parsing inspect output: %w", err)
+	}
+	if len(raws) == 0 {
+		return nil, fmt.Errorf("no such container")
+	}
+	return raws[0].toInspect(), nil
+}
@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Log streaming
+// ============================================================================
+
+// LogLine is a single line from a container's output
+type LogLine struct {
+	Time   time.Time // zero if the runtime didn't send a timestamp
+	Stream string    // "stdout" or "stderr"
+	Text   string
+}
+
+// LogOptions narrows a log stream, zero values mean "no limit"
+type LogOptions struct {
+	Follow bool
+	Tail   int // lines of history before following, 0 = all
+	Since  time.Time
+	Until  time.Time
+}
+
+// parseLogLine splits the RFC3339 timestamp `--timestamps` puts in front of each line
+func parseLogLine(stream, raw string) LogLine {
+	raw = strings.TrimRight(raw, "\r\n")
+	if ts, rest, ok := strings.Cut(raw, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return LogLine{Time: t, Stream: stream, Text: rest}
+		}
+	}
+	return LogLine{Stream: stream, Text: raw}
+}
+
+// maxLogLine is the longest line a log stream takes, past it the stream stops
+const maxLogLine = 1024 * 1024
+
+// scanError is the last line of a stream that broke off, so it doesn't end
+// without a word
+func scanError(err error) LogLine {
+	return LogLine{Time: time.Now(), Stream: "stderr", Text: "dockmate: log stream stopped: " + err.Error()}
+}
+
+// streamName maps the multiplexed frame header byte to a stream name
+func streamName(b byte) string {
+	if b == 2 {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// StreamLogs follows a container's logs on /containers/{id}/logs until ctx is
+// cancelled, the channel is closed when the stream ends
+func (c *EngineClient) StreamLogs(ctx context.Context, containerID string, opts LogOptions) (<-chan LogLine, error) {
+	query := url.Values{
+		"stdout":     {"1"},
+		"stderr":     {"1"},
+		"timestamps": {"1"},
+	}
+	if opts.Follow {
+		query.Set("follow", "1")
+	}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", strconv.FormatInt(opts.Since.Unix(), 10))
+	}
+	if !opts.Until.IsZero() {
+		query.Set("until", strconv.FormatInt(opts.Until.Unix(), 10))
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(containerID)+"/logs", query)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan LogLine)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		send := func(l LogLine) bool {
+			select {
+			case ch <- l:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		r := bufio.NewReader(resp.Body)
+		header, err := r.Peek(8)
+		if err != nil && len(header) == 0 {
+			return
+		}
+
+		// TTY containers send a raw stream, everything counts as stdout
+		if !isMultiplexed(header) {
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+			for scanner.Scan() {
+				if !send(parseLogLine("stdout", scanner.Text())) {
+					return
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				send(scanError(err))
+			}
+			return
+		}
+
+		// frames don't line up with lines, keep a partial line per stream. A frame is
+		// read in chunks, its header can claim up to 4GiB
+		partial := map[byte]string{}
+		flush := func() {
+			for _, stream := range []byte{1, 2} {
+				if partial[stream] != "" && !send(parseLogLine(streamName(stream), partial[stream])) {
+					return
+				}
+			}
+		}
+		hdr := make([]byte, 8)
+		buf := make([]byte, 64*1024)
+		for {
+			if _, err := io.ReadFull(r, hdr); err != nil {
+				flush()
+				return
+			}
+			for size := int(binary.BigEndian.Uint32(hdr[4:8])); size > 0; {
+				n, err := io.ReadFull(r, buf[:min(size, len(buf))])
+				size -= n
+
+				lines := strings.Split(partial[hdr[0]]+string(buf[:n]), "\n")
+				partial[hdr[0]] = lines[len(lines)-1]
+				for i, line := range lines {
+					if len(line) > maxLogLine {
+						send(scanError(bufio.ErrTooLong))
+						return
+					}
+					if i < len(lines)-1 && !send(parseLogLine(streamName(hdr[0]), line)) {
+						return
+					}
+				}
+				if err != nil {
+					flush()
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// StreamLogs runs `<bin> logs --timestamps` with stdout and stderr on separate pipes
+func (r cliRuntime) StreamLogs(ctx context.Context, containerID string, opts LogOptions) (<-chan LogLine, error) {
+	args := []string{"logs", "--timestamps"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(opts.Tail))
+	}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since", opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		args = append(args, "--until", opts.Until.Format(time.RFC3339))
+	}
+	args = append(args, containerID)
+
+	cmd := r.command(ctx, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan LogLine)
+	var wg sync.WaitGroup
+	pipe := func(stream string, rd io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(rd)
+		scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+		for scanner.Scan() {
+			select {
+			case ch <- parseLogLine(stream, scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case ch <- scanError(err):
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	wg.Add(2)
+	go pipe("stdout", stdout)
+	go pipe("stderr", stderr)
+	go func() {
+		wg.Wait()
+		cmd.Wait()
+		close(ch)
+	}()
+	return ch, nil
+}
@@ -0,0 +1,324 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"sync"
+)
+
+// ============================================================================
+// Aggregated runtime (every host in one list)
+// ============================================================================
+
+// NamedRuntime is a runtime and the host name it's shown under
+type NamedRuntime struct {
+	Name    string
+	Runtime Runtime
+}
+
+// MultiRuntime lists the containers of several hosts as one, each tagged with
+// its Host. container actions (network connects included) go to the host
+// that listed the container. images, volumes and networks only come from the
+// first host.
+type MultiRuntime struct {
+	hosts []NamedRuntime
+
+	mu          sync.Mutex
+	owner       map[string]Runtime // container id -> host it was listed on
+	unreachable []string           // hosts the last listing failed on
+}
+
+func NewMultiRuntime(hosts ...NamedRuntime) *MultiRuntime {
+	return &MultiRuntime{hosts: hosts, owner: map[string]Runtime{}}
+}
+
+func (r *MultiRuntime) primary() Runtime {
+	return r.hosts[0].Runtime
+}
+
+func (r *MultiRuntime) Name() string {
+	return r.primary().Name()
+}
+
+// route finds the host of a container, it has to have been listed first
+func (r *MultiRuntime) route(containerID string) (Runtime, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt := r.owner[containerID]
+	if rt == nil {
+		return nil, fmt.Errorf("no host known for container %s", containerID)
+	}
+	return rt, nil
+}
+
+// Unreachable names the hosts the last container listing failed on
+func (r *MultiRuntime) Unreachable() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unreachable...)
+}
+
+// each runs fn against every host at once and collects the errors by host
+func (r *MultiRuntime) each(fn func(i int, h NamedRuntime) error) []error {
+	errs := make([]error, len(r.hosts))
+	var wg sync.WaitGroup
+	for i, h := range r.hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(i, h); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", h.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// allFailed joins the errors when no host answered, a few unreachable hosts
+// shouldn't hide the rest
+func allFailed(errs []error) error {
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *MultiRuntime) ListContainers() ([]Container, error) {
+	lists := make([][]Container, len(r.hosts))
+	errs := r.each(func(i int, h NamedRuntime) error {
+		containers, err := h.Runtime.ListContainers()
+		for j := range containers {
+			containers[j].Host = h.Name
+		}
+		lists[i] = containers
+		return err
+	})
+	if err := allFailed(errs); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unreachable = nil
+	for i, err := range errs {
+		if err != nil {
+			r.unreachable = append(r.unreachable, r.hosts[i].Name)
+		}
+	}
+	// built again every time so removed containers don't pile up
+	r.owner = map[string]Runtime{}
+	var all []Container
+	for i, containers := range lists {
+		for _, c := range containers {
+			r.owner[c.ID] = r.hosts[i].Runtime
+		}
+		all = append(all, containers...)
+	}
+	return all, nil
+}
+
+func (r *MultiRuntime) GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
+	byHost := map[Runtime][]string{}
+	for _, id := range containerIDs {
+		if rt, err := r.route(id); err == nil {
+			byHost[rt] = append(byHost[rt], id)
+		}
+	}
+
+	var mu sync.Mutex
+	out := map[string]ContainerStats{}
+	failed := map[string]error{}
+	r.each(func(_ int, h NamedRuntime) error {
+		ids := byHost[h.Runtime]
+		if len(ids) == 0 {
+			return nil
+		}
+		stats, err := h.Runtime.GetAllContainerStats(ids)
+		mu.Lock()
+		defer mu.Unlock()
+		for id, s := range stats {
+			out[id] = s
+		}
+		// a host that failed outright is missing every one of its containers
+		var partial *StatsError
+		if errors.As(err, &partial) {
+			maps.Copy(failed, partial.Failed)
+		} else if err != nil {
+			for _, id := range ids {
+				failed[id] = fmt.Errorf("%s: %w", h.Name, err)
+			}
+		}
+		return err
+	})
+	return statsResult(out, failed)
+}
+
+// FetchComposeProjects keys projects by host/name so the same project on two
+// hosts stays two projects, Name is left as compose knows it
+func (r *MultiRuntime) FetchComposeProjects() (map[string]*ComposeProject, error) {
+	var mu sync.Mutex
+	out := map[string]*ComposeProject{}
+	errs := r.each(func(_ int, h NamedRuntime) error {
+		projects, err := h.Runtime.FetchComposeProjects()
+		mu.Lock()
+		defer mu.Unlock()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, p := range projects {
+			for i := range p.Containers {
+				p.Containers[i].Host = h.Name
+				r.owner[p.Containers[i].ID] = h.Runtime
+			}
+			out[h.Name+"/"+p.Name] = p
+		}
+		return err
+	})
+	if err := allFailed(errs); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *MultiRuntime) Compose(ctx context.Context, project ComposeProject, action ComposeAction) (<-chan ComposeOutput, error) {
+	if len(project.Containers) == 0 {
+		return nil, fmt.Errorf("no host known for project %s", project.Name)
+	}
+	rt, err := r.route(project.Containers[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Compose(ctx, project, action)
+}
+
+// Events merges the event streams of every host that has one
+func (r *MultiRuntime) Events(ctx context.Context) (<-chan Event, error) {
+	out := make(chan Event)
+	var wg sync.WaitGroup
+	var errs []error
+	for _, h := range r.hosts {
+		ch, err := h.Runtime.Events(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.Name, err))
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ev := range ch {
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	if len(errs) == len(r.hosts) {
+		return nil, errors.Join(errs...)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+// ============================================================================
+// Routed to the container's host
+// ============================================================================
+
+func (r *MultiRuntime) ExecCommand(containerID string, cmd ...string) *exec.Cmd {
+	rt, err := r.route(containerID)
+	if err != nil {
+		rt = r.primary()
+	}
+	return rt.ExecCommand(containerID, cmd...)
+}
+
+func (r *MultiRuntime) GetLogs(containerID string) ([]string, error) {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.GetLogs(containerID)
+}
+
+func (r *MultiRuntime) StreamLogs(ctx context.Context, containerID string, opts LogOptions) (<-chan LogLine, error) {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.StreamLogs(ctx, containerID, opts)
+}
+
+func (r *MultiRuntime) DoAction(action ContainerAction, containerID string) error {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return err
+	}
+	return rt.DoAction(action, containerID)
+}
+
+func (r *MultiRuntime) RemoveContainer(containerID string, opts RemoveOptions) error {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return err
+	}
+	return rt.RemoveContainer(containerID, opts)
+}
+
+func (r *MultiRuntime) Inspect(containerID string) (*ContainerInspect, error) {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Inspect(containerID)
+}
+
+// ConnectNetwork looks the network up on the container's host, not the one
+// the networks view lists
+func (r *MultiRuntime) ConnectNetwork(network, containerID string) error {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return err
+	}
+	return rt.ConnectNetwork(network, containerID)
+}
+
+func (r *MultiRuntime) DisconnectNetwork(network, containerID string) error {
+	rt, err := r.route(containerID)
+	if err != nil {
+		return err
+	}
+	return rt.DisconnectNetwork(network, containerID)
+}
+
+// ============================================================================
+// First host only
+// ============================================================================
+
+func (r *MultiRuntime) ListImages() ([]Image, error) { return r.primary().ListImages() }
+func (r *MultiRuntime) InspectImage(id string) (*ImageInspect, error) {
+	return r.primary().InspectImage(id)
+}
+func (r *MultiRuntime) RemoveImage(id string, force bool) error {
+	return r.primary().RemoveImage(id, force)
+}
+func (r *MultiRuntime) PruneImages() (PruneReport, error) { return r.primary().PruneImages() }
+func (r *MultiRuntime) PullImage(ctx context.Context, ref string) (<-chan PullProgress, error) {
+	return r.primary().PullImage(ctx, ref)
+}
+
+func (r *MultiRuntime) ListVolumes() ([]Volume, error) { return r.primary().ListVolumes() }
+func (r *MultiRuntime) RemoveVolume(name string, force bool) error {
+	return r.primary().RemoveVolume(name, force)
+}
+func (r *MultiRuntime) PruneVolumes() (PruneReport, error) { return r.primary().PruneVolumes() }
+
+func (r *MultiRuntime) ListNetworks() ([]Network, error) { return r.primary().ListNetworks() }
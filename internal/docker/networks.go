@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Networks
+// ============================================================================
+
+// network inspect document. the Engine API and docker use this shape, podman 4+
+// (netavark) lowercases the keys and lists subnets at the top level. json
+// field matching is case-insensitive so one struct reads all three.
+type networkJSON struct {
+	ID       string `json:"Id"`
+	Name     string `json:"Name"`
+	Driver   string `json:"Driver"`
+	Scope    string `json:"Scope"`
+	Internal bool   `json:"Internal"`
+	IPAM     struct {
+		Config []struct {
+			Subnet  string `json:"Subnet"`
+			Gateway string `json:"Gateway"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+	Subnets []struct {
+		Subnet  string `json:"subnet"`
+		Gateway string `json:"gateway"`
+	} `json:"subnets"`
+}
+
+func (raw networkJSON) toNetwork() Network {
+	n := Network{
+		ID:       shortID(raw.ID),
+		Name:     raw.Name,
+		Driver:   raw.Driver,
+		Scope:    raw.Scope,
+		Internal: raw.Internal,
+	}
+	var subnets, gateways []string
+	for _, c := range raw.IPAM.Config {
+		subnets = append(subnets, c.Subnet)
+		if c.Gateway != "" {
+			gateways = append(gateways, c.Gateway)
+		}
+	}
+	for _, c := range raw.Subnets {
+		subnets = append(subnets, c.Subnet)
+		if c.Gateway != "" {
+			gateways = append(gateways, c.Gateway)
+		}
+	}
+	n.Subnet = strings.Join(subnets, ", ")
+	n.Gateway = strings.Join(gateways, ", ")
+	return n
+}
+
+// attachNetworkContainers fills Network.Containers from endpoints keyed by network name
+func attachNetworkContainers(networks []Network, endpoints map[string][]NetworkAttachment) {
+	for i := range networks {
+		networks[i].Containers = endpoints[networks[i].Name]
+		sort.Slice(networks[i].Containers, func(a, b int) bool {
+			return networks[i].Containers[a].Name < networks[i].Containers[b].Name
+		})
+	}
+}
+
+// sortNetworks puts user networks first (that's what people debug), then the builtin ones
+func sortNetworks(networks []Network) {
+	sort.SliceStable(networks, func(i, j int) bool {
+		if networks[i].Builtin() != networks[j].Builtin() {
+			return !networks[i].Builtin()
+		}
+		return networks[i].Name < networks[j].Name
+	})
+}
+
+func withPrefixLen(ip string, prefix int) string {
+	if ip == "" || prefix == 0 {
+		return ip
+	}
+	return fmt.Sprintf("%s/%d", ip, prefix)
+}
+
+// ============================================================================
+// Engine API
+// ============================================================================
+
+func (c *EngineClient) ListNetworks() ([]Network, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var raws []networkJSON
+	if err := c.getJSON(ctx, "/networks", nil, &raws); err != nil {
+		return nil, err
+	}
+	// /networks doesn't list containers and /networks/{id} only lists running
+	// ones, the container list has every endpoint in one call
+	var entries []engineContainer
+	if err := c.getJSON(ctx, "/containers/json", url.Values{"all": {"1"}}, &entries); err != nil {
+		return nil, err
+	}
+
+	out := make([]Network, 0, len(raws))
+	for _, raw := range raws {
+		out = append(out, raw.toNetwork())
+	}
+	endpoints := map[string][]NetworkAttachment{}
+	for _, e := range entries {
+		ct := e.toContainer()
+		for name, ep := range e.NetworkSettings.Networks {
+			att := NetworkAttachment{
+				ContainerID: ct.ID,
+				State:       ct.State,
+				IPAddress:   withPrefixLen(ep.IPAddress, ep.IPPrefixLen),
+				IPv6Address: ep.GlobalIPv6Address,
+				MacAddress:  ep.MacAddress,
+			}
+			if len(ct.Names) > 0 {
+				att.Name = ct.Names[0]
+			}
+			endpoints[name] = append(endpoints[name], att)
+		}
+	}
+	attachNetworkContainers(out, endpoints)
+	sortNetworks(out)
+	return out, nil
+}
+
+func (c *EngineClient) ConnectNetwork(network, containerID string) error {
+	return c.networkAction("connect", network, map[string]any{"Container": containerID})
+}
+
+func (c *EngineClient) DisconnectNetwork(network, containerID string) error {
+	return c.networkAction("disconnect", network, map[string]any{"Container": containerID})
+}
+
+func (c *EngineClient) networkAction(action, network string, body any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.doBody(ctx, http.MethodPost, "/networks/"+url.PathEscape(network)+"/"+action, nil, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ============================================================================
+// CLI
+// ============================================================================
+
+// ListNetworks works the same on docker and podman: `network ls -q` for the
+// ids, one `network inspect` for the details and one container inspect for endpoints
+func (r cliRuntime) ListNetworks() ([]Network, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "network", "ls", "-q").Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+	var ids []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	output, err = r.command(ctx, append([]string{"network", "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+	var raws []networkJSON
+	if err := json.Unmarshal(output, &raws); err != nil {
+		return nil, fmt.Errorf("parsing network inspect output: %w", err)
+	}
+	out := make([]Network, 0, len(raws))
+	for _, raw := range raws {
+		out = append(out, raw.toNetwork())
+	}
+
+	containerIDs, err := r.containerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inspected, err := r.inspectAll(ctx, containerIDs)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := map[string][]NetworkAttachment{}
+	for _, raw := range inspected {
+		in := raw.toInspect()
+		for _, ep := range in.Networks {
+			endpoints[ep.Name] = append(endpoints[ep.Name], NetworkAttachment{
+				ContainerID: in.ID,
+				Name:        in.Name,
+				State:       in.State,
+				IPAddress:   ep.IPAddress,
+				IPv6Address: ep.IPv6Address,
+				MacAddress:  ep.MacAddress,
+			})
+		}
+	}
+	attachNetworkContainers(out, endpoints)
+	sortNetworks(out)
+	return out, nil
+}
+
+func (r cliRuntime) ConnectNetwork(network, containerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.command(ctx, "network", "connect", network, containerID).Output()
+	return cliError(err)
+}
+
+func (r cliRuntime) DisconnectNetwork(network, containerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.command(ctx, "network", "disconnect", network, containerID).Output()
+	return cliError(err)
+}
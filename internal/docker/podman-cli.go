@@ -0,0 +1,158 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// PodmanCLI shells out to the podman binary
+type PodmanCLI struct {
+	cliRuntime
+}
+
+func NewPodmanCLI() *PodmanCLI {
+	return &PodmanCLI{cliRuntime{bin: "podman"}}
+}
+
+type podmanPort struct {
+	HostPort      int    `json:"host_port"`
+	ContainerPort int    `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+// an entry of `podman ps --format json`
+type podmanPsEntry struct {
+	Id      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	Status  string            `json:"Status"`
+	State   string            `json:"State"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []podmanPort      `json:"Ports"`
+	Created int64             `json:"Created"`
+}
+
+func (e podmanPsEntry) toContainer() Container {
+	labels := e.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	workingDir := labels["com.docker.compose.project.working_dir"]
+	var created time.Time
+	if e.Created > 0 {
+		created = time.Unix(e.Created, 0)
+	}
+
+	return Container{
+		ID:                   e.Id,
+		Names:                e.Names,
+		Image:                e.Image,
+		ImageID:              shortID(e.ImageID),
+		Status:               e.Status,
+		State:                strings.ToLower(e.State),
+		Health:               ParseHealth(e.Status),
+		Ports:                formatPodmanPorts(e.Ports),
+		Created:              created,
+		Labels:               labels,
+		ComposeProject:       composeProjectName(labels),
+		ComposeService:       labels["com.docker.compose.service"],
+		ComposeDirectory:     workingDir,
+		ComposeFileDirectory: workingDir + "/" + labels["com.docker.compose.project.config_files"],
+	}
+}
+
+// ps runs `podman ps -a` with extra args. podman prints a JSON array, but
+// some older versions print one object per line so we handle both.
+func (r *PodmanCLI) ps(ctx context.Context, extra ...string) ([]Container, error) {
+	args := append([]string{"ps", "--all", "--format", "json"}, extra...)
+	output, err := r.command(ctx, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Container
+
+	var entries []podmanPsEntry
+	if err := json.Unmarshal(output, &entries); err == nil {
+		for _, e := range entries {
+			out = append(out, e.toContainer())
+		}
+		return out, nil
+	}
+
+	// Fallback - newline delimited
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var e podmanPsEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue // skip weird lines
+		}
+		out = append(out, e.toContainer())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListContainers lists every container, stats come from GetAllContainerStats
+func (r *PodmanCLI) ListContainers() ([]Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return r.ps(ctx)
+}
+
+// GetAllContainerStats fetches stats for multiple containers in a single podman stats call.
+// podman's {{json .}} uses different keys so we spell the template out.
+func (r *PodmanCLI) GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error) {
+	if len(containerIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := []string{"stats", "--no-stream", "--format",
+		`{"ID":"{{.ID}}","CPUPerc":"{{.CPUPerc}}","MemUsage":"{{.MemUsage}}","NetIO":"{{.NetIO}}","BlockIO":"{{.BlockIO}}","PIDs":"{{.PIDs}}"}`}
+	args = append(args, containerIDs...)
+
+	output, err := r.command(ctx, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	// podman stats prints short IDs, ps gave us long ones
+	return parseStatsLines(output, func(id string) string {
+		for _, longID := range containerIDs {
+			if strings.HasPrefix(longID, id) {
+				return longID
+			}
+		}
+		return id
+	}), nil
+}
+
+// FetchComposeProjects fetches podman-compose projects (and quadlet units) with their containers
+func (r *PodmanCLI) FetchComposeProjects() (map[string]*ComposeProject, error) {
+	// 30 sec timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// well podman uses io.podman.compose labels
+	containers, err := r.ps(ctx, "--filter", "label=io.podman.compose.project")
+	if err != nil {
+		return nil, err
+	}
+
+	return groupComposeProjects(containers), nil
+}
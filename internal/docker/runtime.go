@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/config"
+)
+
+// Runtime is a container backend. The TUI only talks to this, so it can be
+// pointed at the Engine API, the docker/podman CLI or a fake in tests.
+type Runtime interface {
+	// Name is the runtime binary ("docker" / "podman")
+	Name() string
+	// ExecCommand is an interactive `exec -it` into a container on the same
+	// daemon, run with the terminal handed over
+	ExecCommand(containerID string, cmd ...string) *exec.Cmd
+
+	ListContainers() ([]Container, error)
+	GetAllContainerStats(containerIDs []string) (map[string]ContainerStats, error)
+	GetLogs(containerID string) ([]string, error)
+	DoAction(action ContainerAction, containerID string) error
+	RemoveContainer(containerID string, opts RemoveOptions) error
+	FetchComposeProjects() (map[string]*ComposeProject, error)
+	Inspect(containerID string) (*ContainerInspect, error)
+	// Compose runs a project-wide compose command and streams its output, the
+	// channel is closed when the command exits
+	Compose(ctx context.Context, project ComposeProject, action ComposeAction) (<-chan ComposeOutput, error)
+
+	ListImages() ([]Image, error)
+	InspectImage(id string) (*ImageInspect, error)
+	RemoveImage(id string, force bool) error
+	PruneImages() (PruneReport, error)
+	// PullImage streams pull progress, the channel is closed when the pull is done
+	PullImage(ctx context.Context, ref string) (<-chan PullProgress, error)
+
+	ListVolumes() ([]Volume, error)
+	RemoveVolume(name string, force bool) error
+	// PruneVolumes removes every volume no container mounts, named ones included
+	PruneVolumes() (PruneReport, error)
+
+	ListNetworks() ([]Network, error)
+	ConnectNetwork(network, containerID string) error
+	DisconnectNetwork(network, containerID string) error
+
+	// StreamLogs streams a container's logs until ctx is cancelled (or the
+	// history is exhausted when not following), the channel is closed at the end
+	StreamLogs(ctx context.Context, containerID string, opts LogOptions) (<-chan LogLine, error)
+
+	// Events streams container events until ctx is cancelled, the channel is
+	// closed when the stream ends
+	Events(ctx context.Context) (<-chan Event, error)
+}
+
+var (
+	_ Runtime = (*EngineClient)(nil)
+	_ Runtime = (*DockerCLI)(nil)
+	_ Runtime = (*PodmanCLI)(nil)
+	_ Runtime = (*FakeRuntime)(nil)
+	_ Runtime = (*MultiRuntime)(nil)
+)
+
+// RuntimeName normalises the configured runtime type to "docker" or "podman"
+func RuntimeName(cfg *config.Config) string {
+	if strings.TrimSpace(strings.ToLower(cfg.Runtime.Type)) == "podman" {
+		return "podman"
+	}
+	return "docker"
+}
+
+// NewRuntime picks a backend for cfg: the Engine API when its socket answers,
+// otherwise the CLI for the configured runtime
+func NewRuntime(cfg *config.Config) Runtime {
+	name := RuntimeName(cfg)
+
+	socket := socketPath(cfg)
+	if _, err := os.Stat(socket); err == nil {
+		c := NewEngineClient(socket)
+		c.name = name
+		if c.Ping() == nil {
+			return c
+		}
+	}
+
+	if name == "podman" {
+		return NewPodmanCLI()
+	}
+	return NewDockerCLI()
+}
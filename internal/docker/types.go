@@ -1,5 +1,12 @@
 package docker
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
 type ProjectStatus int
 
 const (
@@ -9,6 +16,18 @@ const (
 	Unknown
 )
 
+func (s ProjectStatus) String() string {
+	switch s {
+	case AllRunning:
+		return "running"
+	case SomeStopped:
+		return "partial"
+	case AllStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
 type ComposeProject struct {
 	Name       string
 	Containers []Container
@@ -19,37 +38,111 @@ type ComposeProject struct {
 
 // Container holds all the data we show in the TUI
 type Container struct {
-	ID     string   // short container id
-	Names  []string // can have multiple names
-	Image  string   // image name like "nginx:latest"
-	Status string   // human readable status
-	State  string   // running/exited/etc
-	Memory string   // mem usage %
-	CPU    string   // cpu usage %
-	//PIDs    string // process count
-	Ports                string // ports
-	NetIO                string // network I/O
-	BlockIO              string // block I/O
-	ComposeProject       string // compose project name (empty if standalone)
-	ComposeService       string // compose service name
-	ComposeNumber        string // compose container number
+	ID                   string            // short container id
+	Names                []string          // can have multiple names
+	Image                string            // image name like "nginx:latest"
+	ImageID              string            // short image id, empty when the backend doesn't report it
+	Status               string            // human readable status
+	State                string            // running/exited/etc
+	Health               Health            // healthcheck result, HealthNone without a healthcheck
+	Stats                *ContainerStats   // latest stats, nil until fetched (running containers only)
+	Ports                string            // ports
+	Created              time.Time         // zero when the backend doesn't report it
+	IPAddress            string            // first network's address, only the engine api lists it
+	Labels               map[string]string // container labels
+	ComposeProject       string            // compose project name (empty if standalone)
+	ComposeService       string            // compose service name
+	ComposeNumber        string            // compose container number
 	ComposeDirectory     string
 	ComposeFileDirectory string
+	Host                 string // host it was listed on in the aggregated view, empty otherwise
+}
+
+// Health is where a container's healthcheck stands
+type Health string
+
+const (
+	HealthNone      Health = "" // no healthcheck
+	HealthStarting  Health = "starting"
+	HealthHealthy   Health = "healthy"
+	HealthUnhealthy Health = "unhealthy"
+)
+
+// ParseHealth reads the health docker and podman append to the status,
+// "Up 5 minutes (healthy)"
+func ParseHealth(status string) Health {
+	switch {
+	case strings.HasSuffix(status, " (healthy)"):
+		return HealthHealthy
+	case strings.HasSuffix(status, " (unhealthy)"):
+		return HealthUnhealthy
+	case strings.HasSuffix(status, " (health: starting)"), strings.HasSuffix(status, " (starting)"):
+		return HealthStarting
+	}
+	return HealthNone
+}
+
+// RemoveOptions tweak container removal
+type RemoveOptions struct {
+	Force   bool // kill the container first if it's running
+	Volumes bool // also remove its anonymous volumes
 }
+
 type ComposeInfo struct {
 	Project string
 	Service string
 	Number  int
 }
 
-// ContainerStats holds stats for a single container
+// ContainerStats is one stats sample for a container in absolute numbers,
+// formatting is left to whoever shows them
 type ContainerStats struct {
-	ID     string
-	CPU    string
-	Memory string
-	// PIDs    string
-	NetIO   string
-	BlockIO string
+	ID         string
+	CPU        float64 // fraction of one core, 1.5 = 150%
+	MemUsed    uint64  // bytes, page cache not counted
+	MemLimit   uint64  // bytes, 0 when unknown
+	NetRx      uint64  // bytes received, all networks
+	NetTx      uint64  // bytes sent
+	BlockRead  uint64  // bytes
+	BlockWrite uint64  // bytes
+	PIDs       uint64  // processes
+}
+
+// MemPercent is memory used against the limit, 0 without a limit
+func (s ContainerStats) MemPercent() float64 {
+	if s.MemLimit == 0 {
+		return 0
+	}
+	return float64(s.MemUsed) / float64(s.MemLimit) * 100
+}
+
+// StatsError comes back from GetAllContainerStats next to the stats that
+// did arrive, naming the containers that have none
+type StatsError struct {
+	Failed map[string]error // by container ID
+}
+
+func (e *StatsError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("no stats for %d containers, %s: %v", len(ids), ids[0], e.Failed[ids[0]])
+}
+
+// statsResult is what a stats fan-out returns: an error of its own when
+// nothing came back, the stats and a *StatsError when some did
+func statsResult(stats map[string]ContainerStats, failed map[string]error) (map[string]ContainerStats, error) {
+	if len(failed) == 0 {
+		return stats, nil
+	}
+	if len(stats) == 0 {
+		for _, err := range failed {
+			return nil, err
+		}
+	}
+	return stats, &StatsError{Failed: failed}
 }
 
 // sent when we finish fetching the container list
@@ -58,9 +151,205 @@ type ContainersMsg struct {
 	Err        error
 }
 
-// sent when logs are ready
-type LogsMsg struct {
+// Event is a container lifecycle event from the runtime's event stream
+type Event struct {
+	Action      string // create, start, die, destroy, health_status, oom, ...
+	ContainerID string
+	Name        string
+	Image       string
+	ExitCode    string            // set on die
+	Health      Health            // set on health_status
+	Labels      map[string]string // container labels at the time of the event
+	Time        time.Time
+}
+
+// ContainerInspect is the typed subset of `docker inspect` we show in the TUI
+type ContainerInspect struct {
+	ID           string
+	Name         string
+	Image        string
+	Created      time.Time
+	State        string // running/exited/etc
+	ExitCode     int
+	Error        string
+	OOMKilled    bool
+	Pid          int
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	RestartCount int
+
+	Hostname   string
+	User       string
+	WorkingDir string
+	Entrypoint []string
+	Cmd        []string
+	Env        []string // KEY=value, as docker returns them
+	Labels     map[string]string
+
+	RestartPolicy     string // no, always, on-failure, unless-stopped
+	RestartMaxRetries int
+
+	Healthcheck *Healthcheck // nil if the image doesn't define one
+	Health      *HealthState // nil if there's no healthcheck
+
+	Mounts   []Mount
+	Networks []NetworkEndpoint
+}
+
+// Healthcheck is the configured check
+type Healthcheck struct {
+	Test     []string
+	Interval time.Duration
+	Timeout  time.Duration
+	Retries  int
+}
+
+// HealthState is the result of the latest health checks
+type HealthState struct {
+	Status        Health
+	FailingStreak int
+	LastCheck     time.Time
+	LastExitCode  int
+	LastOutput    string
+	Probes        []HealthProbe // the runtime keeps the last 5, oldest first
+}
+
+// HealthProbe is one run of the healthcheck
+type HealthProbe struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
+}
+
+// Mount is a volume, bind or tmpfs mount
+type Mount struct {
+	Type        string // volume/bind/tmpfs
+	Name        string // volume name, empty for binds
+	Source      string
+	Destination string
+	Mode        string
+	ReadWrite   bool
+}
+
+// NetworkEndpoint is the container's attachment to one network
+type NetworkEndpoint struct {
+	Name        string
+	NetworkID   string
+	IPAddress   string // with prefix length, e.g. 172.18.0.2/16
+	IPv6Address string
+	Gateway     string
+	MacAddress  string
+	Aliases     []string
+}
+
+// Image is one repo:tag of a local image, an image with several tags shows up once per tag
+type Image struct {
+	ID         string // short image id
+	Repository string // "<none>" for dangling images
+	Tag        string
+	Size       int64 // bytes
+	Created    time.Time
+	Dangling   bool
+	Containers []string // names of containers (running or not) using this image
+}
+
+// Ref is the repo:tag to pull or show, falling back to the ID for dangling images
+func (i Image) Ref() string {
+	if i.Dangling || i.Repository == "" || i.Repository == "<none>" {
+		return i.ID
+	}
+	if i.Tag == "" || i.Tag == "<none>" {
+		return i.Repository
+	}
+	return i.Repository + ":" + i.Tag
+}
+
+// ImageInspect is the typed subset of `docker image inspect` we show in the TUI
+type ImageInspect struct {
+	ID           string
+	RepoTags     []string
+	RepoDigests  []string
+	Created      time.Time
+	Size         int64
+	Architecture string
+	Os           string
+	Author       string
+	User         string
+	WorkingDir   string
+	Entrypoint   []string
+	Cmd          []string
+	Env          []string
+	ExposedPorts []string
+	Labels       map[string]string
+	Layers       int
+}
+
+// PullProgress is one status update while pulling an image
+type PullProgress struct {
+	ID      string // layer id, empty for messages about the whole image
+	Status  string // "Downloading", "Pull complete", ...
+	Current int64  // bytes done, 0 if unknown
+	Total   int64  // bytes total, 0 if unknown
+	Error   string
+}
+
+// PruneReport is what a prune removed
+type PruneReport struct {
+	Deleted        []string
+	SpaceReclaimed int64 // bytes, 0 if the backend doesn't say
+}
+
+// Volume is a named (or anonymous) volume with the containers mounting it
+type Volume struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	Size       int64 // bytes, -1 when the backend didn't compute it
+	Created    time.Time
+	Labels     map[string]string
+	Containers []VolumeUser // running and stopped containers mounting it
+}
+
+// VolumeUser is a container that mounts a volume
+type VolumeUser struct {
 	ID    string
-	Lines []string
-	Err   error
+	Name  string
+	State string // running/exited/etc
+}
+
+// InUse reports whether any container, running or not, mounts the volume
+func (v Volume) InUse() bool {
+	return len(v.Containers) > 0
+}
+
+// Network is a container network with the containers attached to it
+type Network struct {
+	ID         string
+	Name       string
+	Driver     string // bridge/host/overlay/macvlan/null
+	Scope      string // local/swarm
+	Internal   bool
+	Subnet     string // comma separated when there's more than one (v4 + v6)
+	Gateway    string
+	Containers []NetworkAttachment // running and stopped, stopped ones have no addresses
+}
+
+// Builtin reports whether this is one of the networks the daemon creates itself
+func (n Network) Builtin() bool {
+	switch n.Name {
+	case "bridge", "host", "none", "podman":
+		return true
+	}
+	return false
+}
+
+// NetworkAttachment is one container's endpoint on a network
+type NetworkAttachment struct {
+	ContainerID string
+	Name        string
+	State       string
+	IPAddress   string // with prefix length, e.g. 172.18.0.2/16
+	IPv6Address string
+	MacAddress  string
 }
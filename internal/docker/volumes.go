@@ -0,0 +1,362 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Volumes
+// ============================================================================
+
+// attachVolumeUsers fills Volume.Containers. mounts maps a short container id
+// to the names of the volumes it mounts.
+func attachVolumeUsers(volumes []Volume, containers []Container, mounts map[string][]string) {
+	byName := make(map[string]int, len(volumes))
+	for i := range volumes {
+		volumes[i].Containers = nil
+		byName[volumes[i].Name] = i
+	}
+	for _, c := range containers {
+		for _, name := range mounts[shortID(c.ID)] {
+			i, ok := byName[name]
+			if !ok {
+				continue
+			}
+			user := VolumeUser{ID: shortID(c.ID), State: c.State}
+			if len(c.Names) > 0 {
+				user.Name = c.Names[0]
+			}
+			volumes[i].Containers = append(volumes[i].Containers, user)
+		}
+	}
+}
+
+func sortVolumes(volumes []Volume) {
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+}
+
+// parseDfVolumeSizes reads the "Local Volumes space usage" table of
+// `system df -v`, docker and podman print the same layout
+func parseDfVolumeSizes(output string) map[string]int64 {
+	sizes := map[string]int64{}
+	inVolumes, inTable := false, false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Local Volumes space usage"):
+			inVolumes = true
+		case !inVolumes:
+		case strings.HasPrefix(line, "VOLUME NAME"):
+			inTable = true
+		case line == "":
+			if inTable {
+				return sizes
+			}
+		case inTable:
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				sizes[fields[0]] = parseHumanSize(fields[len(fields)-1])
+			}
+		}
+	}
+	return sizes
+}
+
+// parseVolumePruneOutput picks the volume names out of `volume prune`.
+// docker prints a "Deleted Volumes:" header and a total, podman just the names.
+func parseVolumePruneOutput(output string) PruneReport {
+	var report PruneReport
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := pruneTotalPattern.FindStringSubmatch(line); m != nil {
+			report.SpaceReclaimed = parseHumanSize(m[1])
+			continue
+		}
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		report.Deleted = append(report.Deleted, line)
+	}
+	return report
+}
+
+// ============================================================================
+// Engine API
+// ============================================================================
+
+type engineVolume struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Mountpoint string            `json:"Mountpoint"`
+	CreatedAt  string            `json:"CreatedAt"`
+	Labels     map[string]string `json:"Labels"`
+	UsageData  *struct {
+		Size     int64 `json:"Size"`
+		RefCount int64 `json:"RefCount"`
+	} `json:"UsageData"`
+}
+
+func (e engineVolume) toVolume() Volume {
+	v := Volume{
+		Name:       e.Name,
+		Driver:     e.Driver,
+		Mountpoint: e.Mountpoint,
+		Size:       -1,
+		Created:    parseTime(e.CreatedAt),
+		Labels:     e.Labels,
+	}
+	if e.UsageData != nil && e.UsageData.Size >= 0 {
+		v.Size = e.UsageData.Size
+	}
+	return v
+}
+
+// ListVolumes uses /system/df rather than /volumes since only df computes sizes
+func (c *EngineClient) ListVolumes() ([]Volume, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var df struct {
+		Volumes []engineVolume `json:"Volumes"`
+	}
+	if err := c.getJSON(ctx, "/system/df", url.Values{"type": {"volume"}}, &df); err != nil {
+		return nil, err
+	}
+	var entries []engineContainer
+	if err := c.getJSON(ctx, "/containers/json", url.Values{"all": {"1"}}, &entries); err != nil {
+		return nil, err
+	}
+
+	out := make([]Volume, 0, len(df.Volumes))
+	for _, e := range df.Volumes {
+		out = append(out, e.toVolume())
+	}
+	containers := make([]Container, 0, len(entries))
+	mounts := map[string][]string{}
+	for _, e := range entries {
+		containers = append(containers, e.toContainer())
+		for _, mt := range e.Mounts {
+			if mt.Type == "volume" && mt.Name != "" {
+				mounts[shortID(e.ID)] = append(mounts[shortID(e.ID)], mt.Name)
+			}
+		}
+	}
+	attachVolumeUsers(out, containers, mounts)
+	sortVolumes(out)
+	return out, nil
+}
+
+func (c *EngineClient) RemoveVolume(name string, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := url.Values{}
+	if force {
+		query.Set("force", "1")
+	}
+	resp, err := c.do(ctx, http.MethodDelete, "/volumes/"+url.PathEscape(name), query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PruneVolumes passes all=true, without it API >= 1.42 only prunes anonymous volumes
+func (c *EngineClient) PruneVolumes() (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	query := url.Values{"filters": {`{"all":["true"]}`}}
+	resp, err := c.do(ctx, http.MethodPost, "/volumes/prune", query)
+	if err != nil {
+		return PruneReport{}, err
+	}
+	defer resp.Body.Close()
+
+	var raw struct {
+		VolumesDeleted []string `json:"VolumesDeleted"`
+		SpaceReclaimed int64    `json:"SpaceReclaimed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return PruneReport{}, err
+	}
+	return PruneReport{Deleted: raw.VolumesDeleted, SpaceReclaimed: raw.SpaceReclaimed}, nil
+}
+
+// ============================================================================
+// CLI
+// ============================================================================
+
+// containerMounts returns the volume names each container mounts, keyed by short id
+func (r cliRuntime) containerMounts(ctx context.Context, containers []Container) (map[string][]string, error) {
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	raws, err := r.inspectAll(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	mounts := map[string][]string{}
+	for _, raw := range raws {
+		for _, mt := range raw.Mounts {
+			if mt.Type == "volume" && mt.Name != "" {
+				mounts[shortID(raw.ID)] = append(mounts[shortID(raw.ID)], mt.Name)
+			}
+		}
+	}
+	return mounts, nil
+}
+
+// volumeSizes runs `system df -v`, which can be slow on hosts with lots of
+// data. sizes are best effort, a failure just leaves them unknown.
+func (r cliRuntime) volumeSizes(ctx context.Context) map[string]int64 {
+	output, err := r.command(ctx, "system", "df", "-v").Output()
+	if err != nil {
+		return nil
+	}
+	return parseDfVolumeSizes(string(output))
+}
+
+// finishVolumes fills in sizes and users, shared by both CLIs once they've listed volumes
+func (r cliRuntime) finishVolumes(ctx context.Context, volumes []Volume, containers []Container) ([]Volume, error) {
+	sizes := r.volumeSizes(ctx)
+	for i := range volumes {
+		volumes[i].Size = -1
+		if size, ok := sizes[volumes[i].Name]; ok {
+			volumes[i].Size = size
+		}
+	}
+	mounts, err := r.containerMounts(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	attachVolumeUsers(volumes, containers, mounts)
+	sortVolumes(volumes)
+	return volumes, nil
+}
+
+// a line of `docker volume ls --format {{json .}}`
+type dockerVolumeEntry struct {
+	Name       string `json:"Name"`
+	Driver     string `json:"Driver"`
+	Mountpoint string `json:"Mountpoint"`
+	Labels     string `json:"Labels"`
+}
+
+func (r *DockerCLI) ListVolumes() ([]Volume, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "volume", "ls", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+
+	var out []Volume
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e dockerVolumeEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("parsing docker output: %w", err)
+		}
+		out = append(out, Volume{Name: e.Name, Driver: e.Driver, Mountpoint: e.Mountpoint, Labels: parseLabels(e.Labels)})
+	}
+
+	containers, err := r.ps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.finishVolumes(ctx, out, containers)
+}
+
+// PruneVolumes needs --all on docker >= 23 to include named volumes, older
+// versions don't know the flag and prune everything unused anyway
+func (r *DockerCLI) PruneVolumes() (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := r.command(ctx, "volume", "prune", "--all", "--force").Output()
+	if err != nil && strings.Contains(cliError(err).Error(), "unknown flag") {
+		output, err = r.command(ctx, "volume", "prune", "--force").Output()
+	}
+	if err != nil {
+		return PruneReport{}, cliError(err)
+	}
+	return parseVolumePruneOutput(string(output)), nil
+}
+
+// an entry of `podman volume ls --format json`
+type podmanVolumeEntry struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Mountpoint string            `json:"Mountpoint"`
+	CreatedAt  string            `json:"CreatedAt"`
+	Labels     map[string]string `json:"Labels"`
+}
+
+func (r *PodmanCLI) ListVolumes() ([]Volume, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := r.command(ctx, "volume", "ls", "--format", "json").Output()
+	if err != nil {
+		return nil, cliError(err)
+	}
+	var entries []podmanVolumeEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("parsing podman output: %w", err)
+	}
+
+	out := make([]Volume, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, Volume{Name: e.Name, Driver: e.Driver, Mountpoint: e.Mountpoint,
+			Created: parseTime(e.CreatedAt), Labels: e.Labels})
+	}
+
+	containers, err := r.ps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.finishVolumes(ctx, out, containers)
+}
+
+// PruneVolumes on podman always includes named volumes
+func (r *PodmanCLI) PruneVolumes() (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := r.command(ctx, "volume", "prune", "--force").Output()
+	if err != nil {
+		return PruneReport{}, cliError(err)
+	}
+	return parseVolumePruneOutput(string(output)), nil
+}
+
+func (r cliRuntime) RemoveVolume(name string, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := []string{"volume", "rm"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := r.command(ctx, append(args, name)...).Output()
+	return cliError(err)
+}
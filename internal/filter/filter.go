@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Container filter query
+// ============================================================================
+//
+// a query is space separated terms and a container has to match all of them:
+//
+//	web                 fuzzy match on name or image
+//	state:running       field contains the value (state, name, image, id,
+//	                    project, service, port, host)
+//	health:unhealthy    healthcheck state (healthy, unhealthy, starting,
+//	                    none for containers without a healthcheck)
+//	label:team          container has the label
+//	label:team=payments label has exactly this value
+//	cpu>50  mem<=10     compare the cpu/memory percentage (> >= < <= =)
+//	-state:exited       a leading - negates any term
+
+// Query is a parsed filter, the zero value matches everything
+type Query struct {
+	raw   string
+	terms []term
+}
+
+type term struct {
+	negate bool
+	key    string // empty for fuzzy terms
+	op     string // comparison for cpu/mem, ":" otherwise
+	value  string
+	num    float64
+}
+
+// fields that take key:value
+var textKeys = map[string]bool{
+	"state": true, "name": true, "image": true, "id": true,
+	"project": true, "service": true, "port": true, "label": true,
+	"host": true, "health": true,
+}
+
+// fields that take a comparison
+var numericKeys = map[string]bool{"cpu": true, "mem": true}
+
+// Parse reads a query, unknown keys and bad numbers are errors
+func Parse(s string) (Query, error) {
+	q := Query{raw: strings.TrimSpace(s)}
+	for _, word := range strings.Fields(s) {
+		t := term{op: ":"}
+		if strings.HasPrefix(word, "-") && len(word) > 1 {
+			t.negate = true
+			word = word[1:]
+		}
+
+		if key, value, ok := strings.Cut(word, ":"); ok {
+			key = strings.ToLower(key)
+			if !textKeys[key] {
+				return Query{}, fmt.Errorf("unknown filter %q (try state, name, image, id, project, service, port, host, health, label, cpu, mem)", key)
+			}
+			if value == "" {
+				return Query{}, fmt.Errorf("%s: needs a value", key)
+			}
+			t.key, t.value = key, value
+			q.terms = append(q.terms, t)
+			continue
+		}
+
+		if key, op, value, ok := cutComparison(word); ok {
+			key = strings.ToLower(key)
+			if !numericKeys[key] {
+				return Query{}, fmt.Errorf("can't compare %q, only cpu and mem", key)
+			}
+			num, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+			if err != nil {
+				return Query{}, fmt.Errorf("%s%s: %q isn't a number", key, op, value)
+			}
+			t.key, t.op, t.value, t.num = key, op, value, num
+			q.terms = append(q.terms, t)
+			continue
+		}
+
+		t.value = word
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// cutComparison splits "cpu>=50" into cpu, >=, 50
+func cutComparison(word string) (key, op, value string, ok bool) {
+	i := strings.IndexAny(word, "<>=")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	op = word[i : i+1]
+	if i+1 < len(word) && word[i+1] == '=' && op != "=" {
+		op += "="
+	}
+	return word[:i], op, word[i+len(op):], true
+}
+
+// String is the query as typed
+func (q Query) String() string {
+	return q.raw
+}
+
+// Empty is true when the query matches everything
+func (q Query) Empty() bool {
+	return len(q.terms) == 0
+}
+
+// UsesStats is true when a term compares cpu or mem, the container list
+// doesn't carry stats so they have to be fetched first
+func (q Query) UsesStats() bool {
+	for _, t := range q.terms {
+		if numericKeys[t.key] {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the container matches every term
+func (q Query) Match(c docker.Container) bool {
+	for _, t := range q.terms {
+		if t.match(c) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t term) match(c docker.Container) bool {
+	name := ""
+	if len(c.Names) > 0 {
+		name = strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	switch t.key {
+	case "":
+		return Fuzzy(t.value, name) || Fuzzy(t.value, c.Image)
+	case "state":
+		return contains(c.State, t.value)
+	case "name":
+		return contains(name, t.value)
+	case "image":
+		return contains(c.Image, t.value)
+	case "id":
+		return strings.HasPrefix(strings.ToLower(c.ID), strings.ToLower(t.value))
+	case "project":
+		return contains(c.ComposeProject, t.value)
+	case "service":
+		return contains(c.ComposeService, t.value)
+	case "port":
+		return contains(c.Ports, t.value)
+	case "host":
+		return contains(c.Host, t.value)
+	case "health":
+		if strings.EqualFold(t.value, "none") {
+			return c.Health == docker.HealthNone
+		}
+		return c.Health != docker.HealthNone && strings.HasPrefix(string(c.Health), strings.ToLower(t.value))
+	case "label":
+		key, value, hasValue := strings.Cut(t.value, "=")
+		got, ok := c.Labels[key]
+		return ok && (!hasValue || got == value)
+	case "cpu":
+		return compare(cpuPercent(c), t.op, t.num)
+	case "mem":
+		return compare(memPercent(c), t.op, t.num)
+	}
+	return false
+}
+
+func contains(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
+
+// containers without stats count as 0
+func cpuPercent(c docker.Container) float64 {
+	if c.Stats == nil {
+		return 0
+	}
+	return c.Stats.CPU * 100
+}
+
+func memPercent(c docker.Container) float64 {
+	if c.Stats == nil {
+		return 0
+	}
+	return c.Stats.MemPercent()
+}
+
+func compare(v float64, op string, want float64) bool {
+	switch op {
+	case ">":
+		return v > want
+	case ">=":
+		return v >= want
+	case "<":
+		return v < want
+	case "<=":
+		return v <= want
+	}
+	return v == want
+}
+
+// Fuzzy is true when the letters of pattern appear in s in order, ignoring case
+func Fuzzy(pattern, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
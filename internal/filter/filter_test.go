@@ -0,0 +1,87 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	web = docker.Container{
+		ID: "a1b2c3d4e5f6", Names: []string{"/shop-web-1"}, Image: "nginx:1.27", State: "running", Health: docker.HealthUnhealthy,
+		Stats: &docker.ContainerStats{CPU: 0.625, MemUsed: 31, MemLimit: 1000}, Ports: "0.0.0.0:8080->80/tcp",
+		Labels:         map[string]string{"team": "storefront"},
+		ComposeProject: "shop", ComposeService: "web",
+	}
+	db = docker.Container{
+		ID: "0f9e8d7c6b5a", Names: []string{"/shop-db-1"}, Image: "postgres:16", State: "running", Health: docker.HealthHealthy,
+		Stats: &docker.ContainerStats{CPU: 0.04, MemUsed: 120, MemLimit: 1000}, Ports: "5432/tcp",
+		Labels:         map[string]string{"team": "payments"},
+		ComposeProject: "shop", ComposeService: "db",
+	}
+	job = docker.Container{
+		ID: "112233445566", Names: []string{"/old-job"}, Image: "busybox", State: "exited",
+	}
+)
+
+func matching(t *testing.T, query string) []string {
+	t.Helper()
+	q, err := Parse(query)
+	require.NoError(t, err)
+	var names []string
+	for _, c := range []docker.Container{web, db, job} {
+		if q.Match(c) {
+			names = append(names, c.Names[0][1:])
+		}
+	}
+	return names
+}
+
+func TestQueryMatch(t *testing.T) {
+	all := []string{"shop-web-1", "shop-db-1", "old-job"}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", all},
+		{"shpweb", []string{"shop-web-1"}},
+		{"PG", []string{"shop-db-1"}},
+		{"zz", nil},
+		{"postgres", []string{"shop-db-1"}},
+		{"state:running", []string{"shop-web-1", "shop-db-1"}},
+		{"-state:running", []string{"old-job"}},
+		{"image:postgres", []string{"shop-db-1"}},
+		{"project:shop service:db", []string{"shop-db-1"}},
+		{"port:5432", []string{"shop-db-1"}},
+		{"label:team", []string{"shop-web-1", "shop-db-1"}},
+		{"label:team=payments", []string{"shop-db-1"}},
+		{"id:112", []string{"old-job"}},
+		{"health:unhealthy", []string{"shop-web-1"}},
+		{"health:HEALTHY", []string{"shop-db-1"}},
+		{"health:none", []string{"old-job"}},
+		{"-health:none", []string{"shop-web-1", "shop-db-1"}},
+		{"cpu>50", []string{"shop-web-1"}},
+		{"cpu<=4", []string{"shop-db-1", "old-job"}},
+		{"mem>=12%", []string{"shop-db-1"}},
+		{"shop cpu>50", []string{"shop-web-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, matching(t, tt.query))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{"colour:red", "state:", "name>3", "cpu>lots"} {
+		_, err := Parse(query)
+		assert.Error(t, err, query)
+	}
+
+	q, err := Parse("  state:running  ")
+	require.NoError(t, err)
+	assert.Equal(t, "state:running", q.String())
+	assert.True(t, Query{}.Empty())
+}
@@ -0,0 +1,267 @@
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Key bindings
+// ============================================================================
+//
+// every action has a name, the one used under `keys:` in the config:
+//
+//	keys:
+//	  preset: vim         # default, vim, emacs or htop
+//	  stop: b             # one key
+//	  logs: [l, ctrl+l]   # or several
+//
+// keys are bubbletea key names: "a", "A", "ctrl+x", "alt+v", "f5", "enter",
+// "esc", "tab", "up", "pgdown", " " for space. the views (images, volumes,
+// networks, inspect, settings, the dialogs) and the logs panel read their
+// keys from here too, an action like up or back is read in several of them.
+
+// Scope is where an action's keys are read. two actions sharing a scope
+// can't share a key, one of them would never fire
+type Scope int
+
+const (
+	Main     Scope = 1 << iota // container list and compose view
+	Columns                    // column mode (tab)
+	Project                    // a compose project row, read before Main
+	Logs                       // the logs panel with focus, only these are read then
+	Images                     // images view
+	Volumes                    // volumes view
+	Networks                   // networks view
+	Inspect                    // inspect view
+	Settings                   // settings screen
+	Dialog                     // confirm and action dialogs, letters are text while typing
+)
+
+// the views that list something and share their navigation keys
+const views = Images | Volumes | Networks | Inspect
+
+// Action is something a key can be bound to
+type Action struct {
+	Name   string   // key under `keys:` in the config
+	Keys   []string // default bindings
+	Scopes Scope
+	Help   string // line on the help screen
+}
+
+// Actions in the order the help screen lists them
+var Actions = []Action{
+	{"up", []string{"up", "k"}, Main | Logs | views | Settings, "Move cursor up (logs: scroll up)"},
+	{"down", []string{"down", "j"}, Main | Logs | views | Settings, "Move cursor down (logs: scroll down)"},
+	{"prev_page", []string{"left", "pgup"}, Main | Images, "Previous page"},
+	{"next_page", []string{"right", "pgdown"}, Main | Images, "Next page"},
+	{"column_mode", []string{"tab"}, Main | Columns | Logs, "Toggle column selection mode"},
+	{"column_left", []string{"left", "h"}, Columns, "Column mode: select the column to the left"},
+	{"column_right", []string{"right"}, Columns, "Column mode: select the column to the right"},
+	{"select", []string{"enter"}, Main | Columns | Project | views | Dialog, "Sort by the selected column (column mode), expand/collapse a compose project, open the row in the views, confirm a dialog"},
+	{"start", []string{"s", "S"}, Main | Project | Logs, "Start selected container (compose project row: up -d)"},
+	{"stop", []string{"x", "X"}, Main | Project | Logs, "Stop selected container (compose project row: stop)"},
+	{"restart", []string{"r", "R"}, Main | Project | Logs, "Restart selected container (compose project row: restart)"},
+	{"pause", []string{"z", "Z"}, Main | Logs, "Pause/unpause selected container"},
+	{"kill", []string{"K"}, Main | Logs, "Kill selected container with a signal (SIGTERM, SIGKILL, SIGHUP, SIGUSR1...)"},
+	{"stop_wait", []string{"t", "T"}, Main, "Stop selected container with a custom timeout before SIGKILL"},
+	{"rename", []string{"N"}, Main, "Rename selected container"},
+	{"remove", []string{"d", "D"}, Main | Project | Logs, "Remove selected container (confirm dialog: stop first, force, remove volumes), compose project row: down"},
+	{"pull", []string{"p", "P"}, Project, "Compose project row: pull"},
+	{"recreate", []string{"u", "U"}, Project, "Compose project row: up --force-recreate"},
+	{"mark", []string{" ", "v", "V"}, Main | Logs, "Mark row for a bulk action (a project row marks all its containers)"},
+	{"mark_all", []string{"a", "A"}, Main | Logs, "Mark every listed container, again to clear"},
+	{"exec", []string{"e", "E"}, Main | Logs, "Open interactive shell"},
+	{"logs", []string{"l", "L"}, Main | Columns | Logs, "View/Toggle container logs"},
+	{"info", []string{"i", "I"}, Main | Logs, "View/Toggle container info"},
+	{"inspect", []string{"o", "O"}, Main | Logs | Images | Inspect, "Inspect container (env, mounts, networks, health...), images: inspect the image, inspect view: close it"},
+	{"filter", []string{"/"}, Main, "Filter: fuzzy name/image, state: image: project: port: label:k=v cpu>50 mem<10, -term negates"},
+	{"compose_view", []string{"c", "C"}, Main | Logs, "Toggle compose/normal view"},
+	{"host", []string{"H"}, Main | Logs, "Switch host: local, a configured context/host/connection, or all of them with a HOST column"},
+	{"refresh", []string{"f5"}, Main | Logs, "Reload the container list"},
+	{"settings", []string{"f2"}, Main | Columns | Logs | Settings, "Open/close settings"},
+	{"images", []string{"f3"}, Main | Columns | Logs | Images, "Images view, again to close it"},
+	{"volumes", []string{"f4"}, Main | Columns | Logs | Volumes, "Volumes view, again to close it"},
+	{"networks", []string{"f6"}, Main | Columns | Logs | Networks, "Networks view, again to close it"},
+	{"help", []string{"f1", "?"}, Main | Columns | Logs, "Show this help"},
+	{"debug", []string{"`"}, Main | Columns | Logs, "Write a state snapshot to the debug log"},
+	{"back", []string{"esc"}, Main | Columns | Project | Logs | views | Settings | Dialog, "Back/Cancel, clears the marks, then the filter"},
+	{"quit", []string{"q", "Q", "ctrl+c", "f10"}, Main | Columns | Logs | views | Settings, "Quit application"},
+
+	{"page_up", []string{"pgup"}, Logs | Volumes | Networks | Inspect, "Logs and views: page up (logs: pauses auto-scroll)"},
+	{"page_down", []string{"pgdown"}, Logs | Volumes | Networks | Inspect, "Logs and views: page down"},
+	{"top", []string{"home", "g"}, Logs | views, "Logs and views: jump to the top"},
+	{"bottom", []string{"end", "G"}, Logs | views, "Logs and views: jump to the bottom"},
+	{"follow", []string{"f"}, Logs, "Logs: follow new lines"},
+	{"timestamps", []string{"t"}, Logs, "Logs: show/hide timestamps"},
+	{"window", []string{"w"}, Logs, "Logs: time window (since/until)"},
+	{"search", []string{"/"}, Logs, "Logs: search"},
+	{"next_match", []string{"n"}, Logs, "Logs: next search match"},
+	{"prev_match", []string{"N"}, Logs, "Logs: previous search match"},
+	{"expand", []string{"right", "l"}, Volumes | Networks | Inspect, "Views: expand the row"},
+	{"collapse", []string{"left", "h"}, Volumes | Networks | Inspect, "Views: collapse the row (inspect: go up to the parent)"},
+	{"toggle", []string{" "}, Volumes | Networks | Inspect | Settings | Dialog, "Views: expand/collapse the row, settings: show/hide the column, dialogs: check/uncheck the option"},
+	{"reload", []string{"r", "R", "f5"}, views, "Views: load the list again"},
+	{"pull_image", []string{"p"}, Images, "Images: pull the selected image again"},
+	{"pull_new", []string{"P"}, Images, "Images: pull an image by name"},
+	{"cancel_pull", []string{"x", "X"}, Images, "Images: cancel the running pull"},
+	{"delete", []string{"d"}, Images | Volumes, "Images/volumes: remove the selected one (asks first)"},
+	{"prune", []string{"D"}, Images | Volumes, "Images/volumes: prune dangling images / unused volumes"},
+	{"connect", []string{"c", "C"}, Networks, "Networks: connect a container"},
+	{"disconnect", []string{"d", "D", "x", "X"}, Networks, "Networks: disconnect the selected container"},
+	{"reveal", []string{"v", "V"}, Inspect, "Inspect: show/hide a secret value"},
+	{"move_up", []string{"K", "shift+up"}, Settings, "Settings: move the column up (left on screen)"},
+	{"move_down", []string{"J", "shift+down"}, Settings, "Settings: move the column down (right on screen)"},
+	{"decrease", []string{"left", "h", "-"}, Settings, "Settings: decrease the value, previous option"},
+	{"increase", []string{"right", "l", "+"}, Settings, "Settings: increase the value, next option"},
+	{"save", []string{"s", "S"}, Settings, "Settings: save to config.yml"},
+	{"option_up", []string{"up", "k", "shift+tab"}, Dialog, "Dialogs: previous option"},
+	{"option_down", []string{"down", "j", "tab"}, Dialog, "Dialogs: next option"},
+	{"confirm", []string{"y", "Y"}, Dialog | Images | Volumes | Networks, "Dialogs and view prompts: yes"},
+	{"cancel", []string{"n", "N", "q"}, Dialog, "Dialogs: no"},
+}
+
+// Presets change some defaults, user overrides go on top
+var Presets = map[string]map[string][]string{
+	"default": {},
+	// hjkl are the defaults already, this adds the paging keys
+	"vim": {
+		"prev_page": {"left", "pgup", "ctrl+b", "ctrl+u"},
+		"next_page": {"right", "pgdown", "ctrl+f", "ctrl+d"},
+		"page_up":   {"pgup", "ctrl+b", "ctrl+u"},
+		"page_down": {"pgdown", "ctrl+f", "ctrl+d"},
+	},
+	"emacs": {
+		"up":           {"up", "ctrl+p"},
+		"down":         {"down", "ctrl+n"},
+		"prev_page":    {"left", "pgup", "alt+v"},
+		"next_page":    {"right", "pgdown", "ctrl+v"},
+		"page_up":      {"pgup", "alt+v"},
+		"page_down":    {"pgdown", "ctrl+v"},
+		"option_up":    {"up", "ctrl+p", "shift+tab"},
+		"option_down":  {"down", "ctrl+n", "tab"},
+		"column_left":  {"left", "ctrl+b"},
+		"column_right": {"right", "ctrl+f"},
+		"filter":       {"/", "ctrl+s"},
+		"back":         {"esc", "ctrl+g"},
+	},
+	// the function keys as htop has them: F3/F4 search and filter, F5 tree
+	// (the compose view), F6 sort, F9 kill. the views move to F7, F8, F12
+	"htop": {
+		"up":           {"up"},
+		"down":         {"down"},
+		"kill":         {"K", "k", "f9"},
+		"filter":       {"/", "\\", "f3", "f4"},
+		"compose_view": {"c", "C", "f5"},
+		"column_mode":  {"tab", "f6", ">"},
+		"refresh":      {"ctrl+l"},
+		"images":       {"f7"},
+		"volumes":      {"f8"},
+		"networks":     {"f12"},
+	},
+}
+
+// PresetNames lists the presets, sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Problem is a binding that couldn't be used, Action is the config key
+// ("preset" for the preset)
+type Problem struct {
+	Action  string
+	Message string
+}
+
+// Resolve works out the keys of every action for a preset and the user's
+// overrides. a bad override is reported and dropped, the action keeps the
+// preset's keys
+func Resolve(preset string, overrides map[string][]string) (map[string][]string, []Problem) {
+	var problems []Problem
+	if preset == "" {
+		preset = "default"
+	}
+	presetKeys, ok := Presets[preset]
+	if !ok {
+		problems = append(problems, Problem{"preset", fmt.Sprintf("unknown preset %q, use %s", preset, strings.Join(PresetNames(), ", "))})
+	}
+
+	base := make(map[string][]string, len(Actions))
+	for _, a := range Actions {
+		base[a.Name] = a.Keys
+		if keys, ok := presetKeys[a.Name]; ok {
+			base[a.Name] = keys
+		}
+	}
+
+	// sorted so the problems come out the same every time
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make(map[string][]string, len(base))
+	for name, k := range base {
+		keys[name] = k
+	}
+	overridden := map[string]bool{}
+	for _, name := range names {
+		if _, ok := base[name]; !ok {
+			problems = append(problems, Problem{name, "unknown action"})
+			continue
+		}
+		if len(overrides[name]) == 0 || slices.Contains(overrides[name], "") {
+			problems = append(problems, Problem{name, "needs at least one key, and no empty ones"})
+			continue
+		}
+		keys[name] = overrides[name]
+		overridden[name] = true
+	}
+
+	// an override that clashes goes, the preset never clashes with itself
+	for _, c := range conflicts(keys) {
+		for _, name := range []string{c.a, c.b} {
+			if overridden[name] {
+				other := c.b
+				if name == c.b {
+					other = c.a
+				}
+				problems = append(problems, Problem{name, fmt.Sprintf("%q is %s's key too", c.key, other)})
+				keys[name] = base[name]
+				delete(overridden, name)
+			}
+		}
+	}
+	return keys, problems
+}
+
+type conflict struct {
+	key  string
+	a, b string // actions, in Actions order
+}
+
+// conflicts finds keys bound to two actions that are read in the same scope
+func conflicts(keys map[string][]string) []conflict {
+	var found []conflict
+	for i, a := range Actions {
+		for _, b := range Actions[i+1:] {
+			if a.Scopes&b.Scopes == 0 {
+				continue
+			}
+			for _, k := range keys[a.Name] {
+				if slices.Contains(keys[b.Name], k) {
+					found = append(found, conflict{k, a.Name, b.Name})
+				}
+			}
+		}
+	}
+	return found
+}
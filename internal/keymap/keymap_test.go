@@ -0,0 +1,70 @@
+package keymap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for _, name := range PresetNames() {
+		keys, problems := Resolve(name, nil)
+		assert.Empty(t, problems, name)
+		assert.Empty(t, conflicts(keys), name)
+		for action := range Presets[name] {
+			_, ok := keys[action]
+			assert.True(t, ok, "%s: unknown action %s", name, action)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	keys, problems := Resolve("", map[string][]string{
+		"stop": {"b"},
+		"logs": {"l", "ctrl+l"},
+	})
+	assert.Empty(t, problems)
+	assert.Equal(t, []string{"b"}, keys["stop"])
+	assert.Equal(t, []string{"l", "ctrl+l"}, keys["logs"])
+	assert.Equal(t, []string{"s", "S"}, keys["start"])
+
+	keys, problems = Resolve("vim", nil)
+	assert.Empty(t, problems)
+	assert.Contains(t, keys["next_page"], "ctrl+f")
+
+	// same scope clashes, the override is dropped
+	keys, problems = Resolve("default", map[string][]string{"logs": {"s"}})
+	assert.Equal(t, []Problem{{"logs", `"s" is start's key too`}}, problems)
+	assert.Equal(t, []string{"l", "L"}, keys["logs"])
+
+	// different scopes don't: u recreates on a project row and pages elsewhere
+	_, problems = Resolve("default", map[string][]string{"next_page": {"u"}})
+	assert.Empty(t, problems)
+
+	// project rows read start/stop too
+	_, problems = Resolve("default", map[string][]string{"pull": {"x"}})
+	assert.Equal(t, []Problem{{"pull", `"x" is stop's key too`}}, problems)
+
+	// the logs panel reads start, its own keys can't take it
+	_, problems = Resolve("default", map[string][]string{"follow": {"s"}})
+	assert.Equal(t, []Problem{{"follow", `"s" is start's key too`}}, problems)
+
+	// the views too, and the same key can mean different things in two views
+	_, problems = Resolve("default", map[string][]string{"reveal": {"r"}})
+	assert.Equal(t, []Problem{{"reveal", `"r" is reload's key too`}}, problems)
+	_, problems = Resolve("default", map[string][]string{"connect": {"p"}})
+	assert.Empty(t, problems)
+
+	// two clashing overrides both go
+	keys, problems = Resolve("default", map[string][]string{"info": {"F"}, "exec": {"F"}})
+	assert.Len(t, problems, 2)
+	assert.Equal(t, []string{"e", "E"}, keys["exec"])
+	assert.Equal(t, []string{"i", "I"}, keys["info"])
+
+	_, problems = Resolve("nano", map[string][]string{"fly": {"f"}, "stop": {}})
+	assert.Equal(t, []Problem{
+		{"preset", `unknown preset "nano", use default, emacs, htop, vim`},
+		{"fly", "unknown action"},
+		{"stop", "needs at least one key, and no empty ones"},
+	}, problems)
+}
@@ -0,0 +1,189 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Color themes
+// ============================================================================
+//
+// the TUI paints everything with a handful of roles. a theme gives each role
+// a color, the `theme:` section of the config picks one and can override
+// single roles:
+//
+//	theme:
+//	  name: light        # dark, light, high-contrast, solarized or monochrome
+//	  accent: "#0e7490"  # hex
+//	  stopped: "9"       # or an ANSI color number, 0-255
+//	  border: ""         # or nothing, the terminal's own color
+
+// Color is a role's color on 256 color and truecolor terminals, and on 16
+// color ones. lipgloss rounds True to the nearest color when it has to, but
+// the nearest of 16 is often unreadable so the themes pick Basic by hand.
+// empty is no color at all
+type Color struct {
+	True  string
+	Basic string
+}
+
+// Role is a color slot of the TUI
+type Role struct {
+	Name string // key under `theme:` in the config
+	Used string // what it paints
+}
+
+// Roles in the order the README lists them
+var Roles = []Role{
+	{"accent", "titles and compose project names"},
+	{"text", "values, footer descriptions"},
+	{"subtle", "labels and the container rows"},
+	{"muted", "meter brackets and empty meter bars"},
+	{"border", "dividers"},
+	{"warning", "status messages, paused containers, marked rows, log matches"},
+	{"selected", "the cursor row, footer keys, meter labels"},
+	{"running", "running containers, the table header, the running meter"},
+	{"stopped", "stopped containers, stderr lines, dialogs, the stopped meter"},
+	{"unhealthy", "running containers failing their healthcheck"},
+	{"inverse", "text on the colored bars: header, cursor row, footer keys"},
+	{"column", "the highlighted column in column mode"},
+}
+
+// Palette is a color for every role
+type Palette map[string]Color
+
+// Names are the built-in themes, the order settings cycles through them
+var Names = []string{"dark", "light", "high-contrast", "solarized", "monochrome"}
+
+var palettes = map[string]Palette{
+	"dark": {
+		"accent":    {"#22D3EE", "14"},
+		"text":      {"#F8FAFC", "15"},
+		"subtle":    {"#94A3B8", "7"},
+		"muted":     {"#475569", "8"},
+		"border":    {"#334155", "8"},
+		"warning":   {"#F59E0B", "11"},
+		"selected":  {"#06B6D4", "6"},
+		"running":   {"#4ADE80", "10"},
+		"stopped":   {"#F87171", "9"},
+		"unhealthy": {"#FB923C", "13"},
+		"inverse":   {"#000000", "0"},
+		"column":    {"#58CDFF", "12"},
+	},
+	// darker shades of the same hues, for white and light grey backgrounds
+	"light": {
+		"accent":    {"#0E7490", "6"},
+		"text":      {"#0F172A", "0"},
+		"subtle":    {"#475569", "8"},
+		"muted":     {"#94A3B8", "7"},
+		"border":    {"#CBD5E1", "7"},
+		"warning":   {"#B45309", "3"},
+		"selected":  {"#0891B2", "6"},
+		"running":   {"#15803D", "2"},
+		"stopped":   {"#B91C1C", "1"},
+		"unhealthy": {"#C2410C", "5"},
+		"inverse":   {"#FFFFFF", "15"},
+		"column":    {"#0284C7", "4"},
+	},
+	"high-contrast": {
+		"accent":    {"#FFFF00", "11"},
+		"text":      {"#FFFFFF", "15"},
+		"subtle":    {"#FFFFFF", "15"},
+		"muted":     {"#C0C0C0", "7"},
+		"border":    {"#FFFFFF", "15"},
+		"warning":   {"#FFFF00", "11"},
+		"selected":  {"#00FFFF", "14"},
+		"running":   {"#00FF00", "10"},
+		"stopped":   {"#FF4040", "9"},
+		"unhealthy": {"#FF8C00", "13"},
+		"inverse":   {"#000000", "0"},
+		"column":    {"#FF00FF", "13"},
+	},
+	// solarized dark. the Basic numbers are where solarized terminal schemes
+	// put the base tones, 8-15
+	"solarized": {
+		"accent":    {"#268BD2", "4"},
+		"text":      {"#93A1A1", "14"},
+		"subtle":    {"#839496", "12"},
+		"muted":     {"#586E75", "10"},
+		"border":    {"#073642", "0"},
+		"warning":   {"#B58900", "3"},
+		"selected":  {"#2AA198", "6"},
+		"running":   {"#859900", "2"},
+		"stopped":   {"#DC322F", "1"},
+		"unhealthy": {"#CB4B16", "5"},
+		"inverse":   {"#002B36", "8"},
+		"column":    {"#6C71C4", "13"},
+	},
+	// no colors, the TUI falls back to bold, underline and reverse video
+	"monochrome": {},
+}
+
+// Problem is a theme setting that couldn't be used, Role is the config key
+// ("name" for the theme name)
+type Problem struct {
+	Role    string
+	Message string
+}
+
+// Resolve gives the palette for a theme name with the user's overrides on
+// top. an unknown name is reported and gets the dark theme, a bad override is
+// reported and dropped
+func Resolve(name string, overrides map[string]string) (Palette, []Problem) {
+	var problems []Problem
+	if name == "" {
+		name = Names[0]
+	}
+	base, ok := palettes[name]
+	if !ok {
+		problems = append(problems, Problem{"name", fmt.Sprintf("unknown theme %q, use %s", name, strings.Join(Names, ", "))})
+		base = palettes[Names[0]]
+	}
+
+	p := make(Palette, len(Roles))
+	for _, r := range Roles {
+		p[r.Name] = base[r.Name]
+	}
+
+	// sorted so the problems come out the same every time
+	roles := make([]string, 0, len(overrides))
+	for role := range overrides {
+		roles = append(roles, role)
+	}
+	slices.Sort(roles)
+	for _, role := range roles {
+		color := overrides[role]
+		switch {
+		case !slices.ContainsFunc(Roles, func(r Role) bool { return r.Name == role }):
+			problems = append(problems, Problem{role, "unknown role, use " + roleNames()})
+		case !ValidColor(color):
+			problems = append(problems, Problem{role, fmt.Sprintf("%q isn't a color, use #rrggbb, #rgb or 0-255", color)})
+		default:
+			p[role] = Color{color, color}
+		}
+	}
+	return p, problems
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ValidColor accepts hex colors, ANSI color numbers and "" for no color
+func ValidColor(s string) bool {
+	if s == "" || hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func roleNames() string {
+	names := make([]string, len(Roles))
+	for i, r := range Roles {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,43 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemesColorEveryRole(t *testing.T) {
+	for _, name := range Names {
+		p, problems := Resolve(name, nil)
+		assert.Empty(t, problems, name)
+		for _, r := range Roles {
+			c := p[r.Name]
+			assert.True(t, ValidColor(c.True) && ValidColor(c.Basic), "%s: %s", name, r.Name)
+			if name != "monochrome" {
+				assert.NotEmpty(t, c.True, "%s: %s", name, r.Name)
+				assert.NotEmpty(t, c.Basic, "%s: %s", name, r.Name)
+			}
+		}
+	}
+	assert.Len(t, palettes, len(Names))
+}
+
+func TestResolve(t *testing.T) {
+	p, problems := Resolve("", map[string]string{"accent": "#0e7490", "stopped": "9", "border": ""})
+	assert.Empty(t, problems)
+	assert.Equal(t, Color{"#0e7490", "#0e7490"}, p["accent"])
+	assert.Equal(t, Color{"9", "9"}, p["stopped"])
+	assert.Equal(t, Color{}, p["border"])
+	assert.Equal(t, Color{"#F8FAFC", "15"}, p["text"])
+
+	p, problems = Resolve("paper", map[string]string{"accent": "blue", "glow": "#fff", "text": "256"})
+	assert.Equal(t, []Problem{
+		{"name", `unknown theme "paper", use dark, light, high-contrast, solarized, monochrome`},
+		{"accent", `"blue" isn't a color, use #rrggbb, #rgb or 0-255`},
+		{"glow", "unknown role, use accent, text, subtle, muted, border, warning, selected, running, stopped, unhealthy, inverse, column"},
+		{"text", `"256" isn't a color, use #rrggbb, #rgb or 0-255`},
+	}, problems)
+	// the dark theme, nothing overridden
+	assert.Equal(t, palettes["dark"]["accent"], p["accent"])
+	assert.Equal(t, palettes["dark"]["text"], p["text"])
+}
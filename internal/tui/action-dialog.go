@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Action dialog (kill with a signal, stop with a timeout, rename)
+// ============================================================================
+
+const defaultStopTimeout = 10 // seconds, what docker uses when the container doesn't set one
+
+// actionDialog asks for the one value kill, timed stop, rename and the host
+// switcher need before they run. it's a picker when choices is set, a text
+// input otherwise.
+type actionDialog struct {
+	title   string
+	targets []docker.Container
+	choices []string
+	focus   int
+	input   string
+	hint    string                                        // shown under the input
+	err     error                                         // why the last enter was refused
+	run     func(m *model, value string) (tea.Cmd, error) // picked choice or typed text
+}
+
+// handleActionDialogKey takes every key while the dialog is open
+func (m *model) handleActionDialogKey(msg tea.KeyMsg) tea.Cmd {
+	d := m.actionDialog
+
+	// letters are text in the input
+	if d.choices == nil && msg.Type == tea.KeyRunes {
+		d.input += string(msg.Runes)
+		d.err = nil
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.actionDialog = nil
+		m.statusMessage = "Cancelled"
+		return nil
+	case key.Matches(msg, m.keys.Select):
+		value := strings.TrimSpace(d.input)
+		if d.choices != nil {
+			value = d.choices[d.focus]
+		}
+		cmd, err := d.run(m, value)
+		if err != nil {
+			d.err = err
+			return nil
+		}
+		m.actionDialog = nil
+		return cmd
+	}
+
+	if d.choices != nil {
+		switch {
+		case key.Matches(msg, m.keys.OptionUp):
+			d.focus = (d.focus + len(d.choices) - 1) % len(d.choices)
+		case key.Matches(msg, m.keys.OptionDown):
+			d.focus = (d.focus + 1) % len(d.choices)
+		}
+		return nil
+	}
+
+	if msg.Type == tea.KeyBackspace && len(d.input) > 0 {
+		r := []rune(d.input)
+		d.input = string(r[:len(r)-1])
+	}
+	d.err = nil
+	return nil
+}
+
+// targetsLabel names what an action is about to hit
+func targetsLabel(targets []docker.Container) string {
+	if len(targets) == 1 {
+		return containerName(targets[0])
+	}
+	return fmt.Sprintf("%d containers", len(targets))
+}
+
+// openKillDialog picks the signal for `kill`
+func (m *model) openKillDialog() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.actionDialog = &actionDialog{
+		title:   "Send a signal to " + targetsLabel(targets),
+		targets: targets,
+		choices: docker.KillSignals,
+		run: func(m *model, signal string) (tea.Cmd, error) {
+			return m.act(targets, docker.ContainerAction{Kind: "kill", Signal: signal}), nil
+		},
+	}
+}
+
+// openStopTimeoutDialog asks how long `stop` waits before it kills
+func (m *model) openStopTimeoutDialog() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.actionDialog = &actionDialog{
+		title:   "Stop " + targetsLabel(targets),
+		targets: targets,
+		input:   strconv.Itoa(defaultStopTimeout),
+		hint:    "Seconds to wait for a clean exit before SIGKILL",
+		run: func(m *model, value string) (tea.Cmd, error) {
+			timeout, err := strconv.Atoi(value)
+			if err != nil || timeout < 0 {
+				return nil, fmt.Errorf("%q isn't a number of seconds", value)
+			}
+			return m.act(targets, docker.ContainerAction{Kind: "stop", Timeout: &timeout}), nil
+		},
+	}
+}
+
+// openRenameDialog renames the container under the cursor, marks don't apply
+func (m *model) openRenameDialog() {
+	c := m.selectedContainer()
+	if c == nil {
+		return
+	}
+	target, name := *c, containerName(*c)
+	m.actionDialog = &actionDialog{
+		title:   "Rename " + name,
+		targets: []docker.Container{target},
+		input:   name,
+		hint:    "Letters, digits and _.- only",
+		run: func(m *model, value string) (tea.Cmd, error) {
+			action := docker.ContainerAction{Kind: "rename", Name: value}
+			if err := action.Validate(); err != nil {
+				return nil, err
+			}
+			if value == name {
+				m.statusMessage = "Name unchanged"
+				return nil, nil
+			}
+			m.statusMessage = fmt.Sprintf("Renaming %s to %s...", name, value)
+			return doAction(m.rt, action, target.ID), nil
+		},
+	}
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderActionDialog() string {
+	d := m.actionDialog
+	inner := dialogWidth - 6 // border and padding
+
+	var b strings.Builder
+	b.WriteString(dialogTitleStyle.Render(truncateToWidth(d.title, inner)))
+	b.WriteString("\n\n")
+	if len(d.targets) > 1 {
+		names := make([]string, len(d.targets))
+		for i, c := range d.targets {
+			names[i] = containerName(c)
+		}
+		b.WriteString(infoLabelStyle.Render(fmt.Sprintf("%-13s", "Containers")) + " " +
+			infoValueStyle.Render(truncateToWidth(strings.Join(names, ", "), inner-14)))
+		b.WriteString("\n\n")
+	}
+
+	if d.choices != nil {
+		for i, choice := range d.choices {
+			line := padRight("  "+choice, inner)
+			if i == d.focus {
+				line = selectedStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString("> " + d.input + "_\n")
+		b.WriteString(footerDescStyle.Render(d.hint))
+		b.WriteString("\n")
+	}
+
+	if d.err != nil {
+		b.WriteString("\n")
+		b.WriteString(logStderrStyle.Render(truncateToWidth("✗ "+d.err.Error(), inner)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if d.choices != nil {
+		b.WriteString(footerDescStyle.Render(fmt.Sprintf("%s pick  %s send  %s cancel",
+			label(m.keys.OptionUp, m.keys.OptionDown), label(m.keys.Select), label(m.keys.Back))))
+	} else {
+		b.WriteString(footerDescStyle.Render(fmt.Sprintf("%s confirm  %s cancel", label(m.keys.Select), label(m.keys.Back))))
+	}
+	return dialogStyle.Width(dialogWidth - 2).Render(b.String())
+}
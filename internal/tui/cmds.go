@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,17 +13,17 @@ import (
 // ============================================================================
 
 // grab container list in background
-func fetchContainers() tea.Cmd {
+func fetchContainers(rt docker.Runtime) tea.Cmd {
 	return func() tea.Msg {
-		containers, err := docker.ListContainers()
+		containers, err := rt.ListContainers()
 		return docker.ContainersMsg{Containers: containers, Err: err}
 	}
 }
 
 // fetch compose projects asynchronously
-func fetchComposeProjects() tea.Cmd {
+func fetchComposeProjects(rt docker.Runtime) tea.Cmd {
 	return func() tea.Msg {
-		projects, err := docker.FetchComposeProjects()
+		projects, err := rt.FetchComposeProjects()
 		return composeProjectsMsg{Projects: projects, Err: err}
 	}
 }
@@ -38,17 +39,21 @@ func tickCmd(d time.Duration) tea.Cmd {
 }
 
 // run docker action in background (start/stop/etc)
-func doAction(action, containerID string) tea.Cmd {
+func doAction(rt docker.Runtime, action docker.ContainerAction, containerID string) tea.Cmd {
 	return func() tea.Msg {
-		err := docker.DoAction(action, containerID)
+		err := rt.DoAction(action, containerID)
 		return actionDoneMsg{err: err}
 	}
 }
 
-// fetch logs for a container
-func fetchLogsCmd(id string) tea.Cmd {
+// removeContainerCmd removes a container, stopping it gracefully first when asked
+func removeContainerCmd(rt docker.Runtime, containerID string, stopFirst bool, opts docker.RemoveOptions) tea.Cmd {
 	return func() tea.Msg {
-		lines, err := docker.GetLogs(id)
-		return docker.LogsMsg{ID: id, Lines: lines, Err: err}
+		if stopFirst {
+			if err := rt.DoAction(docker.Action("stop"), containerID); err != nil {
+				return actionDoneMsg{err: fmt.Errorf("stopping before remove: %w", err)}
+			}
+		}
+		return actionDoneMsg{err: rt.RemoveContainer(containerID, opts)}
 	}
 }
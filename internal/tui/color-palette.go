@@ -2,102 +2,227 @@ package tui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/theme"
 )
 
+// ============================================================================
+// Colors and styles
+// ============================================================================
+//
+// the colors come from the theme in the config (internal/theme has the
+// palettes), applyTheme sets them and rebuilds the styles. it runs when the
+// model is created and again on every config reload
+
 var (
-	// color palette
+	// color palette, the comment is the theme role
 	// main accents
-	accent = lipgloss.Color("#22D3EE")
+	accent lipgloss.TerminalColor // accent
 
 	// text colors
-	textPrimary   = lipgloss.Color("#F8FAFC") // near white
-	textSecondary = lipgloss.Color("#94A3B8")
-	textMuted     = lipgloss.Color("#475569")
+	textPrimary   lipgloss.TerminalColor // text
+	textSecondary lipgloss.TerminalColor // subtle
+	textMuted     lipgloss.TerminalColor // muted
 
 	// backgrounds
-	borderColor = lipgloss.Color("#334155")
+	borderColor lipgloss.TerminalColor // border
 
 	// status colors
-	yellowColor = lipgloss.Color("#F59E0B") // warnings/actions
-	cyanColor   = lipgloss.Color("#06B6D4") // selected
+	yellowColor lipgloss.TerminalColor // warning: warnings/actions
+	cyanColor   lipgloss.TerminalColor // selected
 
 	// others
-	meterGreen = lipgloss.Color("#4ADE80") // bright green for bars
-	meterRed   = lipgloss.Color("#F87171") // bright red for bars
+	meterGreen   lipgloss.TerminalColor // running: bars and running containers
+	meterRed     lipgloss.TerminalColor // stopped: bars and stopped containers
+	unhealthyRed lipgloss.TerminalColor // unhealthy: containers failing their healthcheck
+	inverseColor lipgloss.TerminalColor // inverse: text on the colored bars
+	columnColor  lipgloss.TerminalColor // column: selected column in column mode
+
+	titleStyle           lipgloss.Style
+	appNameStyle         lipgloss.Style
+	meterLabelStyle      lipgloss.Style
+	meterBracketStyle    lipgloss.Style
+	infoLabelStyle       lipgloss.Style
+	infoValueStyle       lipgloss.Style
+	headerStyle          lipgloss.Style
+	columnHighlightStyle lipgloss.Style
+	selectedStyle        lipgloss.Style
+	markedStyle          lipgloss.Style
+	runningStyle         lipgloss.Style
+	stoppedStyle         lipgloss.Style
+	pausedStyle          lipgloss.Style
+	unhealthyStyle       lipgloss.Style
+	normalStyle          lipgloss.Style
+	groupStyle           lipgloss.Style
+	logStderrStyle       lipgloss.Style
+	logMatchStyle        lipgloss.Style
+	logCurrentMatchStyle lipgloss.Style
+	footerKeyStyle       lipgloss.Style
+	footerDescStyle      lipgloss.Style
+	footerArrowStyle     lipgloss.Style
+	messageStyle         lipgloss.Style
+	dividerStyle         lipgloss.Style
+	dialogStyle          lipgloss.Style
+	dialogTitleStyle     lipgloss.Style
+)
+
+func init() {
+	applyTheme(config.ThemeConfig{})
+}
+
+// applyTheme switches the colors. bad names and roles are dropped here,
+// config.Validate is what reports them
+func applyTheme(cfg config.ThemeConfig) {
+	p, _ := theme.Resolve(cfg.Name, cfg.Colors)
+
+	// a 16 color terminal gets the colors the theme picked for it, lipgloss
+	// rounds the others down on its own
+	basic := lipgloss.ColorProfile() == termenv.ANSI
+	color := func(role string) lipgloss.TerminalColor {
+		c := p[role]
+		if basic {
+			c.True = c.Basic
+		}
+		if c.True == "" {
+			return lipgloss.NoColor{}
+		}
+		return lipgloss.Color(c.True)
+	}
+
+	accent = color("accent")
+	textPrimary = color("text")
+	textSecondary = color("subtle")
+	textMuted = color("muted")
+	borderColor = color("border")
+	yellowColor = color("warning")
+	cyanColor = color("selected")
+	meterGreen = color("running")
+	meterRed = color("stopped")
+	unhealthyRed = color("unhealthy")
+	inverseColor = color("inverse")
+	columnColor = color("column")
+
+	// bar is text on a colored background. with no color for it (the
+	// monochrome theme) it's reverse video, or the cursor row would vanish
+	bar := func(bg lipgloss.TerminalColor) lipgloss.Style {
+		if bg == (lipgloss.NoColor{}) {
+			return lipgloss.NewStyle().Reverse(true)
+		}
+		return lipgloss.NewStyle().Foreground(inverseColor).Background(bg)
+	}
 
 	// title style
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(accent).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(accent).
+		Padding(0, 1)
 
 	// app name
 	appNameStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(textPrimary)
+		Bold(true).
+		Foreground(textPrimary)
 
 	// meter styles
 	meterLabelStyle = lipgloss.NewStyle().
-			Foreground(cyanColor).
-			Bold(true)
+		Foreground(cyanColor).
+		Bold(true)
 
 	meterBracketStyle = lipgloss.NewStyle().
-				Foreground(textMuted)
+		Foreground(textMuted)
 
 	infoLabelStyle = lipgloss.NewStyle().
-			Foreground(textSecondary)
+		Foreground(textSecondary)
 
 	infoValueStyle = lipgloss.NewStyle().
-			Foreground(textPrimary).
-			Bold(true)
+		Foreground(textPrimary).
+		Bold(true)
 
-	// table header
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#000000")).
-			Background(meterGreen)
+	// table header, and its column picked in column mode
+	headerStyle = bar(meterGreen).Bold(true)
+	columnHighlightStyle = bar(columnColor).Bold(true)
+	if columnColor == (lipgloss.NoColor{}) {
+		// the header is reversed already
+		columnHighlightStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	}
 
 	// selected row
-	selectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#000000")).
-			Background(cyanColor)
+	selectedStyle = bar(cyanColor).Bold(true)
+
+	// marked row (multi-select)
+	markedStyle = bar(yellowColor).Bold(true)
+	if yellowColor == (lipgloss.NoColor{}) {
+		// reversed would look like the cursor
+		markedStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	}
 
 	// container states
 	runningStyle = lipgloss.NewStyle().
-			Foreground(meterGreen).
-			Bold(true)
+		Foreground(meterGreen).
+		Bold(true)
 
 	stoppedStyle = lipgloss.NewStyle().
-			Foreground(meterRed)
+		Foreground(meterRed)
 
 	pausedStyle = lipgloss.NewStyle().
-			Foreground(yellowColor)
+		Foreground(yellowColor)
+
+	unhealthyStyle = lipgloss.NewStyle().
+		Foreground(unhealthyRed).
+		Bold(true)
+	if unhealthyRed == (lipgloss.NoColor{}) {
+		// running rows are bold already
+		unhealthyStyle = unhealthyStyle.Italic(true)
+	}
 
 	normalStyle = lipgloss.NewStyle().
-			Foreground(textSecondary)
+		Foreground(textSecondary)
+
+	// compose project rows
+	groupStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(accent)
+
+	// logs
+	logStderrStyle = lipgloss.NewStyle().
+		Foreground(meterRed)
+
+	logMatchStyle = bar(yellowColor)
+	if yellowColor == (lipgloss.NoColor{}) {
+		logMatchStyle = lipgloss.NewStyle().Underline(true)
+	}
+
+	logCurrentMatchStyle = bar(cyanColor).Bold(true)
 
 	// footer
-	footerKeyStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#000000")).
-			Background(cyanColor).
-			Bold(true).
-			Padding(0, 0)
+	footerKeyStyle = bar(cyanColor).
+		Bold(true).
+		Padding(0, 0)
 
 	footerDescStyle = lipgloss.NewStyle().
-			Foreground(textPrimary).
-			Padding(0, 0)
+		Foreground(textPrimary).
+		Padding(0, 0)
 
 	footerArrowStyle = lipgloss.NewStyle().
-				Foreground(yellowColor).
-				Bold(true)
+		Foreground(yellowColor).
+		Bold(true)
 
 	// message
 	messageStyle = lipgloss.NewStyle().
-			Foreground(yellowColor).
-			Bold(true)
+		Foreground(yellowColor).
+		Bold(true)
 
 	// divider
 	dividerStyle = lipgloss.NewStyle().
-			Foreground(borderColor)
-)
+		Foreground(borderColor)
+
+	// confirm dialog
+	dialogStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(meterRed).
+		Padding(0, 2)
+
+	dialogTitleStyle = lipgloss.NewStyle().
+		Foreground(meterRed).
+		Bold(true)
+}
@@ -0,0 +1,519 @@
+package tui
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/columns"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Container list columns
+// ============================================================================
+//
+// internal/columns names the columns, this is how each one is drawn and
+// sorted. layout.columns in the config (Settings.Columns here) picks the ones
+// that show, their order and their share of the screen
+
+// columnKind draws and sorts one column
+type columnKind struct {
+	trim int                                                         // cells are this much narrower than the column
+	text func(m *model, c *docker.Container) string                  // cell text, "─" when there's nothing to show
+	less func(m *model, a, b *docker.Container) bool                 // ascending order
+	fit  func(m *model, c *docker.Container, s string, w int) string // nil cuts the text to w-2
+}
+
+// shownColumn is a visible column with its width on screen
+type shownColumn struct {
+	columns.Column
+	kind  columnKind
+	width int
+}
+
+var columnKinds = map[string]columnKind{
+	"id": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return c.ID },
+		less: func(m *model, a, b *docker.Container) bool { return a.ID < b.ID },
+	},
+	"name": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return listName(c) },
+		less: byText(listName),
+	},
+	"memory": {
+		trim: 2,
+		text: func(m *model, c *docker.Container) string { return m.memText(c.Stats) },
+		less: byStat(func(s *docker.ContainerStats) uint64 { return s.MemUsed }),
+		fit: func(m *model, c *docker.Container, s string, w int) string {
+			return m.inlineSpark(cut(s, w-2), c.ID, func(s statSample) float64 { return s.mem }, w-2)
+		},
+	},
+	"cpu": {
+		trim: 2,
+		text: func(m *model, c *docker.Container) string { return cpuText(c.Stats) },
+		less: func(m *model, a, b *docker.Container) bool { return cpuOf(*a) < cpuOf(*b) },
+		fit: func(m *model, c *docker.Container, s string, w int) string {
+			return m.inlineSpark(cut(s, w-2), c.ID, func(s statSample) float64 { return s.cpu }, w-2)
+		},
+	},
+	"net_io": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return m.netText(c.Stats) },
+		less: byStat(func(s *docker.ContainerStats) uint64 { return s.NetRx + s.NetTx }),
+	},
+	"disk_io": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return m.blockText(c.Stats) },
+		less: byStat(func(s *docker.ContainerStats) uint64 { return s.BlockRead + s.BlockWrite }),
+	},
+	"image": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return c.Image },
+		less: byText(func(c *docker.Container) string { return c.Image }),
+	},
+	"status": {
+		trim: 0,
+		text: func(m *model, c *docker.Container) string { return c.Status },
+		less: byText(func(c *docker.Container) string { return c.Status }),
+	},
+	"ports": {
+		trim: 2,
+		text: func(m *model, c *docker.Container) string { return dashed(c.Ports) },
+		less: byText(func(c *docker.Container) string { return c.Ports }),
+		// ports are cut shorter, the arrows make a cut-off port hard to read
+		fit: func(m *model, c *docker.Container, s string, w int) string {
+			if visibleLen(s) > w-7 {
+				return truncateToWidth(s, w-6)
+			}
+			return s
+		},
+	},
+	"created": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string {
+			if c.Created.IsZero() {
+				return "─"
+			}
+			return timeAgo(c.Created)
+		},
+		less: func(m *model, a, b *docker.Container) bool { return a.Created.Before(b.Created) },
+	},
+	"uptime": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return m.uptimeText(c) },
+		less: func(m *model, a, b *docker.Container) bool { return m.uptime(a) < m.uptime(b) },
+	},
+	"health": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return dashed(string(c.Health)) },
+		less: func(m *model, a, b *docker.Container) bool { return healthRank(a.Health) < healthRank(b.Health) },
+	},
+	"restarts": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string {
+			d, ok := m.details[c.ID]
+			if !ok || !d.loaded {
+				return "─"
+			}
+			return strconv.Itoa(d.restarts)
+		},
+		less: func(m *model, a, b *docker.Container) bool {
+			return m.details[a.ID].restarts < m.details[b.ID].restarts
+		},
+	},
+	"pids": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return pidsText(c.Stats) },
+		less: byStat(func(s *docker.ContainerStats) uint64 { return s.PIDs }),
+	},
+	"mem_limit": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string {
+			if c.Stats == nil {
+				return "─"
+			}
+			if c.Stats.MemLimit == 0 {
+				return m.bytes(c.Stats.MemUsed)
+			}
+			return m.bytePair(c.Stats.MemUsed, c.Stats.MemLimit)
+		},
+		less: func(m *model, a, b *docker.Container) bool {
+			limit := func(s *docker.ContainerStats) uint64 { return s.MemLimit }
+			if la, lb := statOf(*a, limit), statOf(*b, limit); la != lb {
+				return la < lb
+			}
+			used := func(s *docker.ContainerStats) uint64 { return s.MemUsed }
+			return statOf(*a, used) < statOf(*b, used)
+		},
+	},
+	"project": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return dashed(c.ComposeProject) },
+		less: byText(func(c *docker.Container) string { return c.ComposeProject }),
+	},
+	"service": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return dashed(c.ComposeService) },
+		less: byText(func(c *docker.Container) string { return c.ComposeService }),
+	},
+	"ip": {
+		trim: 1,
+		text: func(m *model, c *docker.Container) string { return dashed(m.ipOf(c)) },
+		less: func(m *model, a, b *docker.Container) bool {
+			// unparsable (no address) sorts first
+			ia, _ := netip.ParseAddr(m.ipOf(a))
+			ib, _ := netip.ParseAddr(m.ipOf(b))
+			return ia.Compare(ib) < 0
+		},
+	},
+}
+
+// kindOf is how a column is drawn, label columns are made up on the spot
+func kindOf(name string) columnKind {
+	if key, ok := strings.CutPrefix(name, columns.LabelPrefix); ok {
+		label := func(c *docker.Container) string { return c.Labels[key] }
+		return columnKind{
+			trim: 1,
+			text: func(m *model, c *docker.Container) string { return dashed(label(c)) },
+			less: byText(label),
+		}
+	}
+	if k, ok := columnKinds[name]; ok {
+		return k
+	}
+	return columnKinds["id"]
+}
+
+func byText(text func(c *docker.Container) string) func(m *model, a, b *docker.Container) bool {
+	return func(m *model, a, b *docker.Container) bool {
+		return strings.ToLower(text(a)) < strings.ToLower(text(b))
+	}
+}
+
+func byStat(pick func(*docker.ContainerStats) uint64) func(m *model, a, b *docker.Container) bool {
+	return func(m *model, a, b *docker.Container) bool {
+		return statOf(*a, pick) < statOf(*b, pick)
+	}
+}
+
+// listName is the name the list shows, empty for a nameless container
+func listName(c *docker.Container) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// dashed is s, or "─" for nothing like the stats columns
+func dashed(s string) string {
+	if s == "" {
+		return "─"
+	}
+	return s
+}
+
+// cut shortens s to w with an ellipsis when it doesn't fit
+func cut(s string, w int) string {
+	if visibleLen(s) > w {
+		return truncateToWidth(s, w)
+	}
+	return s
+}
+
+// healthRank orders health so a descending sort puts the sick ones on top
+func healthRank(health docker.Health) int {
+	return slices.Index([]docker.Health{docker.HealthNone, docker.HealthHealthy, docker.HealthStarting, docker.HealthUnhealthy}, health)
+}
+
+// ============================================================================
+// Layout
+// ============================================================================
+
+// visibleColumns are the columns the list shows, in order, with their
+// configured widths. nothing to show gets the default layout
+func (m model) visibleColumns() []config.ColumnConfig {
+	var out []config.ColumnConfig
+	for _, c := range m.settings.Columns {
+		if _, ok := columns.Lookup(c.Name); ok && !c.Hidden && c.Width > 0 {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		for _, c := range config.DefaultConfig().Layout.Columns {
+			if !c.Hidden {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// layoutColumns shares usable characters between the visible columns by
+// their percentages, no column goes under its minimum. the widths never add
+// up to more than usable, the columns that don't fit are left out
+func (m model) layoutColumns(usable int) []shownColumn {
+	visible := m.visibleColumns()
+	sum := 0
+	for _, c := range visible {
+		sum += c.Width
+	}
+
+	shown := make([]shownColumn, len(visible))
+	allocated := 0
+	for i, c := range visible {
+		col, _ := columns.Lookup(c.Name)
+		shown[i] = shownColumn{Column: col, kind: kindOf(c.Name), width: max(col.Min, usable*c.Width/sum)}
+		allocated += shown[i].width
+	}
+
+	// minimums can take it over the width: take it back from the last
+	// columns first, down to their minimums, then drop columns off the end
+	over := allocated - usable
+	for i := len(shown) - 1; i >= 0 && over > 0; i-- {
+		give := min(over, shown[i].width-shown[i].Min)
+		shown[i].width -= give
+		over -= give
+	}
+	for over > 0 && len(shown) > 1 {
+		over -= shown[len(shown)-1].width
+		shown = shown[:len(shown)-1]
+	}
+	if over > 0 {
+		// one column left and it doesn't fit either, it gets what there is
+		shown[0].width = max(1, usable)
+		over = 0
+	}
+
+	// what's left goes out one character at a time
+	for remaining := -over; remaining > 0; {
+		for i := range shown {
+			if remaining == 0 {
+				break
+			}
+			shown[i].width++
+			remaining--
+		}
+	}
+	return shown
+}
+
+// cells are a container's texts for the shown columns, padded to their
+// widths. lead goes in front of the name (tree branch, mark)
+func (m model) cells(c *docker.Container, shown []shownColumn, lead string) []string {
+	out := make([]string, 0, len(shown))
+	for _, col := range shown {
+		w := col.width
+		s := col.kind.text(&m, c)
+		if col.Name == "name" {
+			s = lead + s
+		}
+		if col.kind.fit != nil {
+			s = col.kind.fit(&m, c, s, w)
+		} else {
+			s = cut(s, w-2)
+		}
+		out = append(out, fmt.Sprintf("%-*s", w-col.kind.trim, cut(s, w-col.kind.trim)))
+	}
+	return out
+}
+
+// fitPercents scales the visible widths down to 100 when they add up to more
+func fitPercents(cols []config.ColumnConfig) {
+	total := 0
+	for _, c := range cols {
+		if !c.Hidden {
+			total += c.Width
+		}
+	}
+	if total <= 100 {
+		return
+	}
+	acc, first := 0, -1
+	for i := range cols {
+		if cols[i].Hidden {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		cols[i].Width = max(1, cols[i].Width*100/total)
+		acc += cols[i].Width
+	}
+	// rounding
+	if acc < 100 {
+		cols[first].Width += 100 - acc
+	}
+}
+
+// settingsColumns is the configured columns, then every other built-in one
+// hidden so settings can switch it on
+func settingsColumns(cfg []config.ColumnConfig) []config.ColumnConfig {
+	out := slices.Clone(cfg)
+	for _, c := range columns.All {
+		if !slices.ContainsFunc(out, func(cc config.ColumnConfig) bool { return cc.Name == c.Name }) {
+			out = append(out, config.ColumnConfig{Name: c.Name, Width: c.Width, Hidden: true})
+		}
+	}
+	return out
+}
+
+// ============================================================================
+// Details (restarts, uptime, ip)
+// ============================================================================
+//
+// the list doesn't say how often a container restarted or when it started,
+// an inspect does. containers get inspected once, and again when their state
+// changes, but only while a column needs it
+
+// containerDetails is what a column needs an inspect for
+type containerDetails struct {
+	loaded   bool   // false while the inspect is on its way
+	state    string // container state when it was inspected
+	restarts int
+	started  time.Time
+	ip       string
+}
+
+type detailsMsg struct {
+	details map[string]containerDetails
+}
+
+// inspects running at once
+const detailsWorkers = 4
+
+func fetchDetails(rt docker.Runtime, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		type result struct {
+			id string
+			in *docker.ContainerInspect
+		}
+		jobs := make(chan string)
+		results := make(chan result)
+		for range min(detailsWorkers, len(ids)) {
+			go func() {
+				for id := range jobs {
+					in, err := rt.Inspect(id)
+					if err != nil {
+						debugLogger.Printf("inspect %s for the list failed: %v", id, err)
+					}
+					results <- result{id, in}
+				}
+			}()
+		}
+		go func() {
+			for _, id := range ids {
+				jobs <- id
+			}
+			close(jobs)
+		}()
+
+		out := make(map[string]containerDetails, len(ids))
+		for range ids {
+			r := <-results
+			if r.in == nil {
+				continue
+			}
+			d := containerDetails{loaded: true, state: r.in.State, restarts: r.in.RestartCount, started: r.in.StartedAt}
+			for _, n := range r.in.Networks {
+				if n.IPAddress != "" {
+					d.ip, _, _ = strings.Cut(n.IPAddress, "/")
+					break
+				}
+			}
+			out[r.id] = d
+		}
+		return detailsMsg{details: out}
+	}
+}
+
+// syncDetails forgets containers that are gone and inspects the ones a
+// visible column is missing something for
+func (m *model) syncDetails() tea.Cmd {
+	need := map[string]bool{}
+	for _, c := range m.visibleColumns() {
+		need[c.Name] = true
+	}
+
+	listed := make(map[string]bool, len(m.allContainers))
+	var ids []string
+	for _, c := range m.allContainers {
+		listed[c.ID] = true
+		if d, ok := m.details[c.ID]; ok && d.state == c.State {
+			continue
+		}
+		if need["restarts"] || (need["uptime"] && c.State == "running") || (need["ip"] && c.IPAddress == "") {
+			// a placeholder, so the next refresh doesn't ask again
+			m.details[c.ID] = containerDetails{state: c.State}
+			ids = append(ids, c.ID)
+		}
+	}
+	for id := range m.details {
+		if !listed[id] {
+			delete(m.details, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return fetchDetails(m.rt, ids)
+}
+
+func (m *model) handleDetails(msg detailsMsg) {
+	for id, d := range msg.details {
+		// gone while it was inspected
+		if _, ok := m.details[id]; ok {
+			m.details[id] = d
+		}
+	}
+	m.sortContainers()
+}
+
+func (m model) ipOf(c *docker.Container) string {
+	if c.IPAddress != "" {
+		return c.IPAddress
+	}
+	return m.details[c.ID].ip
+}
+
+// uptime is how long a running container has been up, 0 for the others
+// and while it's unknown
+func (m model) uptime(c *docker.Container) time.Duration {
+	d := m.details[c.ID]
+	if c.State != "running" || d.started.IsZero() {
+		return 0
+	}
+	return time.Since(d.started)
+}
+
+func (m model) uptimeText(c *docker.Container) string {
+	if c.State != "running" {
+		return "─"
+	}
+	if up := m.uptime(c); up > 0 {
+		return shortDuration(up)
+	}
+	// until the inspect is back, docker's own words: "Up 5 minutes"
+	return strings.TrimPrefix(withHealth(c.Status, ""), "Up ")
+}
+
+// shortDuration is the two biggest units, "3d 4h", "12m 5s"
+func shortDuration(d time.Duration) string {
+	s := int(d.Seconds())
+	switch {
+	case s >= 86400:
+		return fmt.Sprintf("%dd %dh", s/86400, s%86400/3600)
+	case s >= 3600:
+		return fmt.Sprintf("%dh %dm", s/3600, s%3600/60)
+	case s >= 60:
+		return fmt.Sprintf("%dm %ds", s/60, s%60)
+	}
+	return fmt.Sprintf("%ds", s)
+}
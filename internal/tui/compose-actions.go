@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Compose project actions (up, stop, restart, down, pull, recreate)
+// ============================================================================
+
+const (
+	composeOutputLines = 500 // lines of compose output kept for the panel
+	composePanelHeight = 8   // divider, title and the newest output lines
+)
+
+// the group row for containers outside any project, it isn't a compose project
+const standaloneGroup = "Standalone Containers"
+
+// composeRun is one compose command against a project, its output fills the panel
+type composeRun struct {
+	project string
+	action  docker.ComposeAction
+	out     *ring[docker.ComposeOutput]
+	stream  <-chan docker.ComposeOutput // nil once the command exits
+	cancel  context.CancelFunc
+	done    bool  // command exited
+	err     error // why the command failed
+}
+
+type composeStartedMsg struct {
+	project string
+	ch      <-chan docker.ComposeOutput
+	cancel  context.CancelFunc
+	err     error
+}
+
+type composeOutputMsg struct {
+	ch     <-chan docker.ComposeOutput
+	lines  []docker.ComposeOutput
+	closed bool
+}
+
+func startComposeCmd(rt docker.Runtime, project docker.ComposeProject, action docker.ComposeAction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := rt.Compose(ctx, project, action)
+		if err != nil {
+			cancel()
+			return composeStartedMsg{project: project.Name, err: err}
+		}
+		return composeStartedMsg{project: project.Name, ch: ch, cancel: cancel}
+	}
+}
+
+// wait for the next line, then grab whatever else is already queued
+func waitForCompose(ch <-chan docker.ComposeOutput) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return composeOutputMsg{ch: ch, closed: true}
+		}
+		lines := []docker.ComposeOutput{line}
+		for {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					return composeOutputMsg{ch: ch, lines: lines, closed: true}
+				}
+				lines = append(lines, line)
+			default:
+				return composeOutputMsg{ch: ch, lines: lines}
+			}
+		}
+	}
+}
+
+// runCompose starts an action on a project and opens the output panel
+func (m *model) runCompose(project *docker.ComposeProject, action docker.ComposeAction) tea.Cmd {
+	if m.composeRun != nil && m.composeRun.stream != nil {
+		m.statusMessage = fmt.Sprintf("compose %s is still running on %s", m.composeRun.action, m.composeRun.project)
+		return nil
+	}
+	m.composeRun = &composeRun{
+		project: project.Name,
+		action:  action,
+		out:     newRing[docker.ComposeOutput](composeOutputLines),
+	}
+	m.statusMessage = fmt.Sprintf("Running compose %s on %s...", strings.Join(action.Args(), " "), project.Name)
+	m.updatePagination()
+	return startComposeCmd(m.rt, *project, action)
+}
+
+// stopCompose kills the running compose command, if any
+func (m *model) stopCompose() {
+	if m.composeRun == nil {
+		return
+	}
+	if m.composeRun.cancel != nil {
+		m.composeRun.cancel()
+	}
+	m.composeRun.stream = nil
+	m.composeRun.cancel = nil
+}
+
+// closeCompose hides the panel, killing the command if it's still going
+func (m *model) closeCompose() {
+	m.stopCompose()
+	m.composeRun = nil
+	m.updatePagination()
+}
+
+func (m *model) handleComposeMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case composeStartedMsg:
+		run := m.composeRun
+		if run == nil || run.project != msg.project {
+			// panel was closed while we were starting
+			if msg.cancel != nil {
+				msg.cancel()
+			}
+			return *m, nil
+		}
+		if msg.err != nil {
+			run.err = msg.err
+			run.done = true
+			run.out.Push(docker.ComposeOutput{Text: msg.err.Error(), Err: msg.err})
+			m.statusMessage = fmt.Sprintf("Compose error: %v", msg.err)
+			return *m, nil
+		}
+		run.stream = msg.ch
+		run.cancel = msg.cancel
+		return *m, waitForCompose(run.stream)
+
+	case composeOutputMsg:
+		run := m.composeRun
+		if run == nil || msg.ch != run.stream {
+			return *m, nil
+		}
+		for _, line := range msg.lines {
+			if line.Err != nil {
+				run.err = line.Err
+				line.Text = line.Err.Error()
+			}
+			run.out.Push(line)
+		}
+		if !msg.closed {
+			return *m, waitForCompose(run.stream)
+		}
+		m.stopCompose()
+		run.done = true
+		if run.err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", run.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("compose %s finished for %s", run.action, run.project)
+		}
+		return *m, m.fullRefresh()
+	}
+	return *m, nil
+}
+
+// handleComposeKey handles keys on project rows of the compose view, handled
+// is false for keys the normal bindings should get
+func (m *model) handleComposeKey(msg tea.KeyMsg) (cmd tea.Cmd, handled bool) {
+	if key.Matches(msg, m.keys.Back) && m.composeRun != nil {
+		if m.composeRun.stream != nil {
+			m.statusMessage = fmt.Sprintf("Cancelled compose %s on %s", m.composeRun.action, m.composeRun.project)
+		}
+		m.closeCompose()
+		return nil, true
+	}
+
+	if m.cursor >= len(m.flatList) || !m.flatList[m.cursor].isProject {
+		return nil, false
+	}
+	row := m.flatList[m.cursor]
+
+	if key.Matches(msg, m.keys.Select) {
+		m.expandedProjects[row.projectName] = !m.expandedProjects[row.projectName]
+		m.buildFlatList()
+		m.updatePagination()
+		return nil, true
+	}
+
+	var action docker.ComposeAction
+	switch {
+	case key.Matches(msg, m.keys.Start):
+		action = docker.ComposeUp
+	case key.Matches(msg, m.keys.Stop):
+		action = docker.ComposeStop
+	case key.Matches(msg, m.keys.Restart):
+		action = docker.ComposeRestart
+	case key.Matches(msg, m.keys.Remove):
+		action = docker.ComposeDown
+	case key.Matches(msg, m.keys.Pull):
+		action = docker.ComposePull
+	case key.Matches(msg, m.keys.Recreate):
+		action = docker.ComposeRecreate
+	default:
+		return nil, false
+	}
+
+	project := m.projects[row.projectName]
+	if row.projectName == standaloneGroup || project == nil {
+		m.statusMessage = "Standalone containers aren't a compose project, act on them one by one"
+		return nil, true
+	}
+	if action == docker.ComposeDown {
+		m.confirmComposeDown(row.projectName, project)
+		return nil, true
+	}
+	return m.runCompose(project, action), true
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderComposePanel(width int) string {
+	var b strings.Builder
+	run := m.composeRun
+
+	b.WriteString(dividerStyle.Render(strings.Repeat("─", width)))
+	b.WriteString("\n")
+
+	title := fmt.Sprintf("Compose: %s %s ", run.project, strings.Join(run.action.Args(), " "))
+	switch {
+	case run.stream != nil:
+		title += "[running] "
+	case run.err != nil:
+		title += "[failed] "
+	case run.done:
+		title += "[done] "
+	default:
+		title += "[starting] "
+	}
+	// titleStyle pads a space on each side
+	if len(title) < width-2 {
+		title += strings.Repeat(" ", width-2-len(title))
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	// newest lines, compose output doesn't need scrollback
+	maxLines := composePanelHeight - 2
+	start := max(0, run.out.Len()-maxLines)
+	for i := start; i < run.out.Len(); i++ {
+		line := run.out.At(i)
+		text := truncateToWidth(line.Text, width-4)
+		style := normalStyle
+		if line.Err != nil {
+			style = logStderrStyle
+		}
+		b.WriteString(style.Render(padRight("  "+text, width)))
+		b.WriteString("\n")
+	}
+	for i := run.out.Len() - start; i < maxLines; i++ {
+		b.WriteString(normalStyle.Render(strings.Repeat(" ", width)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// the output panel shares the bottom of the screen with the logs and info panels
+func (m model) composePanelVisible() bool {
+	return m.composeRun != nil && m.composeViewMode && !m.logsVisible && !m.infoVisible
+}
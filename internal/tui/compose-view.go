@@ -5,23 +5,27 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/shubh-io/dockmate/internal/docker"
 )
 
 func (m *model) buildFlatList() {
 	m.flatList = []treeRow{}
 
-	// sort projects by name
-	projectNames := []string{}
-	for name := range m.projects {
-		projectNames = append(projectNames, name)
-	}
-	sort.Strings(projectNames)
-
 	// Add compose projects
-	for _, projectName := range projectNames {
+	for _, projectName := range sortedProjectNames(m.projects) {
 		project := m.projects[projectName]
+
+		// only containers matching the filter, projects without any are hidden
+		var matching []*docker.Container
+		for i := range project.Containers {
+			if m.filter.Match(project.Containers[i]) {
+				matching = append(matching, &project.Containers[i])
+			}
+		}
+		if len(matching) == 0 && !m.filter.Empty() {
+			continue
+		}
+
 		running := 0
 		for _, c := range project.Containers {
 			if strings.ToLower(c.State) == "running" {
@@ -41,42 +45,27 @@ func (m *model) buildFlatList() {
 
 		// Add container rows if expanded
 		if m.expandedProjects[projectName] {
-			for i := range project.Containers {
+			for _, container := range matching {
 				m.flatList = append(m.flatList, treeRow{
 					isProject: false,
-					container: &project.Containers[i],
+					container: container,
 					indent:    1,
 				})
 			}
 		}
 	}
 
-	// Find standalone containers
-	standaloneContainers := []*docker.Container{}
-	composeContainerIDs := make(map[string]bool)
-
-	for _, project := range m.projects {
-		for _, c := range project.Containers {
-			composeContainerIDs[c.ID] = true
-		}
-	}
-
-	for i := range m.containers {
-		if !composeContainerIDs[m.containers[i].ID] {
-			standaloneContainers = append(standaloneContainers, &m.containers[i])
-		}
-	}
-
 	// Add standalone section if any exist
+	standaloneContainers := m.standaloneContainers()
 	if len(standaloneContainers) > 0 {
 		m.flatList = append(m.flatList, treeRow{
 			isProject:   true,
-			projectName: "Standalone Containers",
+			projectName: standaloneGroup,
 			total:       len(standaloneContainers),
 			indent:      0,
 		})
 
-		if m.expandedProjects["Standalone Containers"] {
+		if m.expandedProjects[standaloneGroup] {
 			for _, container := range standaloneContainers {
 				m.flatList = append(m.flatList, treeRow{
 					isProject: false,
@@ -88,22 +77,39 @@ func (m *model) buildFlatList() {
 	}
 }
 
-func (m model) renderTreeRow(row treeRow, selected bool, idW, nameW, memoryW, cpuW, netIOW, blockIOW, imageW, statusW, portsW, totalWidth int) string {
-	if row.isProject {
-		// Project header row
-		expandIcon := "▼"
-		if !m.expandedProjects[row.projectName] {
-			expandIcon = "▶"
+// sortedProjectNames is the order projects are listed in
+func sortedProjectNames(projects map[string]*docker.ComposeProject) []string {
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// standaloneContainers is every container that isn't part of a compose project
+func (m *model) standaloneContainers() []*docker.Container {
+	composeContainerIDs := make(map[string]bool)
+	for _, project := range m.projects {
+		for _, c := range project.Containers {
+			composeContainerIDs[c.ID] = true
 		}
+	}
 
-		projectLabel := fmt.Sprintf(" %s %s [%d/%d running]", expandIcon, row.projectName, row.running, row.total)
-		if visibleLen(projectLabel) < totalWidth {
-			projectLabel += strings.Repeat(" ", totalWidth-visibleLen(projectLabel))
+	standalone := []*docker.Container{}
+	for i := range m.containers {
+		if !composeContainerIDs[m.containers[i].ID] {
+			standalone = append(standalone, &m.containers[i])
 		}
+	}
+	return standalone
+}
 
-		// Project row style
-		projectStyle := lipgloss.NewStyle().Bold(true).Foreground(accent)
-		return projectStyle.Render(projectLabel)
+func (m model) renderTreeRow(row treeRow, selected bool, shown []shownColumn, totalWidth int) string {
+	if row.isProject {
+		// Project header row
+		label := fmt.Sprintf("%s [%d/%d running]", row.projectName, row.running, row.total)
+		return renderGroupRow(label, m.expandedProjects[row.projectName], selected, totalWidth)
 	}
 
 	c := row.container
@@ -111,177 +117,69 @@ func (m model) renderTreeRow(row treeRow, selected bool, idW, nameW, memoryW, cp
 		return normalStyle.Render(strings.Repeat(" ", totalWidth))
 	}
 
-	name := ""
-	if len(c.Names) > 0 {
-		name = c.Names[0]
-		name = strings.TrimPrefix(name, "/")
-	}
-
-	indentStr := ""
-	if row.indent > 0 {
-		indentStr = " ├─ "
-	}
-
-	id := c.ID
-	if visibleLen(id) > idW-2 {
-		id = truncateToWidth(id, idW-2)
-	}
-
-	containerName := indentStr + name
-	if visibleLen(containerName) > nameW-2 {
-		containerName = truncateToWidth(containerName, nameW-2)
-	}
-
-	img := c.Image
-	if visibleLen(img) > imageW-2 {
-		img = truncateToWidth(img, imageW-2)
+	rowStr := m.hostCell(c)
+	if rowStr != "" {
+		rowStr += "│ "
 	}
+	rowStr += strings.Join(m.cells(c, shown, treeBranch(row.indent)+m.markPrefix(c.ID)), "│ ")
 
-	status := c.Status
-	if visibleLen(status) > statusW-2 {
-		status = truncateToWidth(status, statusW-2)
-	}
-
-	mem := c.Memory
-	if mem == "" {
-		mem = "─"
-	}
-	if visibleLen(mem) > memoryW-2 {
-		mem = truncateToWidth(mem, memoryW-2)
+	if visibleLen(rowStr) < totalWidth {
+		rowStr += strings.Repeat(" ", totalWidth-visibleLen(rowStr))
 	}
 
-	cpu := c.CPU
-	if cpu == "" {
-		cpu = "─"
+	if selected {
+		return selectedStyle.Render(rowStr)
 	}
-	if visibleLen(cpu) > cpuW-2 {
-		cpu = truncateToWidth(cpu, cpuW-2)
+	if m.marked[c.ID] {
+		return markedStyle.Render(rowStr)
 	}
 
-	netio := c.NetIO
-	if netio == "" {
-		netio = "─"
-	}
-	if visibleLen(netio) > netIOW-2 {
-		netio = truncateToWidth(netio, netIOW-2)
-	}
+	return containerStyle(c).Render(rowStr)
+}
 
-	blockio := c.BlockIO
-	if blockio == "" {
-		blockio = "─"
-	}
-	if visibleLen(blockio) > blockIOW-2 {
-		blockio = truncateToWidth(blockio, blockIOW-2)
-	}
+// ============================================================================
+// Tree helpers (compose view, volumes view)
+// ============================================================================
 
-	ports := c.Ports
-	if ports == "" {
-		ports = "─"
-	}
-	if visibleLen(ports) > portsW-7 {
-		ports = truncateToWidth(ports, portsW-6)
+// renderGroupRow draws a collapsible header row like " ▼ shop [2/2 running]"
+func renderGroupRow(label string, expanded, selected bool, width int) string {
+	expandIcon := "▼"
+	if !expanded {
+		expandIcon = "▶"
 	}
 
-	visible := m.settings.VisibleColumns
-	if visible == nil || len(visible) != 9 {
-		visible = []bool{true, true, true, true, true, true, true, true, true}
-		m.settings.VisibleColumns = visible
+	line := fmt.Sprintf(" %s %s", expandIcon, label)
+	if visibleLen(line) > width {
+		line = truncateToWidth(line, width)
 	}
-	rows := []struct {
-		idx   int
-		width int
-		val   string
-	}{
-		{0, idW - 1, id},
-		{1, nameW - 1, containerName},
-		{2, memoryW - 2, mem},
-		{3, cpuW - 2, cpu},
-		{4, netIOW - 1, netio},
-		{5, blockIOW - 1, blockio},
-		{6, imageW - 1, img},
-		{7, statusW, status},
-		{8, portsW - 2, ports},
-	}
-
-	var rowStr string
-	first_row := true
-	for _, row := range rows {
-		if !visible[row.idx] {
-			continue
-		}
-		if first_row {
-			rowStr += fmt.Sprintf("%-*s", row.width, row.val)
-			first_row = false
-
-		} else {
-			rowStr += fmt.Sprintf("│ %-*s", row.width, row.val)
-		}
-
-	}
-
-	if visibleLen(rowStr) < totalWidth {
-		rowStr += strings.Repeat(" ", totalWidth-visibleLen(rowStr))
+	if visibleLen(line) < width {
+		line += strings.Repeat(" ", width-visibleLen(line))
 	}
 
 	if selected {
-		return selectedStyle.Render(rowStr)
+		return selectedStyle.Render(line)
 	}
+	return groupStyle.Render(line)
+}
 
-	switch strings.ToLower(c.State) {
-	case "running":
-		return runningStyle.Render(rowStr)
-	case "paused":
-		return pausedStyle.Render(rowStr)
-	case "exited", "dead":
-		return stoppedStyle.Render(rowStr)
-	default:
-		return normalStyle.Render(rowStr)
+// treeBranch is drawn in front of child rows
+func treeBranch(indent int) string {
+	if indent > 0 {
+		return " ├─ "
 	}
+	return ""
 }
 
+// moveCursorUpTree moves up one row, project rows included so they can be acted on
 func (m *model) moveCursorUpTree() {
-	if len(m.flatList) == 0 {
-		m.cursor = 0
-		return
-	}
-	i := m.cursor - 1
-	for i >= 0 && m.flatList[i].isProject {
-		i--
-	}
-	if i >= 0 {
-		m.cursor = i
-	} else {
-		// clamp to first non-project if any
-		for j := 0; j < len(m.flatList); j++ {
-			if !m.flatList[j].isProject {
-				m.cursor = j
-				return
-			}
-		}
-		m.cursor = 0
+	if m.cursor > 0 {
+		m.cursor--
 	}
 }
 
 func (m *model) moveCursorDownTree() {
-	if len(m.flatList) == 0 {
-		m.cursor = 0
-		return
-	}
-	i := m.cursor + 1
-	for i < len(m.flatList) && m.flatList[i].isProject {
-		i++
-	}
-	if i < len(m.flatList) {
-		m.cursor = i
-	} else {
-		// clamp to last non-project if any
-		for j := len(m.flatList) - 1; j >= 0; j-- {
-			if !m.flatList[j].isProject {
-				m.cursor = j
-				return
-			}
-		}
-		m.cursor = len(m.flatList) - 1
+	if m.cursor < len(m.flatList)-1 {
+		m.cursor++
 	}
 }
 
@@ -306,17 +204,17 @@ func (m *model) refreshInfoContainer() {
 			}
 		}
 		// fallback to normal containers
-		for i := range m.containers {
-			if m.containers[i].ID == id {
-				m.infoContainer = &m.containers[i]
+		for i := range m.allContainers {
+			if m.allContainers[i].ID == id {
+				m.infoContainer = &m.allContainers[i]
 				return
 			}
 		}
 	} else {
 
-		for i := range m.containers {
-			if m.containers[i].ID == id {
-				m.infoContainer = &m.containers[i]
+		for i := range m.allContainers {
+			if m.allContainers[i].ID == id {
+				m.infoContainer = &m.allContainers[i]
 				return
 			}
 		}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shubh-io/dockmate/internal/config"
+)
+
+// ============================================================================
+// Config problems banner
+// ============================================================================
+//
+// when config.Load had to use defaults for part of the file the TUI says so
+// on startup, a typo shouldn't quietly wipe someone's layout
+
+const configBannerRows = 10 // problems shown before "... and N more"
+
+// WithConfigProblems opens the banner for the errors Load reported
+func (m model) WithConfigProblems(err *config.ValidationError) model {
+	if err != nil && len(err.Problems) > 0 {
+		m.configBanner = err
+	}
+	return m
+}
+
+func (m model) renderConfigBanner() string {
+	inner := dialogWidth - 6
+
+	var b strings.Builder
+	b.WriteString(dialogTitleStyle.Render("Config problems, using defaults for:"))
+	b.WriteString("\n")
+	b.WriteString(footerDescStyle.Render(truncateToWidth(m.configBanner.Path, inner)))
+	b.WriteString("\n\n")
+	for i, p := range m.configBanner.Problems {
+		if i == configBannerRows {
+			b.WriteString(fmt.Sprintf("... and %d more\n", len(m.configBanner.Problems)-i))
+			break
+		}
+		b.WriteString(logStderrStyle.Render(truncateToWidth(p.String(), inner)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerDescStyle.Render("`dockmate config validate` lists warnings too"))
+	b.WriteString("\n")
+	b.WriteString(footerDescStyle.Render("any key to close"))
+
+	return dialogStyle.Width(dialogWidth - 2).Render(b.String())
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Live config reload
+// ============================================================================
+//
+// config.yml is checked on every refresh tick. when it changed it's read
+// again and applied to the running model: layout, refresh, shell, units,
+// keys and colors right away, a runtime change swaps the backend underneath. the cursor,
+// expanded projects and open panels stay where they are
+
+// fileStamp is enough to tell the file changed without reading it
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statConfig stamps the config file, the zero stamp when there isn't one
+func statConfig() fileStamp {
+	path, err := config.GetConfigPath()
+	if err != nil {
+		return fileStamp{}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// configReloadedMsg is the config read again after it changed on disk
+type configReloadedMsg struct {
+	cfg   *config.Config
+	err   error // a *config.ValidationError still comes with a usable cfg
+	stamp fileStamp
+}
+
+// runtimeReloadedMsg is the backend reopened after the runtime settings changed
+type runtimeReloadedMsg struct {
+	rt  docker.Runtime
+	err error
+}
+
+// watchConfig re-reads the config when its stamp moved. a deleted file is
+// left alone, the running settings are as good as the defaults
+func (m model) watchConfig() tea.Cmd {
+	load, last := m.loadConfig, m.configStamp
+	return func() tea.Msg {
+		stamp := statConfig()
+		if stamp == last || stamp == (fileStamp{}) {
+			return nil
+		}
+		cfg, err := load()
+		return configReloadedMsg{cfg: cfg, err: err, stamp: stamp}
+	}
+}
+
+// WithConfigLoader makes reloads go through load instead of config.Load,
+// main uses it so the command line overrides survive a reload
+func (m model) WithConfigLoader(load func() (*config.Config, error)) model {
+	m.loadConfig = load
+	return m
+}
+
+// settingsFromConfig is the part of the config the settings screen edits
+func settingsFromConfig(cfg *config.Config) Settings {
+	return Settings{
+		Columns:         settingsColumns(cfg.Layout.Columns),
+		RefreshInterval: cfg.Performance.PollRate,
+		Runtime:         ContainerRuntime(cfg.Runtime.Type),
+		Shell:           cfg.Exec.Shell,
+		Safety:          cfg.Safety,
+		Filter:          cfg.Filter,
+		Units:           cfg.Display.Units,
+		Theme:           cfg.Theme.Name,
+	}
+}
+
+// putEdits copies what was changed in the settings screen onto cfg. the rest
+// of cfg stays as it is, so a value that came from a flag (--runtime,
+// --refresh, --theme, NO_COLOR) only gets into the file when it was edited
+func (m model) putEdits(cfg *config.Config) {
+	edited, base := m.settings, m.savedSettings
+	if !slices.Equal(edited.Columns, base.Columns) {
+		cfg.Layout.Columns = slices.Clone(edited.Columns)
+		fitPercents(cfg.Layout.Columns)
+	}
+	if edited.RefreshInterval != base.RefreshInterval {
+		cfg.Performance.PollRate = edited.RefreshInterval
+	}
+	if edited.Runtime != base.Runtime && string(edited.Runtime) != cfg.Runtime.Type {
+		cfg.Runtime.Type = string(edited.Runtime)
+		cfg.Runtime.Socket = "" // it was the old runtime's socket
+	}
+	if edited.Shell != base.Shell {
+		cfg.Exec.Shell = edited.Shell
+	}
+	if edited.Units != base.Units {
+		cfg.Display.Units = edited.Units
+	}
+	if edited.Theme != base.Theme {
+		cfg.Theme.Name = edited.Theme
+	}
+}
+
+// applyConfig puts a new config into effect without starting over. the
+// runtime is reopened off the update loop when its settings changed
+func (m *model) applyConfig(cfg *config.Config) tea.Cmd {
+	m.settings = settingsFromConfig(cfg)
+	m.savedSettings = settingsFromConfig(cfg)
+	m.keys = newKeyMap(cfg.Keys)
+	applyTheme(cfg.Theme)
+	m.hostNames = docker.HostNames(cfg)
+	m.connect = connector(cfg)
+	m.updatePagination()
+	details := m.syncDetails()
+
+	// a remote host doesn't care about the local runtime settings
+	old := m.runtimeConfig
+	m.runtimeConfig = cfg.Runtime
+	if (cfg.Runtime.Type == old.Type && cfg.Runtime.Socket == old.Socket) || (m.host != "" && m.host != docker.AllHosts) {
+		return details
+	}
+	connect, host := m.connect, m.hostLabel()
+	return tea.Batch(details, func() tea.Msg {
+		rt, err := connect(host)
+		return runtimeReloadedMsg{rt: rt, err: err}
+	})
+}
+
+// swapRuntime moves everything that talks to the daemon over to rt. the
+// list is fetched again, the logs panel follows its container on the new one
+func (m *model) swapRuntime(rt docker.Runtime) tea.Cmd {
+	m.stopEvents()
+	m.rt = rt
+	m.loading = true
+	m.details = make(map[string]containerDetails)
+
+	cmds := []tea.Cmd{fetchContainers(rt), subscribeEvents(rt)}
+	if m.composeViewMode {
+		cmds = append(cmds, fetchComposeProjects(rt))
+	}
+	if m.logsVisible && m.logsContainer != "" {
+		cmds = append(cmds, m.openLogs(m.logsContainer))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *model) handleConfigReload(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case configReloadedMsg:
+		m.configStamp = msg.stamp
+		if msg.cfg == nil {
+			m.statusMessage = fmt.Sprintf("Can't reload the config: %v", msg.err)
+			return nil
+		}
+		m.configBanner = nil
+		var invalid *config.ValidationError
+		if errors.As(msg.err, &invalid) {
+			m.configBanner = invalid
+		}
+		m.statusMessage = "Config reloaded"
+		return m.applyConfig(msg.cfg)
+
+	case runtimeReloadedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Can't switch to %s: %v", m.settings.Runtime, msg.err)
+			return nil
+		}
+		m.statusMessage = fmt.Sprintf("Switched to %s", m.settings.Runtime)
+		return m.swapRuntime(msg.rt)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Confirm dialog (destructive actions)
+// ============================================================================
+
+const dialogWidth = 64
+
+// confirmOption is a checkbox in the confirm dialog
+type confirmOption struct {
+	label   string
+	checked bool
+}
+
+// confirmDialog is a modal in front of a destructive action, nothing runs
+// until it's confirmed. protected targets have to be typed out instead of y.
+type confirmDialog struct {
+	title     string
+	details   [][2]string // label, value
+	options   []confirmOption
+	focus     int    // highlighted option
+	typed     string // text to type to confirm, empty for y/enter
+	input     string
+	onConfirm func(m *model, options []bool) tea.Cmd // runs against the model current when confirmed
+}
+
+// handleConfirmKey takes every key while the dialog is open
+func (m *model) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	d := m.confirm
+
+	// letters are text while the name is being typed
+	if d.typed != "" && msg.Type == tea.KeyRunes {
+		d.input += string(msg.Runes)
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.confirm = nil
+		m.statusMessage = "Cancelled"
+		return nil
+	case key.Matches(msg, m.keys.OptionUp):
+		if len(d.options) > 0 {
+			d.focus = (d.focus + len(d.options) - 1) % len(d.options)
+		}
+		return nil
+	case key.Matches(msg, m.keys.OptionDown):
+		if len(d.options) > 0 {
+			d.focus = (d.focus + 1) % len(d.options)
+		}
+		return nil
+	case key.Matches(msg, m.keys.Toggle):
+		if len(d.options) > 0 {
+			d.options[d.focus].checked = !d.options[d.focus].checked
+		}
+		return nil
+	case key.Matches(msg, m.keys.Select):
+		if d.typed != "" && d.input != d.typed {
+			m.statusMessage = fmt.Sprintf("Type %q to confirm", d.typed)
+			return nil
+		}
+		return m.acceptConfirm()
+	}
+
+	if d.typed == "" {
+		switch {
+		case key.Matches(msg, m.keys.Confirm):
+			return m.acceptConfirm()
+		case key.Matches(msg, m.keys.Cancel):
+			m.confirm = nil
+			m.statusMessage = "Cancelled"
+		}
+		return nil
+	}
+
+	if msg.Type == tea.KeyBackspace && len(d.input) > 0 {
+		r := []rune(d.input)
+		d.input = string(r[:len(r)-1])
+	}
+	return nil
+}
+
+func (m *model) acceptConfirm() tea.Cmd {
+	d := m.confirm
+	m.confirm = nil
+	checked := make([]bool, len(d.options))
+	for i, o := range d.options {
+		checked[i] = o.checked
+	}
+	return d.onConfirm(m, checked)
+}
+
+// ============================================================================
+// Dialogs
+// ============================================================================
+
+func containerName(c docker.Container) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return c.ID
+}
+
+// confirmRemoveContainer opens the dialog in front of `rm`
+func (m *model) confirmRemoveContainer(c docker.Container) {
+	name := containerName(c)
+	running := c.State == "running"
+
+	d := &confirmDialog{
+		title: "Remove container " + name + "?",
+		details: [][2]string{
+			{"Name", name},
+			{"ID", c.ID},
+			{"Image", c.Image},
+			{"State", c.State},
+		},
+		options: []confirmOption{
+			{label: "Stop first", checked: running},
+			{label: "Force (kill if running)"},
+			{label: "Remove anonymous volumes"},
+		},
+	}
+	if m.settings.Safety.Protects(name, c.Labels) {
+		d.title = "Remove PROTECTED container " + name + "?"
+		d.typed = name
+	}
+
+	d.onConfirm = func(m *model, options []bool) tea.Cmd {
+		m.statusMessage = fmt.Sprintf("Removing %s...", name)
+		return removeContainerCmd(m.rt, c.ID, options[0], docker.RemoveOptions{Force: options[1], Volumes: options[2]})
+	}
+	m.confirm = d
+}
+
+// confirmRemoveImage opens the dialog in front of `rmi`. an image containers
+// still use needs force, and has to be typed out when one of them is protected
+func (m *model) confirmRemoveImage(img docker.Image) {
+	ref := img.Ref()
+	used := "no containers"
+	if len(img.Containers) > 0 {
+		used = strings.Join(img.Containers, ", ")
+	}
+
+	d := &confirmDialog{
+		title: "Remove image " + ref + "?",
+		details: [][2]string{
+			{"Image", ref},
+			{"ID", img.ID},
+			{"Size", docker.FormatBytes(img.Size)},
+			{"Used by", used},
+		},
+	}
+	if len(img.Containers) > 0 {
+		d.title = fmt.Sprintf("Remove image %s, used by %d containers?", ref, len(img.Containers))
+		d.options = []confirmOption{{label: "Force (remove it from under the containers)"}}
+	}
+	for _, c := range m.allContainers {
+		if slices.Contains(img.Containers, containerName(c)) && m.settings.Safety.Protects(containerName(c), c.Labels) {
+			d.title = "Remove image " + ref + " used by PROTECTED " + containerName(c) + "?"
+			d.typed = ref
+			break
+		}
+	}
+
+	d.onConfirm = func(m *model, options []bool) tea.Cmd {
+		force := len(options) > 0 && options[0]
+		if len(img.Containers) > 0 && !force {
+			m.statusMessage = fmt.Sprintf("%s is used by %s, check Force to remove it", ref, used)
+			return nil
+		}
+		m.statusMessage = fmt.Sprintf("Removing %s...", ref)
+		return removeImageCmd(m.rt, img, force)
+	}
+	m.confirm = d
+}
+
+// confirmComposeDown opens the dialog in front of `compose down`. projectKey is the
+// project's key in m.projects, host/name in the all-hosts view
+func (m *model) confirmComposeDown(projectKey string, project *docker.ComposeProject) {
+	running, protected := 0, false
+	for _, c := range project.Containers {
+		if c.State == "running" {
+			running++
+		}
+		if m.settings.Safety.Protects(containerName(c), c.Labels) {
+			protected = true
+		}
+	}
+
+	d := &confirmDialog{
+		title: "Take down compose project " + project.Name + "?",
+		details: [][2]string{
+			{"Project", project.Name},
+			{"Containers", fmt.Sprintf("%d (%d running), removed with its networks", len(project.Containers), running)},
+			{"Compose file", project.ConfigFile},
+		},
+		options: []confirmOption{
+			{label: "Remove volumes too (--volumes)"},
+		},
+	}
+	if protected {
+		d.title = "Take down PROTECTED compose project " + project.Name + "?"
+		d.typed = project.Name
+	}
+
+	name := project.Name
+	d.onConfirm = func(m *model, options []bool) tea.Cmd {
+		project := m.projects[projectKey]
+		if project == nil {
+			m.statusMessage = fmt.Sprintf("Project %s is gone", name)
+			return nil
+		}
+		action := docker.ComposeDown
+		if options[0] {
+			action = docker.ComposeDownAll
+		}
+		return m.runCompose(project, action)
+	}
+	m.confirm = d
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderConfirm() string {
+	d := m.confirm
+	inner := dialogWidth - 6 // border and padding
+
+	var b strings.Builder
+	b.WriteString(dialogTitleStyle.Render(truncateToWidth(d.title, inner)))
+	b.WriteString("\n\n")
+	for _, kv := range d.details {
+		if kv[1] == "" {
+			continue
+		}
+		value := truncateToWidth(kv[1], inner-14)
+		b.WriteString(infoLabelStyle.Render(fmt.Sprintf("%-13s", kv[0])) + " " + infoValueStyle.Render(value))
+		b.WriteString("\n")
+	}
+
+	if len(d.options) > 0 {
+		b.WriteString("\n")
+		for i, o := range d.options {
+			box := "[ ]"
+			if o.checked {
+				box = "[x]"
+			}
+			line := padRight(box+" "+o.label, inner)
+			if i == d.focus {
+				line = selectedStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if d.typed != "" {
+		b.WriteString(fmt.Sprintf("Type %s to confirm:\n", dialogTitleStyle.Render(d.typed)))
+		b.WriteString("> " + d.input + "_\n\n")
+		b.WriteString(footerDescStyle.Render(fmt.Sprintf("%s option  %s toggle  %s confirm  %s cancel",
+			label(m.keys.OptionUp, m.keys.OptionDown), label(m.keys.Toggle), label(m.keys.Select), label(m.keys.Back))))
+	} else {
+		b.WriteString(footerDescStyle.Render(fmt.Sprintf("%s option  %s toggle  %s confirm  %s cancel",
+			label(m.keys.OptionUp, m.keys.OptionDown), label(m.keys.Toggle), label(m.keys.Confirm, m.keys.Select), label(m.keys.Cancel, m.keys.Back))))
+	}
+
+	return dialogStyle.Width(dialogWidth - 2).Render(b.String())
+}
+
+// overlayCenter puts box in the middle of screen. whole lines are replaced so
+// styled text underneath never gets cut mid escape sequence.
+func overlayCenter(screen, box string, width int) string {
+	lines := strings.Split(screen, "\n")
+	boxLines := strings.Split(box, "\n")
+	top := max(0, (len(lines)-len(boxLines))/2)
+
+	for i, bl := range boxLines {
+		if top+i >= len(lines) {
+			lines = append(lines, "")
+		}
+		left := max(0, (width-lipgloss.Width(bl))/2)
+		lines[top+i] = padRight(strings.Repeat(" ", left)+bl, width)
+	}
+	return strings.Join(lines, "\n")
+}
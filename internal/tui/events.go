@@ -0,0 +1,363 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Live events
+// ============================================================================
+
+// while subscribed, ticks only fetch stats. a full list refresh still runs this
+// often in case an event got lost
+const resyncInterval = 30 * time.Second
+
+// wait this long before trying to resubscribe after the stream drops
+const resubscribeDelay = 5 * time.Second
+
+type eventsSubscribedMsg struct {
+	ch     <-chan docker.Event
+	cancel context.CancelFunc
+	err    error
+}
+
+type eventMsg docker.Event
+
+type eventsClosedMsg struct {
+	ch <-chan docker.Event
+}
+
+type resubscribeMsg struct{}
+
+type statsMsg struct {
+	Stats map[string]docker.ContainerStats
+	Err   error
+}
+
+// open the runtime event stream
+func subscribeEvents(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := rt.Events(ctx)
+		if err != nil {
+			cancel()
+			return eventsSubscribedMsg{err: err}
+		}
+		return eventsSubscribedMsg{ch: ch, cancel: cancel}
+	}
+}
+
+// block until the next event, re-issued after every event
+func waitForEvent(ch <-chan docker.Event) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-ch
+		if !ok {
+			return eventsClosedMsg{ch: ch}
+		}
+		return eventMsg(ev)
+	}
+}
+
+func resubscribeCmd() tea.Cmd {
+	return tea.Tick(resubscribeDelay, func(time.Time) tea.Msg { return resubscribeMsg{} })
+}
+
+// fetch stats only, for ticks while events keep the list up to date
+func fetchStats(rt docker.Runtime, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		if len(ids) == 0 {
+			return statsMsg{Stats: map[string]docker.ContainerStats{}}
+		}
+		stats, err := rt.GetAllContainerStats(ids)
+		return statsMsg{Stats: stats, Err: err}
+	}
+}
+
+// stopEvents closes the event stream, if one is open
+func (m *model) stopEvents() {
+	if m.cancelEvents != nil {
+		m.cancelEvents()
+	}
+	m.events = nil
+	m.cancelEvents = nil
+}
+
+// quit closes the event, log and pull streams before exiting, so `docker events` /
+// `docker logs -f` / `docker pull` children don't outlive us
+func (m *model) quit() tea.Cmd {
+	m.stopEvents()
+	m.stopLogs()
+	m.stopPull()
+	m.stopCompose()
+	return tea.Quit
+}
+
+// fullRefresh refetches everything the current view shows
+func (m *model) fullRefresh() tea.Cmd {
+	m.lastSync = time.Now()
+	if m.composeViewMode {
+		return tea.Batch(fetchContainers(m.rt), fetchComposeProjects(m.rt))
+	}
+	return fetchContainers(m.rt)
+}
+
+// runningContainerIDs lists the containers worth asking stats for
+func (m *model) runningContainerIDs() []string {
+	var ids []string
+	for _, c := range m.allContainers {
+		if c.State == "running" {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// keepStats puts the last known stats on a freshly listed set of containers,
+// the list comes without them and the stats ticks fill them in
+func (m *model) keepStats(fresh []docker.Container) {
+	last := make(map[string]*docker.ContainerStats, len(m.allContainers))
+	for _, c := range m.allContainers {
+		if c.Stats != nil {
+			last[c.ID] = c.Stats
+		}
+	}
+	for i := range fresh {
+		if fresh[i].Stats == nil && fresh[i].State == "running" {
+			fresh[i].Stats = last[fresh[i].ID]
+		}
+	}
+}
+
+// fetchMissingStats gets stats for running containers that have none yet,
+// on the first load and for new containers, instead of waiting a tick
+func (m *model) fetchMissingStats() tea.Cmd {
+	var ids []string
+	for _, c := range m.allContainers {
+		if c.State == "running" && c.Stats == nil {
+			ids = append(ids, c.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return fetchStats(m.rt, ids)
+}
+
+// sameContainer matches IDs of different lengths (podman events carry the full id)
+func sameContainer(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// patchContainer applies fn to the container in both the list and its compose project.
+// returns false when we don't know the container yet.
+func (m *model) patchContainer(id string, fn func(c *docker.Container)) bool {
+	found := false
+	for i := range m.allContainers {
+		if sameContainer(m.allContainers[i].ID, id) {
+			fn(&m.allContainers[i])
+			found = true
+		}
+	}
+	for _, p := range m.projects {
+		for i := range p.Containers {
+			if sameContainer(p.Containers[i].ID, id) {
+				fn(&p.Containers[i])
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// removeContainer drops a destroyed container from the list and its project
+func (m *model) removeContainer(id string) {
+	kept := m.allContainers[:0]
+	for _, c := range m.allContainers {
+		if !sameContainer(c.ID, id) {
+			kept = append(kept, c)
+		}
+	}
+	m.allContainers = kept
+
+	for name, p := range m.projects {
+		keptP := p.Containers[:0]
+		for _, c := range p.Containers {
+			if !sameContainer(c.ID, id) {
+				keptP = append(keptP, c)
+			}
+		}
+		p.Containers = keptP
+		if len(p.Containers) == 0 {
+			delete(m.projects, name)
+		}
+	}
+}
+
+// withHealth swaps the "(healthy)" style suffix docker puts on the status column
+func withHealth(status string, health docker.Health) string {
+	for _, suffix := range []string{" (healthy)", " (unhealthy)", " (health: starting)", " (starting)"} {
+		status = strings.TrimSuffix(status, suffix)
+	}
+	switch health {
+	case docker.HealthHealthy, docker.HealthUnhealthy:
+		return status + " (" + string(health) + ")"
+	case docker.HealthStarting:
+		return status + " (health: starting)"
+	}
+	return status
+}
+
+func clearStats(c *docker.Container) {
+	c.Stats = nil
+}
+
+// applyEvent patches local state for one event. it returns a command when the
+// event needs data we don't have (a new container, a rename) and a refetch is cheaper.
+func (m *model) applyEvent(ev docker.Event) tea.Cmd {
+	name := ev.Name
+	if name == "" {
+		name = ev.ContainerID
+	}
+
+	var known bool
+	switch ev.Action {
+	case "start", "restart":
+		known = m.patchContainer(ev.ContainerID, func(c *docker.Container) {
+			c.State, c.Status = "running", "Up Less than a second"
+		})
+		// new start time, maybe another restart
+		delete(m.details, ev.ContainerID)
+	case "unpause":
+		known = m.patchContainer(ev.ContainerID, func(c *docker.Container) {
+			c.State, c.Status = "running", strings.TrimSuffix(c.Status, " (Paused)")
+		})
+	case "pause":
+		known = m.patchContainer(ev.ContainerID, func(c *docker.Container) {
+			c.State = "paused"
+			if !strings.HasSuffix(c.Status, " (Paused)") {
+				c.Status += " (Paused)"
+			}
+		})
+	case "die":
+		code := ev.ExitCode
+		if code == "" {
+			code = "0"
+		}
+		known = m.patchContainer(ev.ContainerID, func(c *docker.Container) {
+			c.State = "exited"
+			c.Status = fmt.Sprintf("Exited (%s) Less than a second ago", code)
+			// the next health_status brings it back after a start
+			c.Health = docker.HealthNone
+			clearStats(c)
+		})
+	case "health_status":
+		known = m.patchContainer(ev.ContainerID, func(c *docker.Container) {
+			c.Status = withHealth(c.Status, ev.Health)
+			c.Health = ev.Health
+		})
+	case "oom":
+		m.statusMessage = fmt.Sprintf("%s ran out of memory", name)
+		return nil
+	case "destroy", "remove":
+		m.removeContainer(ev.ContainerID)
+		known = true
+	case "create", "rename":
+		return m.fullRefresh()
+	default:
+		// exec_*, attach, kill, top... nothing we display
+		return nil
+	}
+
+	if !known {
+		// started before we listed it
+		return m.fullRefresh()
+	}
+	return nil
+}
+
+// handleEventMsgs deals with the subscription lifecycle and incoming events
+func (m model) handleEventMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case eventsSubscribedMsg:
+		if msg.err != nil {
+			// runtime can't stream (old podman, no permissions), keep polling and retry later
+			debugLogger.Printf("events subscribe failed: %v", msg.err)
+			return m, resubscribeCmd()
+		}
+		m.stopEvents()
+		m.events = msg.ch
+		m.cancelEvents = msg.cancel
+		// anything that happened while we weren't listening
+		return m, tea.Batch(m.fullRefresh(), waitForEvent(m.events))
+
+	case eventsClosedMsg:
+		if msg.ch != m.events {
+			// an old stream we already replaced
+			return m, nil
+		}
+		// daemon restarted or the cli exited, poll until we're back
+		m.stopEvents()
+		return m, tea.Batch(m.fullRefresh(), resubscribeCmd())
+
+	case resubscribeMsg:
+		if m.events != nil {
+			return m, nil
+		}
+		return m, subscribeEvents(m.rt)
+
+	case eventMsg:
+		cmd := m.applyEvent(docker.Event(msg))
+		m.sortContainers()
+		if m.composeViewMode {
+			m.buildFlatList()
+		}
+		m.refreshInfoContainer()
+		m.updatePagination()
+
+		next := waitForEvent(m.events)
+		if m.events == nil {
+			next = nil
+		}
+		return m, tea.Batch(cmd, m.syncDetails(), next)
+
+	case statsMsg:
+		var partial *docker.StatsError
+		if msg.Err != nil && !errors.As(msg.Err, &partial) {
+			debugLogger.Printf("stats fetch failed: %v", msg.Err)
+			return m, nil
+		}
+		if partial != nil {
+			// what's missing shows as missing, not as the last sample
+			debugLogger.Printf("stats fetch: %v", partial)
+			for id := range partial.Failed {
+				m.patchContainer(id, func(c *docker.Container) {
+					c.Stats = nil
+				})
+			}
+			if m.statusMessage == "" {
+				m.statusMessage = fmt.Sprintf("No stats for %d containers this time, see the debug log", len(partial.Failed))
+			}
+		}
+		for id, s := range msg.Stats {
+			m.patchContainer(id, func(c *docker.Container) {
+				c.Stats = &s
+			})
+		}
+		m.recordStats(msg.Stats)
+		m.sortContainers()
+		m.refreshInfoContainer()
+		return m, nil
+	}
+	return m, nil
+}
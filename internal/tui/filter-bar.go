@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/filter"
+)
+
+// ============================================================================
+// Filter bar (`/`)
+// ============================================================================
+
+// filterBar is the `/` prompt, the query applies as it's typed
+type filterBar struct {
+	editing bool
+	input   string
+	before  filter.Query // filter to go back to on esc
+	err     error        // why the input doesn't parse, the last good query stays applied
+	recall  int          // index into the recent filters, -1 while typing
+}
+
+// applyFilter narrows allContainers down to containers and rebuilds the tree
+func (m *model) applyFilter() {
+	m.containers = make([]docker.Container, 0, len(m.allContainers))
+	for _, c := range m.allContainers {
+		if m.filter.Match(c) {
+			m.containers = append(m.containers, c)
+		}
+	}
+	if m.composeViewMode {
+		m.buildFlatList()
+	}
+}
+
+// setFilter swaps the active filter and keeps the cursor on the list
+func (m *model) setFilter(q filter.Query) {
+	m.filter = q
+	m.applyFilter()
+	m.cursor = 0
+	m.page = 0
+	m.updatePagination()
+}
+
+func (m *model) openFilterBar() {
+	m.filterBar = filterBar{editing: true, input: m.filter.String(), before: m.filter, recall: -1}
+}
+
+func (m *model) handleFilterKey(msg tea.KeyMsg) tea.Cmd {
+	bar := &m.filterBar
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		bar.editing = false
+		m.setFilter(bar.before)
+		return nil
+
+	case tea.KeyEnter:
+		if bar.err != nil {
+			m.statusMessage = fmt.Sprintf("Filter: %v", bar.err)
+			return nil
+		}
+		bar.editing = false
+		m.rememberFilter(m.filter.String())
+		return nil
+
+	case tea.KeyUp, tea.KeyDown:
+		// walk through the recent filters
+		recent := m.settings.Filter.Recent
+		if len(recent) == 0 {
+			return nil
+		}
+		if msg.Type == tea.KeyUp {
+			bar.recall = min(bar.recall+1, len(recent)-1)
+		} else {
+			bar.recall = max(bar.recall-1, -1)
+		}
+		bar.input = ""
+		if bar.recall >= 0 {
+			bar.input = recent[bar.recall]
+		}
+
+	case tea.KeyBackspace:
+		if len(bar.input) > 0 {
+			r := []rune(bar.input)
+			bar.input = string(r[:len(r)-1])
+		}
+
+	case tea.KeySpace:
+		bar.input += " "
+
+	case tea.KeyRunes:
+		bar.input += string(msg.Runes)
+
+	default:
+		return nil
+	}
+
+	q, err := filter.Parse(bar.input)
+	bar.err = err
+	if err == nil {
+		m.setFilter(q)
+	}
+	return nil
+}
+
+// rememberFilter keeps the query in the config so it can be recalled next time.
+// like the settings screen it starts from the file, m.loadConfig has the
+// command line flags on top and those aren't for saving
+func (m *model) rememberFilter(query string) {
+	m.settings.Filter.Remember(query)
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	cfg.Filter = m.settings.Filter
+	if err := cfg.Save(); err != nil {
+		debugLogger.Printf("saving recent filters: %v", err)
+		return
+	}
+	// our own write, the watcher has nothing to reload
+	m.configStamp = statConfig()
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// filterLine is shown after the page indicator
+func (m model) filterLine() string {
+	count := fmt.Sprintf("(%d of %d)", len(m.containers), len(m.allContainers))
+	if m.filterBar.editing {
+		line := "  Filter: " + m.filterBar.input + "_  "
+		if m.filterBar.err != nil {
+			return line + "! " + m.filterBar.err.Error()
+		}
+		return line + count
+	}
+	if m.filter.Empty() {
+		return ""
+	}
+	return "  Filter: " + m.filter.String() + "  " + count
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/shubh-io/dockmate/internal/keymap"
 )
 
 // renderHelp shows a full-screen help view with all keyboard shortcuts
@@ -12,26 +13,25 @@ func (m model) renderHelp(width int) string {
 	return m.helpList.View()
 }
 
+// getHelpItems lists the active keymap, the views and the logs panel
+// included, then the keys that aren't bindings
 func getHelpItems(m model) []list.Item {
-	return []list.Item{
-		item{"↑ / ↓", "Move cursor up/down"},
-		item{"← / →", "Navigate between pages"},
-		item{"Tab", "Toggle column selection mode"},
-		item{"Enter", "Sort by selected column (in column mode)"},
-		item{"S", "Start selected container"},
-		item{"X", "Stop selected container"},
-		item{"R", "Restart selected container"},
-		item{"D", "Remove selected container"},
-		item{"E", fmt.Sprintf("Open interactive shell (%s)", m.settings.Shell)},
-		item{"L", "View/Toggle container logs"},
-		item{"I", "View/Toggle container info"},
-		item{"C", "Toggle compose/normal view"},
-		item{"F2", "Open settings"},
-		item{"F1", "Show this help"},
-		item{"q", "Quit application"},
-		item{"Esc", "Back/Cancel"},
+	bindings := m.keys.byName()
+	var items []list.Item
+	for _, a := range keymap.Actions {
+		desc := a.Help
+		if a.Name == "exec" {
+			desc = fmt.Sprintf("%s (%s)", desc, m.settings.Shell)
+		}
+		items = append(items, item{helpLabel(*bindings[a.Name]), desc})
 	}
 
+	k := m.keys
+	marked := label(k.Start, k.Stop, k.Restart, k.Pause, k.Kill, k.StopWait, k.Remove)
+	return append(items,
+		item{marked, "With rows marked: act on all of them, 4 at a time, then show a summary"},
+		item{"↑ / ↓", "Filter bar: recall recent filters"},
+	)
 }
 
 type item struct {
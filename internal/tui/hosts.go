@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Host switcher (`H`)
+// ============================================================================
+
+// openHost connects to a host from the config, tests swap in a fake
+var openHost = docker.OpenHost
+
+// connector opens the hosts of cfg, it's what the switcher and reloads use
+func connector(cfg *config.Config) func(string) (docker.Runtime, error) {
+	return func(host string) (docker.Runtime, error) {
+		return openHost(cfg, host)
+	}
+}
+
+type hostSwitchedMsg struct {
+	host string
+	rt   docker.Runtime
+	err  error
+}
+
+// connecting can mean a ping or an ssh handshake, so it happens off the update loop
+func switchHostCmd(connect func(string) (docker.Runtime, error), host string) tea.Cmd {
+	return func() tea.Msg {
+		rt, err := connect(host)
+		return hostSwitchedMsg{host: host, rt: rt, err: err}
+	}
+}
+
+// hostLabel is the current host for the header and the switcher
+func (m model) hostLabel() string {
+	if m.host == "" {
+		return docker.LocalHost
+	}
+	return m.host
+}
+
+// openHostDialog picks one of the configured hosts, or all of them at once
+func (m *model) openHostDialog() {
+	if len(m.hostNames) < 2 {
+		m.statusMessage = "No other hosts, add them under hosts: in the config"
+		return
+	}
+	m.actionDialog = &actionDialog{
+		title:   "Switch host",
+		choices: m.hostNames,
+		focus:   max(0, slices.Index(m.hostNames, m.hostLabel())),
+		run: func(m *model, host string) (tea.Cmd, error) {
+			if host == m.hostLabel() {
+				return nil, nil
+			}
+			m.statusMessage = fmt.Sprintf("Connecting to %s...", host)
+			return switchHostCmd(m.connect, host), nil
+		},
+	}
+}
+
+// useHost swaps the runtime and starts over: streams are closed, the list,
+// marks and stats history all belonged to the old host
+func (m *model) useHost(host string, rt docker.Runtime) tea.Cmd {
+	m.stopEvents()
+	m.closeLogs()
+	m.closeCompose()
+	m.infoVisible = false
+	m.infoContainer = nil
+	m.infoContainerID = ""
+	if m.currentMode != modeComposeView {
+		m.currentMode = modeNormal
+	}
+
+	m.rt = rt
+	m.host = host
+	m.allContainers = nil
+	m.containers = nil
+	m.projects = make(map[string]*docker.ComposeProject)
+	m.flatList = []treeRow{}
+	m.statsHistory = nil
+	m.details = make(map[string]containerDetails)
+	m.marked = make(map[string]bool)
+	m.cursor, m.page = 0, 0
+	m.loading = true
+	m.statusMessage = "Switched to " + host
+	m.updatePagination()
+
+	cmds := []tea.Cmd{fetchContainers(rt), subscribeEvents(rt)}
+	if m.composeViewMode {
+		cmds = append(cmds, fetchComposeProjects(rt))
+	}
+	return tea.Batch(cmds...)
+}
+
+// unreachableNote says which hosts didn't answer in the aggregated view
+func (m model) unreachableNote() string {
+	multi, ok := m.rt.(interface{ Unreachable() []string })
+	if !ok {
+		return ""
+	}
+	down := multi.Unreachable()
+	if len(down) == 0 {
+		return ""
+	}
+	return "Unreachable: " + strings.Join(down, ", ")
+}
+
+// hostColumnWidth is the HOST column, only there with every host in one list
+func (m model) hostColumnWidth() int {
+	if m.host != docker.AllHosts {
+		return 0
+	}
+	w := len("HOST")
+	for _, name := range m.hostNames {
+		w = max(w, visibleLen(name))
+	}
+	return min(w, 16) + 2
+}
+
+// hostCell is a container's HOST column, empty outside the aggregated view
+func (m model) hostCell(c *docker.Container) string {
+	w := m.hostColumnWidth()
+	if w == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %-*s", w-1, truncateToWidth(c.Host, w-2))
+}
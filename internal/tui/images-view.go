@@ -0,0 +1,601 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Images view
+// ============================================================================
+
+// rows the pull progress panel takes at the bottom of the images view
+const pullPanelHeight = 7
+
+// imagesView is everything the images mode keeps between frames
+type imagesView struct {
+	images  []docker.Image
+	cursor  int
+	scroll  int
+	loading bool
+	err     error
+
+	confirmPrune bool   // prune preview is showing, y runs it
+	prompt       bool   // reading an image ref to pull
+	input        string // text typed into the pull prompt
+
+	pull *pullState // nil when no pull has run yet
+}
+
+// pullState tracks one docker pull
+type pullState struct {
+	ref      string
+	layers   []string // layer ids in the order they showed up
+	progress map[string]docker.PullProgress
+	status   string // latest message about the whole image
+	err      string
+	stream   <-chan docker.PullProgress
+	cancel   context.CancelFunc
+	done     bool
+}
+
+type imagesMsg struct {
+	images []docker.Image
+	err    error
+}
+
+type imageRemovedMsg struct {
+	ref string
+	err error
+}
+
+type imagesPrunedMsg struct {
+	report docker.PruneReport
+	err    error
+}
+
+type pullStartedMsg struct {
+	ref    string
+	ch     <-chan docker.PullProgress
+	cancel context.CancelFunc
+	err    error
+}
+
+type pullProgressMsg struct {
+	ch      <-chan docker.PullProgress
+	updates []docker.PullProgress
+	closed  bool
+}
+
+func fetchImages(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		images, err := rt.ListImages()
+		return imagesMsg{images: images, err: err}
+	}
+}
+
+func removeImageCmd(rt docker.Runtime, img docker.Image, force bool) tea.Cmd {
+	return func() tea.Msg {
+		// remove by tag so an image with several tags only loses this one
+		return imageRemovedMsg{ref: img.Ref(), err: rt.RemoveImage(img.Ref(), force)}
+	}
+}
+
+func pruneImagesCmd(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		report, err := rt.PruneImages()
+		return imagesPrunedMsg{report: report, err: err}
+	}
+}
+
+func startPullCmd(rt docker.Runtime, ref string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := rt.PullImage(ctx, ref)
+		if err != nil {
+			cancel()
+			return pullStartedMsg{ref: ref, err: err}
+		}
+		return pullStartedMsg{ref: ref, ch: ch, cancel: cancel}
+	}
+}
+
+// wait for the next progress update, then grab whatever else is queued
+func waitForPull(ch <-chan docker.PullProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return pullProgressMsg{ch: ch, closed: true}
+		}
+		updates := []docker.PullProgress{p}
+		for {
+			select {
+			case p, ok := <-ch:
+				if !ok {
+					return pullProgressMsg{ch: ch, updates: updates, closed: true}
+				}
+				updates = append(updates, p)
+			default:
+				return pullProgressMsg{ch: ch, updates: updates}
+			}
+		}
+	}
+}
+
+func (m *model) openImages() tea.Cmd {
+	m.closeLogs()
+	m.infoVisible = false
+	m.columnMode = false
+	m.currentMode = modeImages
+	m.imagesView.loading = true
+	m.imagesView.confirmPrune = false
+	m.imagesView.prompt = false
+	m.statusMessage = "Loading images..."
+	return fetchImages(m.rt)
+}
+
+func (m *model) closeImages() {
+	m.currentMode = modeNormal
+	if m.composeViewMode {
+		m.currentMode = modeComposeView
+	}
+	m.updatePagination()
+	m.statusMessage = "Images closed"
+}
+
+// stopPull cancels a running pull, if there is one
+func (m *model) stopPull() {
+	if p := m.imagesView.pull; p != nil && p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+		p.stream = nil
+	}
+}
+
+func (m model) selectedImage() *docker.Image {
+	v := m.imagesView
+	if v.cursor < 0 || v.cursor >= len(v.images) {
+		return nil
+	}
+	return &v.images[v.cursor]
+}
+
+// danglingImages returns how many dangling images a prune would remove and their size
+func (m model) danglingImages() (int, int64) {
+	n, size := 0, int64(0)
+	for _, img := range m.imagesView.images {
+		if img.Dangling {
+			n++
+			size += img.Size
+		}
+	}
+	return n, size
+}
+
+// rows that fit between the header and the status line
+func (m model) imagesPageSize() int {
+	h := m.terminalHeight - 5
+	if m.imagesView.pull != nil {
+		h -= pullPanelHeight
+	}
+	return max(1, h)
+}
+
+func (m *model) clampImagesCursor() {
+	v := &m.imagesView
+	if v.cursor >= len(v.images) {
+		v.cursor = len(v.images) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+	page := m.imagesPageSize()
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+page {
+		v.scroll = v.cursor - page + 1
+	}
+	if v.scroll < 0 {
+		v.scroll = 0
+	}
+}
+
+func (m model) handleImagesMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	v := &m.imagesView
+
+	switch msg := msg.(type) {
+	case imagesMsg:
+		v.loading = false
+		v.err = msg.err
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error listing images: %v", msg.err)
+			return m, nil
+		}
+		// keep the cursor on the same image across refreshes
+		var selected string
+		if img := m.selectedImage(); img != nil {
+			selected = img.ID + img.Ref()
+		}
+		v.images = msg.images
+		for i, img := range v.images {
+			if img.ID+img.Ref() == selected {
+				v.cursor = i
+			}
+		}
+		if m.statusMessage == "Loading images..." {
+			m.statusMessage = ""
+		}
+		m.clampImagesCursor()
+		return m, nil
+
+	case imageRemovedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error removing %s: %v", msg.ref, msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Removed %s", msg.ref)
+		}
+		return m, fetchImages(m.rt)
+
+	case imagesPrunedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error pruning images: %v", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Pruned %d images, reclaimed %s", len(msg.report.Deleted), docker.FormatBytes(msg.report.SpaceReclaimed))
+		}
+		return m, fetchImages(m.rt)
+
+	case pullStartedMsg:
+		p := v.pull
+		if p == nil || p.ref != msg.ref || p.stream != nil {
+			// superseded by another pull
+			if msg.cancel != nil {
+				msg.cancel()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			p.done = true
+			return m, nil
+		}
+		p.stream = msg.ch
+		p.cancel = msg.cancel
+		return m, waitForPull(msg.ch)
+
+	case pullProgressMsg:
+		p := v.pull
+		if p == nil || msg.ch != p.stream {
+			return m, nil
+		}
+		for _, u := range msg.updates {
+			p.apply(u)
+		}
+		if !msg.closed {
+			return m, waitForPull(msg.ch)
+		}
+		m.stopPull()
+		p.done = true
+		if p.err == "" {
+			m.statusMessage = fmt.Sprintf("Pulled %s", p.ref)
+		} else {
+			m.statusMessage = fmt.Sprintf("Pull of %s failed", p.ref)
+		}
+		return m, fetchImages(m.rt)
+	}
+	return m, nil
+}
+
+// apply folds one progress update into the pull state
+func (p *pullState) apply(u docker.PullProgress) {
+	if u.Error != "" {
+		p.err = u.Error
+		return
+	}
+	if u.ID == "" {
+		p.status = u.Status
+		return
+	}
+	if _, ok := p.progress[u.ID]; !ok {
+		p.layers = append(p.layers, u.ID)
+	}
+	p.progress[u.ID] = u
+}
+
+// pull starts pulling ref, replacing any pull still running
+func (m *model) pull(ref string) tea.Cmd {
+	m.stopPull()
+	m.imagesView.pull = &pullState{
+		ref:      ref,
+		progress: map[string]docker.PullProgress{},
+		status:   "Starting pull...",
+	}
+	m.clampImagesCursor()
+	return startPullCmd(m.rt, ref)
+}
+
+func (m *model) handleImagesKey(msg tea.KeyMsg) tea.Cmd {
+	v := &m.imagesView
+
+	if msg.String() == "ctrl+c" {
+		return m.quit()
+	}
+
+	// pull prompt reads text until enter/esc
+	if v.prompt {
+		switch msg.Type {
+		case tea.KeyEnter:
+			v.prompt = false
+			ref := strings.TrimSpace(v.input)
+			if ref == "" {
+				return nil
+			}
+			return m.pull(ref)
+		case tea.KeyEsc:
+			v.prompt = false
+		case tea.KeyBackspace:
+			if r := []rune(v.input); len(r) > 0 {
+				v.input = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			v.input += string(msg.Runes)
+		}
+		return nil
+	}
+
+	// prune preview only takes yes, anything else backs out
+	if v.confirmPrune {
+		v.confirmPrune = false
+		if key.Matches(msg, m.keys.Confirm) {
+			m.statusMessage = "Pruning dangling images..."
+			return pruneImagesCmd(m.rt)
+		}
+		m.statusMessage = "Prune cancelled"
+		return nil
+	}
+
+	// the key that opened the view closes it again
+	if key.Matches(msg, m.keys.Images) {
+		m.closeImages()
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.quit()
+	case key.Matches(msg, m.keys.Back):
+		if p := v.pull; p != nil && p.done {
+			// first esc just dismisses a finished pull
+			v.pull = nil
+			m.clampImagesCursor()
+			return nil
+		}
+		m.closeImages()
+		return nil
+	case key.Matches(msg, m.keys.Up):
+		v.cursor--
+	case key.Matches(msg, m.keys.Down):
+		v.cursor++
+	case key.Matches(msg, m.keys.PrevPage):
+		v.cursor -= m.imagesPageSize()
+	case key.Matches(msg, m.keys.NextPage):
+		v.cursor += m.imagesPageSize()
+	case key.Matches(msg, m.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		v.cursor = len(v.images) - 1
+	case key.Matches(msg, m.keys.Reload):
+		m.statusMessage = "Loading images..."
+		return fetchImages(m.rt)
+	case key.Matches(msg, m.keys.Select, m.keys.Inspect):
+		if img := m.selectedImage(); img != nil {
+			return m.openInspect(inspectImage, img.ID)
+		}
+	case key.Matches(msg, m.keys.PullImage):
+		if img := m.selectedImage(); img != nil {
+			if img.Dangling {
+				m.statusMessage = "Dangling images have no tag to pull"
+				return nil
+			}
+			return m.pull(img.Ref())
+		}
+	case key.Matches(msg, m.keys.PullNew):
+		v.prompt = true
+		v.input = ""
+	case key.Matches(msg, m.keys.CancelPull):
+		if p := v.pull; p != nil && !p.done {
+			m.stopPull()
+			p.done = true
+			p.err = "cancelled"
+			m.statusMessage = fmt.Sprintf("Pull of %s cancelled", p.ref)
+		}
+	case key.Matches(msg, m.keys.Delete):
+		if img := m.selectedImage(); img != nil {
+			m.confirmRemoveImage(*img)
+		}
+	case key.Matches(msg, m.keys.Prune):
+		if n, _ := m.danglingImages(); n == 0 {
+			m.statusMessage = "No dangling images to prune"
+			return nil
+		}
+		v.confirmPrune = true
+	}
+	m.clampImagesCursor()
+	return nil
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// timeAgo formats t like docker does ("3 days ago")
+func timeAgo(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return "Less than a minute ago"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 48*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	case d < 14*24*time.Hour:
+		return plural(int(d.Hours()/24), "day")
+	case d < 60*24*time.Hour:
+		return plural(int(d.Hours()/24/7), "week")
+	case d < 2*365*24*time.Hour:
+		return plural(int(d.Hours()/24/30), "month")
+	}
+	return plural(int(d.Hours()/24/365), "year")
+}
+
+func (m model) renderImages(width int) string {
+	var b strings.Builder
+	v := m.imagesView
+
+	b.WriteString(m.renderTitleBar(width))
+	b.WriteString("\n")
+
+	dangling, reclaim := m.danglingImages()
+	var total int64
+	seen := map[string]bool{}
+	for _, img := range v.images {
+		// tags share the image, only count it once
+		if !seen[img.ID] {
+			seen[img.ID] = true
+			total += img.Size
+		}
+	}
+	title := fmt.Sprintf("Images: %d (%s)  Dangling: %d (%s reclaimable) ", len(v.images), docker.FormatBytes(total), dangling, docker.FormatBytes(reclaim))
+	if v.loading && len(v.images) == 0 {
+		title = "Images: loading... "
+	}
+	if visibleLen(title) < width {
+		title += strings.Repeat(" ", width-visibleLen(title))
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	// columns: repo:tag gets what's left over
+	idW, sizeW, createdW := 12, 10, 16
+	usedW := max(16, (width-idW-sizeW-createdW)/4)
+	refW := max(20, width-idW-sizeW-createdW-usedW-6)
+	formatRow := func(ref, id, size, created, used string) string {
+		cells := []string{
+			padRight(truncateToWidth(ref, refW), refW),
+			padRight(truncateToWidth(id, idW), idW),
+			padRight(truncateToWidth(size, sizeW), sizeW),
+			padRight(truncateToWidth(created, createdW), createdW),
+			truncateToWidth(used, usedW),
+		}
+		line := " " + strings.Join(cells, " ")
+		if visibleLen(line) < width {
+			line += strings.Repeat(" ", width-visibleLen(line))
+		}
+		return line
+	}
+
+	b.WriteString(headerStyle.Render(formatRow("REPOSITORY:TAG", "IMAGE ID", "SIZE", "CREATED", "USED BY")))
+	b.WriteString("\n")
+
+	page := m.imagesPageSize()
+	end := min(len(v.images), v.scroll+page)
+	for i := v.scroll; i < end; i++ {
+		img := v.images[i]
+		ref := img.Ref()
+		if img.Dangling {
+			ref = "<none>:<none>"
+		}
+		used := "-"
+		if len(img.Containers) > 0 {
+			used = strings.Join(img.Containers, ", ")
+		}
+		line := formatRow(ref, img.ID, docker.FormatBytes(img.Size), timeAgo(img.Created), used)
+
+		switch {
+		case i == v.cursor:
+			b.WriteString(selectedStyle.Render(line))
+		case img.Dangling:
+			b.WriteString(stoppedStyle.Render(line))
+		case len(img.Containers) > 0:
+			b.WriteString(runningStyle.Render(line))
+		default:
+			b.WriteString(normalStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	for i := end - v.scroll; i < page; i++ {
+		b.WriteString("\n")
+	}
+
+	if v.pull != nil {
+		b.WriteString(m.renderPullPanel(width))
+	}
+
+	// prompts and confirmations take over the status line
+	status := m.statusMessage
+	switch {
+	case v.prompt:
+		status = "Pull image: " + v.input + "█"
+	case v.confirmPrune:
+		status = fmt.Sprintf("Prune %d dangling images and reclaim %s? (%s/n)", dangling, docker.FormatBytes(reclaim), label(m.keys.Confirm))
+	case status == "" && len(v.images) > page:
+		status = fmt.Sprintf("%d-%d of %d", v.scroll+1, end, len(v.images))
+	}
+	b.WriteString(normalStyle.Render(" " + status))
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter(width))
+	return b.String()
+}
+
+// renderPullPanel shows the newest layers of the current pull, pullPanelHeight lines
+func (m model) renderPullPanel(width int) string {
+	p := m.imagesView.pull
+	var lines []string
+
+	head := fmt.Sprintf("Pulling %s", p.ref)
+	switch {
+	case p.err != "":
+		head = fmt.Sprintf("Pull %s: %s", p.ref, p.err)
+	case p.done:
+		head = fmt.Sprintf("Pulled %s", p.ref)
+	}
+	lines = append(lines, dividerStyle.Render(strings.Repeat("─", width)))
+	lines = append(lines, infoLabelStyle.Render(" "+truncateToWidth(head, width-2)))
+
+	// newest layers last, the panel has room for pullPanelHeight-3 of them
+	layers := p.layers
+	if room := pullPanelHeight - 3; len(layers) > room {
+		layers = layers[len(layers)-room:]
+	}
+	for _, id := range layers {
+		u := p.progress[id]
+		line := fmt.Sprintf("  %s  %-18s", id, u.Status)
+		if u.Total > 0 {
+			const barW = 20
+			done := int(float64(barW) * float64(u.Current) / float64(u.Total))
+			done = min(max(done, 0), barW)
+			line += fmt.Sprintf(" [%s%s] %s / %s", strings.Repeat("=", done), strings.Repeat(" ", barW-done), docker.FormatBytes(u.Current), docker.FormatBytes(u.Total))
+		}
+		lines = append(lines, normalStyle.Render(truncateToWidth(line, width-1)))
+	}
+	for len(lines) < pullPanelHeight-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, normalStyle.Render(" "+truncateToWidth(p.status, width-2)))
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -34,9 +34,9 @@ func (m model) renderInfoPanel(width int) string {
 			}
 		}
 		if container == nil {
-			for i := range m.containers {
-				if m.containers[i].ID == id {
-					container = &m.containers[i]
+			for i := range m.allContainers {
+				if m.allContainers[i].ID == id {
+					container = &m.allContainers[i]
 					break
 				}
 			}
@@ -75,10 +75,11 @@ func (m model) renderInfoPanel(width int) string {
 		{"Image", container.Image},
 		{"Status", container.Status},
 		{"State", container.State},
-		{"CPU Usage", container.CPU},
-		{"Memory Usage", container.Memory},
-		{"Network I/O", container.NetIO},
-		{"Block I/O", container.BlockIO},
+		{"CPU Usage", cpuText(container.Stats)},
+		{"Memory Usage", m.memUsage(container.Stats)},
+		{"Network I/O", m.netText(container.Stats)},
+		{"Block I/O", m.blockText(container.Stats)},
+		{"PIDs", pidsText(container.Stats)},
 		{"Ports", container.Ports},
 		// {"Compose Project", container.ComposeProject},
 		// {"Compose File Directory", container.ComposeFileDirectory},
@@ -120,7 +121,18 @@ func (m model) renderInfoPanel(width int) string {
 	if maxInfoLines < 1 {
 		maxInfoLines = 1
 	}
+	// stats charts go on the right when there's room for them
+	fieldsWidth := width
+	var charts []string
+	if width/2 >= minChartWidth {
+		charts = m.renderStatsCharts(container.ID, width/2-2)
+	}
+	if charts != nil {
+		fieldsWidth = width - width/2
+	}
+
 	// Render info fields with wrapping
+	var lines []string
 	renderedLines := 0
 	for _, field := range infoFields {
 		if renderedLines >= maxInfoLines {
@@ -135,16 +147,15 @@ func (m model) renderInfoPanel(width int) string {
 		labelRendered := infoLabelStyle.Render(field.label)
 		labelPart := fmt.Sprintf("  %s: ", labelRendered)
 
-		valueMaxWidth := width - visibleLen(labelPart)
+		valueMaxWidth := fieldsWidth - visibleLen(labelPart)
 
 		if valueMaxWidth <= 0 {
 			if renderedLines < maxInfoLines {
 				line := labelPart
-				if visibleLen(line) < width {
-					line += strings.Repeat(" ", width-visibleLen(line))
+				if visibleLen(line) < fieldsWidth {
+					line += strings.Repeat(" ", fieldsWidth-visibleLen(line))
 				}
-				b.WriteString(normalStyle.Render(line))
-				b.WriteString("\n")
+				lines = append(lines, normalStyle.Render(line))
 				renderedLines++
 			}
 			continue
@@ -156,21 +167,19 @@ func (m model) renderInfoPanel(width int) string {
 		if len(valueLines) > 0 {
 			if renderedLines < maxInfoLines {
 				line := labelPart + infoValueStyle.Render(valueLines[0])
-				if visibleLen(line) < width {
-					line += strings.Repeat(" ", width-visibleLen(line))
+				if visibleLen(line) < fieldsWidth {
+					line += strings.Repeat(" ", fieldsWidth-visibleLen(line))
 				}
-				b.WriteString(normalStyle.Render(line))
-				b.WriteString("\n")
+				lines = append(lines, normalStyle.Render(line))
 				renderedLines++
 			}
 		} else {
 			if renderedLines < maxInfoLines {
 				line := labelPart
-				if visibleLen(line) < width {
-					line += strings.Repeat(" ", width-visibleLen(line))
+				if visibleLen(line) < fieldsWidth {
+					line += strings.Repeat(" ", fieldsWidth-visibleLen(line))
 				}
-				b.WriteString(normalStyle.Render(line))
-				b.WriteString("\n")
+				lines = append(lines, normalStyle.Render(line))
 				renderedLines++
 			}
 		}
@@ -183,11 +192,10 @@ func (m model) renderInfoPanel(width int) string {
 					break
 				}
 				line := indent + infoValueStyle.Render(valueLines[i])
-				if visibleLen(line) < width {
-					line += strings.Repeat(" ", width-visibleLen(line))
+				if visibleLen(line) < fieldsWidth {
+					line += strings.Repeat(" ", fieldsWidth-visibleLen(line))
 				}
-				b.WriteString(normalStyle.Render(line))
-				b.WriteString("\n")
+				lines = append(lines, normalStyle.Render(line))
 				renderedLines++
 			}
 		}
@@ -195,7 +203,16 @@ func (m model) renderInfoPanel(width int) string {
 
 	// Fill remaining lines with empty space
 	for i := renderedLines; i < maxInfoLines; i++ {
-		b.WriteString(normalStyle.Render(strings.Repeat(" ", width)))
+		lines = append(lines, normalStyle.Render(strings.Repeat(" ", fieldsWidth)))
+	}
+
+	for i, line := range lines {
+		if i < len(charts) {
+			line += padRight(charts[i], width-fieldsWidth)
+		} else if charts != nil {
+			line += strings.Repeat(" ", width-fieldsWidth)
+		}
+		b.WriteString(line)
 		b.WriteString("\n")
 	}
 
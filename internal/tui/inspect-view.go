@@ -0,0 +1,529 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Inspect view (full screen `docker inspect` tree)
+// ============================================================================
+
+type inspectMsg struct {
+	id    string
+	title string
+	tree  []*inspectNode
+	err   error
+}
+
+// what the inspect view is showing
+type inspectKind int
+
+const (
+	inspectContainer inspectKind = iota
+	inspectImage
+)
+
+// fetchInspectCmd loads the document and lays it out off the UI goroutine
+func fetchInspectCmd(rt docker.Runtime, kind inspectKind, id string) tea.Cmd {
+	return func() tea.Msg {
+		switch kind {
+		case inspectImage:
+			data, err := rt.InspectImage(id)
+			if err != nil {
+				return inspectMsg{id: id, err: err}
+			}
+			title := data.ID
+			if len(data.RepoTags) > 0 {
+				title = fmt.Sprintf("%s (%s)", data.RepoTags[0], data.ID)
+			}
+			return inspectMsg{id: id, title: title, tree: buildImageInspectTree(data)}
+		default:
+			data, err := rt.Inspect(id)
+			if err != nil {
+				return inspectMsg{id: id, err: err}
+			}
+			return inspectMsg{id: id, title: fmt.Sprintf("%s (%s)", data.Name, data.ID), tree: buildInspectTree(data)}
+		}
+	}
+}
+
+// env var names that usually hold credentials
+var secretEnvPattern = regexp.MustCompile(`(?i)(pass|secret|token|key|credential|private|auth|dsn)`)
+
+const maskedValue = "••••••••"
+
+// inspectNode is one line of the tree, sections have children
+type inspectNode struct {
+	path     string // unique, used for collapse/reveal state
+	key      string
+	value    string
+	secret   bool
+	children []*inspectNode
+}
+
+// inspectRow is a visible node after collapsed sections are skipped
+type inspectRow struct {
+	node  *inspectNode
+	depth int
+}
+
+func leaf(key, value string) *inspectNode {
+	return &inspectNode{key: key, value: value}
+}
+
+// section groups children, the count shows next to the name
+func section(key string, children ...*inspectNode) *inspectNode {
+	return &inspectNode{key: key, value: fmt.Sprintf("(%d)", len(children)), children: children}
+}
+
+// group is a section without a count, for fixed sets of fields
+func group(key string, children ...*inspectNode) *inspectNode {
+	return &inspectNode{key: key, children: children}
+}
+
+// assignPaths gives every node a stable path so collapse state survives a refresh
+func assignPaths(nodes []*inspectNode, parent string) {
+	for _, n := range nodes {
+		n.path = parent + "/" + n.key
+		assignPaths(n.children, n.path)
+	}
+}
+
+func formatInspectTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// shellJoin renders an exec-form command the way you'd type it
+func shellJoin(args []string) string {
+	if len(args) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"'$") {
+			a = fmt.Sprintf("%q", a)
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// buildInspectTree lays out the inspect document as sections
+func buildInspectTree(in *docker.ContainerInspect) []*inspectNode {
+	var tree []*inspectNode
+
+	state := []*inspectNode{
+		leaf("Status", in.State),
+		leaf("Exit Code", fmt.Sprint(in.ExitCode)),
+		leaf("Started", formatInspectTime(in.StartedAt)),
+		leaf("Finished", formatInspectTime(in.FinishedAt)),
+		leaf("Restarts", fmt.Sprint(in.RestartCount)),
+	}
+	if in.Pid > 0 {
+		state = append(state, leaf("PID", fmt.Sprint(in.Pid)))
+	}
+	if in.OOMKilled {
+		state = append(state, leaf("OOM Killed", "yes"))
+	}
+	if in.Error != "" {
+		state = append(state, leaf("Error", in.Error))
+	}
+	tree = append(tree, group("State", state...))
+
+	config := []*inspectNode{
+		leaf("Image", in.Image),
+		leaf("Created", formatInspectTime(in.Created)),
+		leaf("Entrypoint", shellJoin(in.Entrypoint)),
+		leaf("Cmd", shellJoin(in.Cmd)),
+	}
+	if in.WorkingDir != "" {
+		config = append(config, leaf("Working Dir", in.WorkingDir))
+	}
+	if in.User != "" {
+		config = append(config, leaf("User", in.User))
+	}
+	if in.Hostname != "" {
+		config = append(config, leaf("Hostname", in.Hostname))
+	}
+	policy := in.RestartPolicy
+	if policy == "" {
+		policy = "no"
+	}
+	if policy == "on-failure" && in.RestartMaxRetries > 0 {
+		policy = fmt.Sprintf("%s (max %d)", policy, in.RestartMaxRetries)
+	}
+	config = append(config, leaf("Restart Policy", policy))
+	tree = append(tree, group("Config", config...))
+
+	if in.Healthcheck != nil || in.Health != nil {
+		var health []*inspectNode
+		if h := in.Health; h != nil {
+			health = append(health,
+				leaf("Status", string(h.Status)),
+				leaf("Failing Streak", fmt.Sprint(h.FailingStreak)),
+				leaf("Last Check", formatInspectTime(h.LastCheck)),
+				leaf("Last Exit Code", fmt.Sprint(h.LastExitCode)),
+			)
+			if h.LastOutput != "" {
+				health = append(health, leaf("Last Output", strings.ReplaceAll(h.LastOutput, "\n", " ⏎ ")))
+			}
+			// newest first, that's the one you're after
+			var probes []*inspectNode
+			for i := len(h.Probes) - 1; i >= 0; i-- {
+				p := h.Probes[i]
+				probes = append(probes, leaf(formatInspectTime(p.Start),
+					fmt.Sprintf("exit %d  %s", p.ExitCode, dashed(strings.ReplaceAll(p.Output, "\n", " ⏎ ")))))
+			}
+			if len(probes) > 0 {
+				health = append(health, section("Probes", probes...))
+			}
+		}
+		if hc := in.Healthcheck; hc != nil {
+			health = append(health,
+				leaf("Test", shellJoin(hc.Test)),
+				leaf("Interval", hc.Interval.String()),
+				leaf("Timeout", hc.Timeout.String()),
+				leaf("Retries", fmt.Sprint(hc.Retries)),
+			)
+		}
+		tree = append(tree, group("Health", health...))
+	}
+
+	tree = append(tree, envSection(in.Env))
+
+	var mounts []*inspectNode
+	for _, mt := range in.Mounts {
+		source := mt.Source
+		if mt.Name != "" {
+			source = mt.Name
+		}
+		access := "ro"
+		if mt.ReadWrite {
+			access = "rw"
+		}
+		mounts = append(mounts, leaf(mt.Destination, fmt.Sprintf("%s %s (%s)", mt.Type, source, access)))
+	}
+	tree = append(tree, section("Mounts", mounts...))
+
+	var networks []*inspectNode
+	for _, n := range in.Networks {
+		fields := []*inspectNode{
+			leaf("IP", orDash(n.IPAddress)),
+			leaf("Gateway", orDash(n.Gateway)),
+			leaf("MAC", orDash(n.MacAddress)),
+		}
+		if n.IPv6Address != "" {
+			fields = append(fields, leaf("IPv6", n.IPv6Address))
+		}
+		if len(n.Aliases) > 0 {
+			fields = append(fields, leaf("Aliases", strings.Join(n.Aliases, ", ")))
+		}
+		networks = append(networks, group(n.Name, fields...))
+	}
+	tree = append(tree, section("Networks", networks...))
+
+	tree = append(tree, labelsSection(in.Labels))
+
+	assignPaths(tree, "")
+	return tree
+}
+
+// buildImageInspectTree lays out an image inspect document
+func buildImageInspectTree(in *docker.ImageInspect) []*inspectNode {
+	var tags []*inspectNode
+	for _, t := range in.RepoTags {
+		tags = append(tags, leaf(t, ""))
+	}
+	for _, d := range in.RepoDigests {
+		tags = append(tags, leaf(d, ""))
+	}
+
+	config := []*inspectNode{
+		leaf("Created", formatInspectTime(in.Created)),
+		leaf("Size", docker.FormatBytes(in.Size)),
+		leaf("Platform", orDash(strings.Trim(in.Os+"/"+in.Architecture, "/"))),
+		leaf("Layers", fmt.Sprint(in.Layers)),
+		leaf("Entrypoint", shellJoin(in.Entrypoint)),
+		leaf("Cmd", shellJoin(in.Cmd)),
+	}
+	if in.WorkingDir != "" {
+		config = append(config, leaf("Working Dir", in.WorkingDir))
+	}
+	if in.User != "" {
+		config = append(config, leaf("User", in.User))
+	}
+	if in.Author != "" {
+		config = append(config, leaf("Author", in.Author))
+	}
+	if len(in.ExposedPorts) > 0 {
+		config = append(config, leaf("Exposed Ports", strings.Join(in.ExposedPorts, ", ")))
+	}
+
+	tree := []*inspectNode{
+		section("Tags", tags...),
+		group("Config", config...),
+		envSection(in.Env),
+		labelsSection(in.Labels),
+	}
+	assignPaths(tree, "")
+	return tree
+}
+
+// envSection lists KEY=value pairs, masking the ones that look like credentials
+func envSection(vars []string) *inspectNode {
+	var env []*inspectNode
+	for _, kv := range vars {
+		k, v, _ := strings.Cut(kv, "=")
+		n := leaf(k, v)
+		n.secret = secretEnvPattern.MatchString(k)
+		env = append(env, n)
+	}
+	return section("Env", env...)
+}
+
+func labelsSection(labels map[string]string) *inspectNode {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var nodes []*inspectNode
+	for _, k := range keys {
+		nodes = append(nodes, leaf(k, labels[k]))
+	}
+	return section("Labels", nodes...)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// inspectRows flattens the tree, skipping children of collapsed sections
+func (m model) inspectRows() []inspectRow {
+	var rows []inspectRow
+	var walk func(nodes []*inspectNode, depth int)
+	walk = func(nodes []*inspectNode, depth int) {
+		for _, n := range nodes {
+			rows = append(rows, inspectRow{node: n, depth: depth})
+			if len(n.children) > 0 && !m.inspectCollapsed[n.path] {
+				walk(n.children, depth+1)
+			}
+		}
+	}
+	walk(m.inspectTree, 0)
+	return rows
+}
+
+// openInspect switches to the full screen inspect view for a container or image
+func (m *model) openInspect(kind inspectKind, id string) tea.Cmd {
+	m.inspectPrevMode = m.currentMode
+	m.currentMode = modeInspect
+	m.inspectKind = kind
+	m.inspectID = id
+	m.inspectTitle = id
+	m.inspectTree = nil
+	m.inspectCursor = 0
+	m.inspectScroll = 0
+	m.inspectRevealed = map[string]bool{}
+	m.statusMessage = "Inspecting..."
+	return fetchInspectCmd(m.rt, kind, id)
+}
+
+func (m *model) closeInspect() {
+	m.currentMode = m.inspectPrevMode
+	m.inspectTree = nil
+	m.statusMessage = "Inspect closed"
+}
+
+func (m *model) handleInspectMsg(msg inspectMsg) {
+	if m.currentMode != modeInspect || msg.id != m.inspectID {
+		return
+	}
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Inspect error: %v", msg.err)
+		return
+	}
+	m.statusMessage = ""
+	m.inspectTitle = msg.title
+	m.inspectTree = msg.tree
+	if m.inspectCollapsed == nil {
+		// labels are mostly compose noise, start them folded
+		m.inspectCollapsed = map[string]bool{}
+		for _, n := range m.inspectTree {
+			if n.key == "Labels" {
+				m.inspectCollapsed[n.path] = true
+			}
+		}
+	}
+	m.clampInspectCursor()
+}
+
+// rows that fit between the title and the footer
+func (m model) inspectPageSize() int {
+	return max(1, m.terminalHeight-5)
+}
+
+func (m *model) clampInspectCursor() {
+	rows := len(m.inspectRows())
+	if m.inspectCursor >= rows {
+		m.inspectCursor = rows - 1
+	}
+	if m.inspectCursor < 0 {
+		m.inspectCursor = 0
+	}
+	page := m.inspectPageSize()
+	if m.inspectCursor < m.inspectScroll {
+		m.inspectScroll = m.inspectCursor
+	}
+	if m.inspectCursor >= m.inspectScroll+page {
+		m.inspectScroll = m.inspectCursor - page + 1
+	}
+	if m.inspectScroll < 0 {
+		m.inspectScroll = 0
+	}
+}
+
+func (m *model) handleInspectKey(msg tea.KeyMsg) tea.Cmd {
+	rows := m.inspectRows()
+	var cur *inspectNode
+	if m.inspectCursor < len(rows) {
+		cur = rows[m.inspectCursor].node
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Inspect):
+		m.closeInspect()
+		return nil
+	case key.Matches(msg, m.keys.Quit):
+		return m.quit()
+	case key.Matches(msg, m.keys.Up):
+		m.inspectCursor--
+	case key.Matches(msg, m.keys.Down):
+		m.inspectCursor++
+	case key.Matches(msg, m.keys.PageUp):
+		m.inspectCursor -= m.inspectPageSize()
+	case key.Matches(msg, m.keys.PageDown):
+		m.inspectCursor += m.inspectPageSize()
+	case key.Matches(msg, m.keys.Top):
+		m.inspectCursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		m.inspectCursor = len(rows) - 1
+	case key.Matches(msg, m.keys.Select, m.keys.Toggle):
+		if cur != nil && len(cur.children) > 0 {
+			m.inspectCollapsed[cur.path] = !m.inspectCollapsed[cur.path]
+		}
+	case key.Matches(msg, m.keys.Collapse):
+		if cur == nil {
+			break
+		}
+		if len(cur.children) > 0 && !m.inspectCollapsed[cur.path] {
+			m.inspectCollapsed[cur.path] = true
+			break
+		}
+		// jump to the parent section
+		depth := rows[m.inspectCursor].depth
+		for i := m.inspectCursor - 1; i >= 0; i-- {
+			if rows[i].depth < depth {
+				m.inspectCursor = i
+				break
+			}
+		}
+	case key.Matches(msg, m.keys.Expand):
+		if cur != nil && len(cur.children) > 0 {
+			m.inspectCollapsed[cur.path] = false
+		}
+	case key.Matches(msg, m.keys.Reveal):
+		if cur != nil && cur.secret {
+			m.inspectRevealed[cur.path] = !m.inspectRevealed[cur.path]
+		}
+	case key.Matches(msg, m.keys.Reload):
+		m.statusMessage = "Inspecting..."
+		return fetchInspectCmd(m.rt, m.inspectKind, m.inspectID)
+	}
+	m.clampInspectCursor()
+	return nil
+}
+
+func (m model) renderInspect(width int) string {
+	var b strings.Builder
+
+	b.WriteString(m.renderTitleBar(width))
+	b.WriteString("\n")
+
+	title := fmt.Sprintf("Inspect: %s ", m.inspectTitle)
+	if visibleLen(title) < width {
+		title += strings.Repeat(" ", width-visibleLen(title))
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	rows := m.inspectRows()
+	page := m.inspectPageSize()
+	end := min(len(rows), m.inspectScroll+page)
+
+	for i := m.inspectScroll; i < end; i++ {
+		row := rows[i]
+		n := row.node
+		indent := strings.Repeat("  ", row.depth+1)
+
+		var line string
+		if len(n.children) > 0 {
+			marker := "▾ "
+			if m.inspectCollapsed[n.path] {
+				marker = "▸ "
+			}
+			line = indent + marker + n.key
+			if n.value != "" {
+				line += " " + n.value
+			}
+		} else {
+			value := n.value
+			if n.secret && !m.inspectRevealed[n.path] {
+				value = maskedValue
+			}
+			line = indent + "  " + n.key + ": " + value
+		}
+		if visibleLen(line) > width-1 {
+			line = truncateToWidth(line, width-1)
+		}
+		if visibleLen(line) < width {
+			line += strings.Repeat(" ", width-visibleLen(line))
+		}
+
+		switch {
+		case i == m.inspectCursor:
+			b.WriteString(selectedStyle.Render(line))
+		case len(n.children) > 0:
+			b.WriteString(infoLabelStyle.Render(line))
+		default:
+			b.WriteString(normalStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	for i := end - m.inspectScroll; i < page; i++ {
+		b.WriteString("\n")
+	}
+
+	status := m.statusMessage
+	if status == "" && len(rows) > page {
+		status = fmt.Sprintf("%d-%d of %d", m.inspectScroll+1, end, len(rows))
+	}
+	b.WriteString(normalStyle.Render(" " + status))
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter(width))
+	return b.String()
+}
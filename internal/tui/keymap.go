@@ -1,47 +1,212 @@
 package tui
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/keymap"
 )
 
 // ============================================================================
 // Keyboard shortcuts
 // ============================================================================
+//
+// the actions, their default keys and the presets live in internal/keymap,
+// this turns them into bindings. the footer and the help screen read their
+// labels from here so they always show the keys that actually work. the views
+// and dialogs match their keys against the same bindings
 
 type keyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Start    key.Binding
-	Stop     key.Binding
-	Restart  key.Binding
-	Logs     key.Binding
-	Info     key.Binding
-	Exec     key.Binding
-	Remove   key.Binding
-	Refresh  key.Binding
-	PageUp   key.Binding
-	NextPage key.Binding
-	PrevPage key.Binding
-	PageDown key.Binding
-	Quit     key.Binding
-	Help     key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	PrevPage    key.Binding
+	NextPage    key.Binding
+	ColumnMode  key.Binding
+	ColumnLeft  key.Binding
+	ColumnRight key.Binding
+	Select      key.Binding
+	Start       key.Binding
+	Stop        key.Binding
+	Restart     key.Binding
+	Pause       key.Binding
+	Kill        key.Binding
+	StopWait    key.Binding
+	Rename      key.Binding
+	Remove      key.Binding
+	Pull        key.Binding
+	Recreate    key.Binding
+	Mark        key.Binding
+	MarkAll     key.Binding
+	Exec        key.Binding
+	Logs        key.Binding
+	Info        key.Binding
+	Inspect     key.Binding
+	Filter      key.Binding
+	ComposeView key.Binding
+	Host        key.Binding
+	Refresh     key.Binding
+	Settings    key.Binding
+	Images      key.Binding
+	Volumes     key.Binding
+	Networks    key.Binding
+	Help        key.Binding
+	Debug       key.Binding
+	Back        key.Binding
+	Quit        key.Binding
+
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Top        key.Binding
+	Bottom     key.Binding
+	Follow     key.Binding
+	Timestamps key.Binding
+	Window     key.Binding
+	Search     key.Binding
+	NextMatch  key.Binding
+	PrevMatch  key.Binding
+	Expand     key.Binding
+	Collapse   key.Binding
+	Toggle     key.Binding
+	Reload     key.Binding
+	PullImage  key.Binding
+	PullNew    key.Binding
+	CancelPull key.Binding
+	Delete     key.Binding
+	Prune      key.Binding
+	Connect    key.Binding
+	Disconnect key.Binding
+	Reveal     key.Binding
+	MoveUp     key.Binding
+	MoveDown   key.Binding
+	Decrease   key.Binding
+	Increase   key.Binding
+	Save       key.Binding
+	OptionUp   key.Binding
+	OptionDown key.Binding
+	Confirm    key.Binding
+	Cancel     key.Binding
+}
+
+// byName maps the keymap action names to the bindings
+func (k *keyMap) byName() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up": &k.Up, "down": &k.Down, "prev_page": &k.PrevPage, "next_page": &k.NextPage,
+		"column_mode": &k.ColumnMode, "column_left": &k.ColumnLeft, "column_right": &k.ColumnRight,
+		"select": &k.Select, "start": &k.Start, "stop": &k.Stop, "restart": &k.Restart,
+		"pause": &k.Pause, "kill": &k.Kill, "stop_wait": &k.StopWait, "rename": &k.Rename,
+		"remove": &k.Remove, "pull": &k.Pull, "recreate": &k.Recreate, "mark": &k.Mark,
+		"mark_all": &k.MarkAll, "exec": &k.Exec, "logs": &k.Logs, "info": &k.Info,
+		"inspect": &k.Inspect, "filter": &k.Filter, "compose_view": &k.ComposeView,
+		"host": &k.Host, "refresh": &k.Refresh, "settings": &k.Settings, "images": &k.Images,
+		"volumes": &k.Volumes, "networks": &k.Networks, "help": &k.Help, "debug": &k.Debug,
+		"back": &k.Back, "quit": &k.Quit,
+
+		"page_up": &k.PageUp, "page_down": &k.PageDown, "top": &k.Top, "bottom": &k.Bottom,
+		"follow": &k.Follow, "timestamps": &k.Timestamps, "window": &k.Window, "search": &k.Search,
+		"next_match": &k.NextMatch, "prev_match": &k.PrevMatch, "expand": &k.Expand,
+		"collapse": &k.Collapse, "toggle": &k.Toggle, "reload": &k.Reload,
+		"pull_image": &k.PullImage, "pull_new": &k.PullNew, "cancel_pull": &k.CancelPull,
+		"delete": &k.Delete, "prune": &k.Prune, "connect": &k.Connect, "disconnect": &k.Disconnect,
+		"reveal": &k.Reveal, "move_up": &k.MoveUp, "move_down": &k.MoveDown,
+		"decrease": &k.Decrease, "increase": &k.Increase, "save": &k.Save,
+		"option_up": &k.OptionUp, "option_down": &k.OptionDown, "confirm": &k.Confirm,
+		"cancel": &k.Cancel,
+	}
+}
+
+// newKeyMap builds the bindings for a `keys:` config section. bad bindings
+// are dropped here, config.Validate is what reports them
+func newKeyMap(cfg config.KeysConfig) keyMap {
+	resolved, _ := keymap.Resolve(cfg.Preset, cfg.Overrides())
+	var k keyMap
+	for name, b := range k.byName() {
+		*b = key.NewBinding(key.WithKeys(resolved[name]...))
+	}
+	return k
+}
+
+// readIn reports whether msg is the key of an action read in scope
+func (k keyMap) readIn(msg tea.KeyMsg, scope keymap.Scope) bool {
+	bindings := k.byName()
+	for _, a := range keymap.Actions {
+		if a.Scopes&scope != 0 && key.Matches(msg, *bindings[a.Name]) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLabel is how a key is written in the footer and the help screen
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "Space"
+	case "tab":
+		return "Tab"
+	case "enter":
+		return "Enter"
+	case "esc":
+		return "Esc"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	case "home":
+		return "Home"
+	case "end":
+		return "End"
+	case "shift+up":
+		return "Shift+↑"
+	case "shift+down":
+		return "Shift+↓"
+	case "shift+tab":
+		return "Shift+Tab"
+	}
+	return k
+}
+
+// label is the first key of each binding for the footer: "↑↓", "s/x/r",
+// arrows next to each other go together: "Enter/←→"
+func label(bindings ...key.Binding) string {
+	var b strings.Builder
+	prevArrow := false
+	for _, binding := range bindings {
+		keys := binding.Keys()
+		if len(keys) == 0 {
+			continue
+		}
+		l := keyLabel(keys[0])
+		arrow := strings.Contains("↑↓←→", l)
+		if b.Len() > 0 && !(arrow && prevArrow) {
+			b.WriteString("/")
+		}
+		b.WriteString(l)
+		prevArrow = arrow
+	}
+	return b.String()
 }
 
-var Keys = keyMap{
-	Up:       key.NewBinding(key.WithKeys("up", "k")),
-	Down:     key.NewBinding(key.WithKeys("down", "j")),
-	Start:    key.NewBinding(key.WithKeys("s", "S")),
-	Stop:     key.NewBinding(key.WithKeys("x", "X")),
-	Logs:     key.NewBinding(key.WithKeys("l", "L")),
-	Info:     key.NewBinding(key.WithKeys("i", "I")),
-	Exec:     key.NewBinding(key.WithKeys("e", "E")),
-	Restart:  key.NewBinding(key.WithKeys("r", "R")),
-	Remove:   key.NewBinding(key.WithKeys("d", "D")),
-	Refresh:  key.NewBinding(key.WithKeys("f5")),
-	PageUp:   key.NewBinding(key.WithKeys("pgup", "left")),
-	NextPage: key.NewBinding(key.WithKeys("n", "pagedown")),
-	PrevPage: key.NewBinding(key.WithKeys("p", "pageup")),
-	PageDown: key.NewBinding(key.WithKeys("pgdown", "right")),
-	Quit:     key.NewBinding(key.WithKeys("q", "Q", "ctrl+c", "f10")),
-	Help:     key.NewBinding(key.WithKeys("f1", "?")),
+// helpLabel is every key of a binding for the help screen. s and S are the
+// same key to a reader, only S is shown
+func helpLabel(b key.Binding) string {
+	keys := b.Keys()
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if upper := strings.ToUpper(k); upper != k && len(k) == 1 && slices.Contains(keys, upper) {
+			continue
+		}
+		parts = append(parts, keyLabel(k))
+	}
+	return strings.Join(parts, " / ")
 }
@@ -1,10 +1,384 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/keymap"
 )
 
+// ============================================================================
+// Log stream
+// ============================================================================
+
+// most lines handed to Update per message, so a chatty container can't starve the UI
+const logBatchSize = 256
+
+type logsStartedMsg struct {
+	id     string
+	ch     <-chan docker.LogLine
+	cancel context.CancelFunc
+	err    error
+}
+
+type logLinesMsg struct {
+	ch     <-chan docker.LogLine
+	lines  []docker.LogLine
+	closed bool
+}
+
+// open a log stream for a container
+func startLogsCmd(rt docker.Runtime, id string, opts docker.LogOptions) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := rt.StreamLogs(ctx, id, opts)
+		if err != nil {
+			cancel()
+			return logsStartedMsg{id: id, err: err}
+		}
+		return logsStartedMsg{id: id, ch: ch, cancel: cancel}
+	}
+}
+
+// wait for the next line, then grab whatever else is already queued
+func waitForLogs(ch <-chan docker.LogLine) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return logLinesMsg{ch: ch, closed: true}
+		}
+		lines := []docker.LogLine{line}
+		for len(lines) < logBatchSize {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					return logLinesMsg{ch: ch, lines: lines, closed: true}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{ch: ch, lines: lines}
+			}
+		}
+		return logLinesMsg{ch: ch, lines: lines}
+	}
+}
+
+// openLogs (re)starts streaming logs for a container with the current window
+func (m *model) openLogs(id string) tea.Cmd {
+	m.stopLogs()
+	if m.logsBuf == nil {
+		m.logsBuf = newRing[docker.LogLine](LOG_BUFFER_LINES)
+	}
+	m.logsBuf.Reset()
+	m.logsContainer = id
+	m.logsVisible = true
+	m.logsScroll = 0
+	m.logsMatch = -1
+	// a closed window has nothing more to follow
+	m.logsFollow = m.logsUntil.IsZero()
+
+	opts := docker.LogOptions{Follow: m.logsFollow, Since: m.logsSince, Until: m.logsUntil}
+	if m.logsSince.IsZero() {
+		opts.Tail = LOG_TAIL_LINES
+	}
+	return startLogsCmd(m.rt, id, opts)
+}
+
+// stopLogs closes the log stream, if one is open
+func (m *model) stopLogs() {
+	if m.logsCancel != nil {
+		m.logsCancel()
+	}
+	m.logsStream = nil
+	m.logsCancel = nil
+}
+
+// closeLogs hides the panel and drops the stream
+func (m *model) closeLogs() {
+	m.stopLogs()
+	m.logsVisible = false
+	m.logsPrompt = logPromptNone
+}
+
+func (m *model) handleLogsMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case logsStartedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Logs error: %v", msg.err)
+			m.closeLogs()
+			m.currentMode = modeNormal
+			m.updatePagination()
+			return *m, nil
+		}
+		if !m.logsVisible || msg.id != m.logsContainer {
+			// panel was closed or switched while we were connecting
+			msg.cancel()
+			return *m, nil
+		}
+		m.logsStream = msg.ch
+		m.logsCancel = msg.cancel
+		m.statusMessage = ""
+		return *m, waitForLogs(m.logsStream)
+
+	case logLinesMsg:
+		if msg.ch != m.logsStream {
+			// an old stream we already replaced
+			return *m, nil
+		}
+		m.appendLogs(msg.lines)
+		if msg.closed {
+			m.stopLogs()
+			m.logsFollow = false
+			return *m, nil
+		}
+		return *m, waitForLogs(m.logsStream)
+	}
+	return *m, nil
+}
+
+// appendLogs pushes lines into the scrollback, keeping the view still when paused
+func (m *model) appendLogs(lines []docker.LogLine) {
+	dropped := m.logsBuf.Len() + len(lines) - m.logsBuf.Cap()
+	for _, l := range lines {
+		m.logsBuf.Push(l)
+	}
+	if dropped > 0 && m.logsMatch >= 0 {
+		m.logsMatch -= dropped
+		if m.logsMatch < 0 {
+			m.logsMatch = -1
+		}
+	}
+	if !m.logsFollow {
+		m.logsScroll += len(lines)
+	}
+	m.clampLogsScroll()
+}
+
+// ============================================================================
+// Scrolling & search
+// ============================================================================
+
+// how many log lines fit in the panel
+func (m model) logsPageSize() int {
+	return max(1, m.logPanelHeight-2) // divider and title
+}
+
+func (m *model) clampLogsScroll() {
+	limit := 0
+	if m.logsBuf != nil {
+		limit = max(0, m.logsBuf.Len()-m.logsPageSize())
+	}
+	if m.logsScroll > limit {
+		m.logsScroll = limit
+	}
+	if m.logsScroll < 0 {
+		m.logsScroll = 0
+	}
+}
+
+// scrollLogs moves the view by delta lines (positive = older). reaching the
+// bottom resumes follow, scrolling up pauses it.
+func (m *model) scrollLogs(delta int) {
+	m.logsScroll += delta
+	m.clampLogsScroll()
+	if m.logsScroll > 0 {
+		m.logsFollow = false
+	} else if m.logsStream != nil && m.logsUntil.IsZero() {
+		m.logsFollow = true
+	}
+}
+
+// findLogMatch searches from line `from` in direction dir (-1 older, +1 newer)
+func (m model) findLogMatch(from, dir int) int {
+	if m.logsSearch == "" || m.logsBuf == nil {
+		return -1
+	}
+	term := strings.ToLower(m.logsSearch)
+	for i := from; i >= 0 && i < m.logsBuf.Len(); i += dir {
+		if strings.Contains(strings.ToLower(m.logsBuf.At(i).Text), term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpToLogMatch scrolls so line i sits at the bottom of the panel
+func (m *model) jumpToLogMatch(i int) {
+	if i < 0 {
+		m.statusMessage = fmt.Sprintf("No match for %q", m.logsSearch)
+		return
+	}
+	m.logsMatch = i
+	m.logsFollow = false
+	m.logsScroll = m.logsBuf.Len() - 1 - i
+	m.clampLogsScroll()
+}
+
+// parseLogWindow reads "since [until]", each either a duration back from now
+// ("10m", "2h") or an RFC3339 timestamp
+func parseLogWindow(input string, now time.Time) (since, until time.Time, err error) {
+	fields := strings.Fields(input)
+	if len(fields) > 2 {
+		return since, until, fmt.Errorf("expected \"since [until]\", got %q", input)
+	}
+	parse := func(s string) (time.Time, error) {
+		if d, err := time.ParseDuration(s); err == nil {
+			return now.Add(-d), nil
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("%q is not a duration (10m) or RFC3339 time", s)
+	}
+	if len(fields) > 0 {
+		if since, err = parse(fields[0]); err != nil {
+			return
+		}
+	}
+	if len(fields) > 1 {
+		if until, err = parse(fields[1]); err != nil {
+			return
+		}
+		if until.Before(since) {
+			err = fmt.Errorf("until is before since")
+		}
+	}
+	return
+}
+
+// ============================================================================
+// Keys
+// ============================================================================
+
+// which prompt is reading keys in the logs panel
+type logPrompt int
+
+const (
+	logPromptNone logPrompt = iota
+	logPromptSearch
+	logPromptWindow
+)
+
+// handleLogsKey handles keys while the logs panel has focus, handled is false
+// for keys the normal bindings should get: the actions with the Logs scope.
+// anything else is swallowed
+func (m *model) handleLogsKey(msg tea.KeyMsg) (cmd tea.Cmd, handled bool) {
+	if m.logsPrompt != logPromptNone {
+		return m.handleLogsPromptKey(msg), true
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.scrollLogs(1)
+	case key.Matches(msg, m.keys.Down):
+		m.scrollLogs(-1)
+	case key.Matches(msg, m.keys.PageUp):
+		m.scrollLogs(m.logsPageSize())
+	case key.Matches(msg, m.keys.PageDown):
+		m.scrollLogs(-m.logsPageSize())
+	case key.Matches(msg, m.keys.Top):
+		m.scrollLogs(m.logsBuf.Len())
+	case key.Matches(msg, m.keys.Bottom):
+		m.scrollLogs(-m.logsBuf.Len())
+	case key.Matches(msg, m.keys.Follow):
+		if !m.logsUntil.IsZero() || m.logsStream == nil {
+			m.statusMessage = "Nothing to follow, the stream has ended"
+			return nil, true
+		}
+		m.logsFollow = !m.logsFollow
+		if m.logsFollow {
+			m.logsScroll = 0
+			m.statusMessage = "Following logs"
+		} else {
+			m.statusMessage = "Paused auto-scroll"
+		}
+	case key.Matches(msg, m.keys.Timestamps):
+		m.logsTimestamps = !m.logsTimestamps
+	case key.Matches(msg, m.keys.Search):
+		m.logsPrompt = logPromptSearch
+		m.logsInput = ""
+	case key.Matches(msg, m.keys.Window):
+		m.logsPrompt = logPromptWindow
+		m.logsInput = ""
+	case key.Matches(msg, m.keys.NextMatch, m.keys.PrevMatch):
+		if m.logsSearch == "" {
+			m.statusMessage = fmt.Sprintf("Nothing to jump to, %s to search first", label(m.keys.Search))
+			return nil, true
+		}
+		if key.Matches(msg, m.keys.NextMatch) {
+			m.jumpToLogMatch(m.findLogMatch(m.logsMatch+1, 1))
+		} else {
+			m.jumpToLogMatch(m.findLogMatch(m.logsMatch-1, -1))
+		}
+	default:
+		// the rest go to the container list, if they're read with the panel open
+		return nil, !m.keys.readIn(msg, keymap.Logs)
+	}
+	return nil, true
+}
+
+func (m *model) handleLogsPromptKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.logsPrompt == logPromptSearch {
+			m.logsSearch = ""
+			m.logsMatch = -1
+		}
+		m.logsPrompt = logPromptNone
+		return nil
+
+	case tea.KeyEnter:
+		prompt := m.logsPrompt
+		m.logsPrompt = logPromptNone
+		if prompt == logPromptWindow {
+			since, until, err := parseLogWindow(m.logsInput, time.Now())
+			if err != nil {
+				m.statusMessage = fmt.Sprintf("Window: %v", err)
+				return nil
+			}
+			m.logsSince, m.logsUntil = since, until
+			return m.openLogs(m.logsContainer)
+		}
+		return nil
+
+	case tea.KeyBackspace:
+		if len(m.logsInput) > 0 {
+			r := []rune(m.logsInput)
+			m.logsInput = string(r[:len(r)-1])
+		}
+
+	case tea.KeySpace:
+		m.logsInput += " "
+
+	case tea.KeyRunes:
+		m.logsInput += string(msg.Runes)
+
+	default:
+		return nil
+	}
+
+	// incremental search, jump to the newest match as you type
+	if m.logsPrompt == logPromptSearch {
+		m.logsSearch = m.logsInput
+		m.logsMatch = -1
+		if m.logsSearch != "" {
+			m.jumpToLogMatch(m.findLogMatch(m.logsBuf.Len()-1, -1))
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
 func (m model) renderLogsPanel(width int) string {
 	var b strings.Builder
 
@@ -12,32 +386,62 @@ func (m model) renderLogsPanel(width int) string {
 	b.WriteString("\n")
 
 	logsTitle := fmt.Sprintf("Logs: %s ", m.logsContainer)
+	switch {
+	case m.logsStream == nil && m.logsBuf != nil && m.logsBuf.Len() > 0:
+		logsTitle += "[ended] "
+	case m.logsFollow:
+		logsTitle += "[following] "
+	case m.logsStream != nil:
+		logsTitle += fmt.Sprintf("[paused, %d below] ", m.logsScroll)
+	}
+	if !m.logsSince.IsZero() || !m.logsUntil.IsZero() {
+		logsTitle += "[" + formatLogWindow(m.logsSince, m.logsUntil) + "] "
+	}
+	switch m.logsPrompt {
+	case logPromptSearch:
+		logsTitle += "/" + m.logsInput + "_"
+	case logPromptWindow:
+		logsTitle += "since [until]: " + m.logsInput + "_"
+	default:
+		if m.logsSearch != "" {
+			logsTitle += fmt.Sprintf("/%s", m.logsSearch)
+		}
+	}
 	if len(logsTitle) < width {
 		logsTitle += strings.Repeat(" ", width-len(logsTitle))
 	}
 	b.WriteString(titleStyle.Render(logsTitle))
 	b.WriteString("\n")
 
-	maxLogLines := m.logPanelHeight - 2 // account for divider and title
-	if maxLogLines < 1 {
-		maxLogLines = 1
-	}
+	maxLogLines := m.logsPageSize()
 
-	startLog := 0
-	if len(m.logsLines) > maxLogLines {
-		startLog = len(m.logsLines) - maxLogLines
+	total := 0
+	if m.logsBuf != nil {
+		total = m.logsBuf.Len()
 	}
+	end := total - m.logsScroll
+	startLog := max(0, end-maxLogLines)
 
-	for i := startLog; i < len(m.logsLines); i++ {
-		logLine := m.logsLines[i]
+	for i := startLog; i < end; i++ {
+		line := m.logsBuf.At(i)
+		logLine := line.Text
+		if m.logsTimestamps && !line.Time.IsZero() {
+			logLine = line.Time.Local().Format("2006-01-02 15:04:05") + "  " + logLine
+		}
 		if len(logLine) > width-4 {
-			logLine = logLine[:width-7] + "..."
+			logLine = logLine[:max(0, width-7)] + "..."
+		}
+
+		style := normalStyle
+		if line.Stream == "stderr" {
+			style = logStderrStyle
 		}
-		b.WriteString(normalStyle.Render("  " + logLine))
+		b.WriteString(style.Render("  "))
+		b.WriteString(highlightMatches(logLine, m.logsSearch, style, i == m.logsMatch))
 		b.WriteString("\n")
 	}
 
-	renderedLines := len(m.logsLines) - startLog
+	renderedLines := end - startLog
 	for i := renderedLines; i < maxLogLines; i++ {
 		b.WriteString(normalStyle.Render(strings.Repeat(" ", width)))
 		b.WriteString("\n")
@@ -45,3 +449,45 @@ func (m model) renderLogsPanel(width int) string {
 
 	return b.String()
 }
+
+// highlightMatches renders line in style with every case-insensitive match of
+// term picked out, the current match gets a stronger highlight
+func highlightMatches(line, term string, style lipgloss.Style, current bool) string {
+	if term == "" {
+		return style.Render(line)
+	}
+	match := logMatchStyle
+	if current {
+		match = logCurrentMatchStyle
+	}
+
+	lower, lowerTerm := strings.ToLower(line), strings.ToLower(term)
+	if len(lower) != len(line) || len(lowerTerm) != len(term) {
+		// lowercasing changed byte offsets, don't risk slicing mid-rune
+		return style.Render(line)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, lowerTerm)
+		if i < 0 {
+			break
+		}
+		b.WriteString(style.Render(line[:i]))
+		b.WriteString(match.Render(line[i : i+len(term)]))
+		line, lower = line[i+len(term):], lower[i+len(term):]
+	}
+	b.WriteString(style.Render(line))
+	return b.String()
+}
+
+func formatLogWindow(since, until time.Time) string {
+	const layout = "01-02 15:04"
+	switch {
+	case until.IsZero():
+		return "since " + since.Local().Format(layout)
+	case since.IsZero():
+		return "until " + until.Local().Format(layout)
+	}
+	return since.Local().Format(layout) + " → " + until.Local().Format(layout)
+}
@@ -2,9 +2,6 @@ package tui
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -14,8 +11,10 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shubh-io/dockmate/internal/columns"
 	"github.com/shubh-io/dockmate/internal/config"
 	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/filter"
 )
 
 // layout sizing constants
@@ -23,35 +22,28 @@ const (
 	HEADER_HEIGHT        = 8
 	CONTAINER_ROW_HEIGHT = 1
 	LOG_PANEL_HEIGHT     = 15
+	LOG_BUFFER_LINES     = 5000 // scrollback kept per log stream
+	LOG_TAIL_LINES       = 500  // history loaded when opening logs
 	INFO_PANEL_HEIGHT    = 16
 )
 
-func InitialModel() model {
-	// Load configuration from file
-	cfg, _ := config.Load()
-
-	columnPercents := []int{
-		cfg.Layout.ContainerId,
-		cfg.Layout.ContainerNameWidth,
-		cfg.Layout.MemoryWidth,
-		cfg.Layout.CPUWidth,
-		cfg.Layout.NetIOWidth,
-		cfg.Layout.DiskIOWidth,
-		cfg.Layout.ImageWidth,
-		cfg.Layout.StatusWidth,
-		cfg.Layout.PortWidth,
-	}
-	VisibleColumns := []bool{
-		cfg.Layout.ContainerIdVisible,
-		cfg.Layout.ContainerNameVisible,
-		cfg.Layout.MemoryVisible,
-		cfg.Layout.CPUVisible,
-		cfg.Layout.NetIOVisible,
-		cfg.Layout.DiskIOVisible,
-		cfg.Layout.ImageVisible,
-		cfg.Layout.StatusVisible,
-		cfg.Layout.PortVisible,
+// InitialModel connects to host, one of the configured hosts or a url, ""
+// is the local runtime
+func InitialModel(cfg *config.Config, host string) (model, error) {
+	rt, err := openHost(cfg, host)
+	if err != nil {
+		return model{}, err
 	}
+	m := NewModel(cfg, rt)
+	if host != docker.LocalHost {
+		m.host = host
+	}
+	return m, nil
+}
+
+// NewModel builds the TUI model on top of an already chosen runtime.
+// tests pass a docker.FakeRuntime here.
+func NewModel(cfg *config.Config, rt docker.Runtime) model {
 	helpList := list.New(nil, list.NewDefaultDelegate(), 0, 0)
 	helpList.Title = "Help"
 	helpList.SetShowHelp(true)
@@ -60,7 +52,10 @@ func InitialModel() model {
 	helpList.SetShowFilter(false)
 	helpList.SetFilteringEnabled(false)
 
-	return model{
+	applyTheme(cfg.Theme)
+
+	m := model{
+		rt:                   rt,
 		loading:              true,
 		startTime:            time.Now(),
 		page:                 0,
@@ -69,6 +64,7 @@ func InitialModel() model {
 		terminalHeight:       0,
 		projects:             make(map[string]*docker.ComposeProject),
 		expandedProjects:     make(map[string]bool),
+		marked:               make(map[string]bool),
 		flatList:             []treeRow{},
 		logsVisible:          false, // logs hidden by default
 		logPanelHeight:       LOG_PANEL_HEIGHT,
@@ -76,84 +72,71 @@ func InitialModel() model {
 		infoPanelHeight:      INFO_PANEL_HEIGHT,
 		infoContainer:        nil,
 		infoContainerID:      "",
-		sortBy:               sortByStatus,
+		sortBy:               "status",
 		sortAsc:              false, // descending
 		columnMode:           false,
-		selectedColumn:       7,
+		details:              make(map[string]containerDetails),
 		currentMode:          modeNormal,
 		helpList:             helpList,
+		keys:                 newKeyMap(cfg.Keys),
+		hostNames:            docker.HostNames(cfg),
+		connect:              connector(cfg),
 
 		// Load settings from config file
-		settings: Settings{
-			ColumnPercents:  columnPercents,
-			RefreshInterval: cfg.Performance.PollRate,
-			Runtime:         ContainerRuntime(cfg.Runtime.Type),
-			Shell:           cfg.Exec.Shell,
-			VisibleColumns:  VisibleColumns,
-		},
+		settings:         settingsFromConfig(cfg),
+		savedSettings:    settingsFromConfig(cfg),
+		loadConfig:       config.Load,
+		configStamp:      statConfig(),
+		runtimeConfig:    cfg.Runtime,
 		suspendRefresh:   false,
 		settingsSelected: 0,
 	}
+	if cfg.Display.ComposeView {
+		m.composeViewMode = true
+		m.currentMode = modeComposeView
+		m.expandedProjects[standaloneGroup] = true
+	}
+	return m
 }
 
 // called once at startup
 // kicks off container fetch and timer
 func (m model) Init() tea.Cmd {
+	cmds := []tea.Cmd{fetchContainers(m.rt), subscribeEvents(m.rt), tickCmd(time.Duration(m.settings.RefreshInterval) * time.Second)}
+	if m.composeViewMode {
+		cmds = append(cmds, fetchComposeProjects(m.rt))
+	}
+	return tea.Batch(cmds...)
+}
+
+// stats sort keys, containers without stats sort as 0
+func statOf(c docker.Container, pick func(*docker.ContainerStats) uint64) uint64 {
+	if c.Stats == nil {
+		return 0
+	}
+	return pick(c.Stats)
+}
 
-	return tea.Batch(fetchContainers(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
+func cpuOf(c docker.Container) float64 {
+	if c.Stats == nil {
+		return 0
+	}
+	return c.Stats.CPU
 }
 
 // sort containers by current column and direction
 func (m *model) sortContainers() {
+	kind := kindOf(m.sortBy)
 	lessContainer := func(a, b docker.Container) bool {
-
-		switch m.sortBy {
-		case sortByID:
-			return a.ID < b.ID
-
-		case sortByName:
-			ai, aj := "", ""
-
-			if len(a.Names) > 0 {
-				ai = a.Names[0]
-			}
-
-			if len(b.Names) > 0 {
-				aj = b.Names[0]
-			}
-			return strings.ToLower(ai) < strings.ToLower(aj)
-
-		case sortByMemory:
-			return parsePercent(a.Memory) < parsePercent(b.Memory)
-
-		case sortByCPU:
-			return parsePercent(a.CPU) < parsePercent(b.CPU)
-		case sortByImage:
-			return strings.ToLower(a.Image) < strings.ToLower(b.Image)
-
-		case sortByStatus:
-			return strings.ToLower(a.Status) < strings.ToLower(b.Status)
-
-		case sortByPorts:
-			return strings.ToLower(a.Ports) < strings.ToLower(b.Ports)
-
-		case sortByNetIO:
-
-			return parseNetIO(a.NetIO) < parseNetIO(b.NetIO)
-
-		case sortByBlockIO:
-			return parseNetIO(a.BlockIO) < parseNetIO(b.BlockIO)
-		default:
-			return a.ID < b.ID
-		}
+		return kind.less(m, &a, &b)
 	}
 
 	// sort main container slice
-	sort.Slice(m.containers, func(i, j int) bool {
+	sort.Slice(m.allContainers, func(i, j int) bool {
 		if m.sortAsc {
-			return lessContainer(m.containers[i], m.containers[j])
+			return lessContainer(m.allContainers[i], m.allContainers[j])
 		}
-		return !lessContainer(m.containers[i], m.containers[j])
+		return !lessContainer(m.allContainers[i], m.allContainers[j])
 	})
 
 	// also sort containers inside each compose project so compose view  matches column sorting
@@ -166,10 +149,10 @@ func (m *model) sortContainers() {
 				return !lessContainer(p.Containers[i], p.Containers[j])
 			})
 		}
-		if m.composeViewMode {
-			m.buildFlatList()
-		}
 	}
+
+	// the filtered list keeps the sort order, and rebuilds the compose tree
+	m.applyFilter()
 }
 
 // calculateMaxContainers determines how many containers fit on screen given current layout state
@@ -178,6 +161,9 @@ func (m *model) calculateMaxContainers() int {
 	if m.logsVisible {
 		availableHeight -= m.logPanelHeight
 	}
+	if m.composePanelVisible() {
+		availableHeight -= composePanelHeight
+	}
 	if m.infoVisible {
 		// if compose file dir visible:
 		if m.infoContainer != nil && m.infoContainer.ComposeFileDirectory != "" {
@@ -245,6 +231,20 @@ func (m *model) updatePagination() {
 	}
 }
 
+// selectedContainer is the container under the cursor, nil on a project row or an empty list
+func (m *model) selectedContainer() *docker.Container {
+	if m.composeViewMode {
+		if m.cursor < len(m.flatList) && !m.flatList[m.cursor].isProject {
+			return m.flatList[m.cursor].container
+		}
+		return nil
+	}
+	if m.cursor < len(m.containers) {
+		return &m.containers[m.cursor]
+	}
+	return nil
+}
+
 // ============================================================================
 // Update (event handler)
 // ============================================================================
@@ -268,8 +268,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.err = msg.Err
 		} else {
-			m.containers = msg.Containers
+			m.keepStats(msg.Containers)
+			m.allContainers = msg.Containers
 			m.err = nil
+			m.pruneStatsHistory()
+			m.pruneMarks()
+			if note := m.unreachableNote(); note != "" {
+				m.statusMessage = note
+			}
 			// sort with current settings
 			m.sortContainers()
 			// If in compose view, just rebuild!!
@@ -285,6 +291,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.refreshInfoContainer()
 
 		m.updatePagination()
+		return m, tea.Batch(m.syncDetails(), m.fetchMissingStats())
+
+	case detailsMsg:
+		m.handleDetails(msg)
 		return m, nil
 
 	case composeProjectsMsg:
@@ -294,6 +304,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.Err
 			m.statusMessage = fmt.Sprintf("Error fetching compose projects: %v", msg.Err)
 		} else {
+			for _, p := range msg.Projects {
+				m.keepStats(p.Containers)
+			}
 			m.projects = msg.Projects
 			if m.expandedProjects == nil {
 				m.expandedProjects = make(map[string]bool)
@@ -306,8 +319,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// standalone section for lonely containers (not in compose projects)
-			if _, ok := m.expandedProjects["Standalone Containers"]; !ok {
-				m.expandedProjects["Standalone Containers"] = true
+			if _, ok := m.expandedProjects[standaloneGroup]; !ok {
+				m.expandedProjects[standaloneGroup] = true
 			}
 			m.buildFlatList()
 			// keep cursor in bounds
@@ -321,20 +334,38 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updatePagination()
 		return m, nil
 
-	case docker.LogsMsg:
-		// got logs
-		if msg.Err != nil {
-			m.statusMessage = fmt.Sprintf("Logs error: %v", msg.Err)
-			m.logsLines = nil
-			m.logsVisible = false
-		} else {
-			m.logsLines = msg.Lines
-			m.logsContainer = msg.ID
-			m.logsVisible = true
-		}
-		m.updatePagination()
+	case inspectMsg:
+		m.handleInspectMsg(msg)
 		return m, nil
 
+	case logsStartedMsg, logLinesMsg:
+		return m.handleLogsMsgs(msg)
+
+	case imagesMsg, imageRemovedMsg, imagesPrunedMsg, pullStartedMsg, pullProgressMsg:
+		return m.handleImagesMsgs(msg)
+
+	case volumesMsg, volumeRemovedMsg, volumesPrunedMsg:
+		return m.handleVolumesMsgs(msg)
+
+	case networksMsg, networkActionMsg:
+		return m.handleNetworksMsgs(msg)
+
+	case composeStartedMsg, composeOutputMsg:
+		return m.handleComposeMsgs(msg)
+
+	case bulkDoneMsg:
+		return m, m.handleBulkDone(msg)
+
+	case hostSwitchedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Can't connect to %s: %v", msg.host, msg.err)
+			return m, nil
+		}
+		return m, m.useHost(msg.host, msg.rt)
+
+	case configReloadedMsg, runtimeReloadedMsg:
+		return m, m.handleConfigReload(msg)
+
 	case actionDoneMsg:
 		// docker action finished
 		if msg.err != nil {
@@ -343,33 +374,95 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMessage = "Action completed successfully"
 		}
 
-		return m, fetchContainers()
+		return m, fetchContainers(m.rt)
+
+	case eventsSubscribedMsg, eventsClosedMsg, resubscribeMsg, eventMsg, statsMsg:
+		return m.handleEventMsgs(msg)
 
 	case tickMsg:
 
 		if m.suspendRefresh {
 			return m, tickCmd(time.Duration(m.settings.RefreshInterval) * time.Second)
 		}
-		if m.logsVisible && m.logsContainer != "" {
-			return m, tea.Batch(fetchContainers(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second), fetchLogsCmd(m.logsContainer))
+		next := tea.Batch(tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second), m.watchConfig())
+		// events keep the list current, ticks only need stats (plus the odd full resync)
+		if m.events != nil && time.Since(m.lastSync) < resyncInterval {
+			return m, tea.Batch(fetchStats(m.rt, m.runningContainerIDs()), next)
 		}
+		m.lastSync = time.Now()
+		// the lists come without stats, those are a call of their own
+		stats := fetchStats(m.rt, m.runningContainerIDs())
 		if m.composeViewMode {
 			// in compose view , refresh both compose projects and containers as per refresh interval
-			return m, tea.Batch(fetchComposeProjects(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
+			return m, tea.Batch(fetchComposeProjects(m.rt), stats, next)
 		}
-		return m, tea.Batch(fetchContainers(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
+		return m, tea.Batch(fetchContainers(m.rt), stats, next)
 
 	case tea.KeyMsg:
 		// keyboard input
 		m.statusMessage = ""
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+
+		// the confirm dialog is modal
+		if m.confirm != nil {
+			return m, m.handleConfirmKey(msg)
+		}
+		if m.actionDialog != nil {
+			return m, m.handleActionDialogKey(msg)
+		}
+		// so is the bulk summary, any key closes it
+		if m.bulkSummary != nil {
+			m.bulkSummary = nil
+			return m, nil
+		}
+		if m.configBanner != nil {
+			m.configBanner = nil
+			return m, nil
+		}
+		// the filter bar reads keys while it's open
+		if m.filterBar.editing {
+			return m, m.handleFilterKey(msg)
+		}
+
+		// inspect view is full screen and takes every key
+		if m.currentMode == modeInspect {
+			return m, m.handleInspectKey(msg)
+		}
+
+		// same for the images, volumes and networks views
+		switch m.currentMode {
+		case modeImages:
+			return m, m.handleImagesKey(msg)
+		case modeVolumes:
+			return m, m.handleVolumesKey(msg)
+		case modeNetworks:
+			return m, m.handleNetworksKey(msg)
+		}
+		if m.currentMode == modeSettings {
+			return m, m.handleSettingsKey(msg)
+		}
+
+		// project rows of the compose view take the action keys
+		if m.composeViewMode && (m.currentMode == modeComposeView || m.currentMode == modeNormal) {
+			if cmd, handled := m.handleComposeKey(msg); handled {
+				return m, cmd
+			}
+		}
+
+		// logs panel gets first go at keys (scrolling, search prompt...)
+		if m.logsVisible && m.currentMode == modeLogs {
+			if cmd, handled := m.handleLogsKey(msg); handled {
+				return m, cmd
+			}
+		}
+
+		if key.Matches(msg, m.keys.Quit) {
 			if !(m.currentMode == modeHelp) {
-				return m, tea.Quit
+				return m, m.quit()
 
 			}
 		}
 
-		if msg.String() == "esc" {
+		if key.Matches(msg, m.keys.Back) {
 			if m.columnMode {
 				m.columnMode = false
 				m.currentMode = modeNormal
@@ -377,7 +470,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			if m.logsVisible {
-				m.logsVisible = false
+				m.closeLogs()
 				m.currentMode = modeNormal
 				m.updatePagination()
 				m.statusMessage = "Logs closed"
@@ -391,40 +484,37 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statusMessage = "Info panel closed"
 				return m, nil
 			}
+			if len(m.marked) > 0 {
+				m.clearMarks()
+				return m, nil
+			}
+			if !m.filter.Empty() {
+				m.setFilter(filter.Query{})
+				m.statusMessage = "Filter cleared"
+				return m, nil
+			}
 		}
 
-		switch msg.String() {
+		switch {
 
-		case "`":
+		case key.Matches(msg, m.keys.Debug):
 			debugLogger.Printf(
 				"STATE SNAPSHOT: width=%d height=%d page=%d cursor=%d perPage=%d selectedColumn=%d",
 				m.terminalWidth, m.terminalHeight, m.page, m.cursor, m.maxContainersPerPage, m.selectedColumn,
 			)
 			m.statusMessage = "Dumped debug snapshot"
 			return m, nil
-		case " ":
-			// toggle visibility for column when selected
-			if m.settings.VisibleColumns == nil || len(m.settings.VisibleColumns) != 9 {
-				m.settings.VisibleColumns = []bool{true, true, true, true, true, true, true, true, true}
-			}
-			if m.settingsSelected >= 0 && m.settingsSelected <= 8 {
-				m.settings.VisibleColumns[m.settingsSelected] = !m.settings.VisibleColumns[m.settingsSelected]
-			}
-			return m, nil
-
-		case "tab":
+		case key.Matches(msg, m.keys.ColumnMode):
 			// toggle column/row mode
 			if m.currentMode == modeComposeView || m.currentMode == modeNormal || m.currentMode == modeLogs || m.currentMode == modeInfo {
 				m.columnMode = !m.columnMode
 				if m.columnMode {
-					// ensure selectedColumn maps to a valid visual index
-					colmVisCount := countVisibleColumns(m.settings.VisibleColumns)
-					if colmVisCount <= 0 {
-						colmVisCount = 1
-					}
-					if m.selectedColumn >= colmVisCount {
-						m.selectedColumn = colmVisCount - 1
+					// start on the sorted column, or keep the selection on screen
+					visible := m.visibleColumns()
+					if i := slices.IndexFunc(visible, func(c config.ColumnConfig) bool { return c.Name == m.sortBy }); i >= 0 {
+						m.selectedColumn = i
 					}
+					m.selectedColumn = min(m.selectedColumn, len(visible)-1)
 					m.currentMode = modeColumnSelect
 					m.statusMessage = "Column mode: Use ← → to navigate, Enter to sort"
 				} else {
@@ -434,42 +524,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "f2":
-			// toggle settings mode - say yes to settings or no to settings
-			if m.currentMode == modeSettings {
-				m.currentMode = modeNormal
-				m.suspendRefresh = false
-				m.statusMessage = "Settings closed"
-				// normalize percents to sum 100
-				total := 0
-				for _, p := range m.settings.ColumnPercents {
-					total += p
-				}
-				if total == 0 {
-					m.settings.ColumnPercents = []int{8, 14, 6, 6, 10, 12, 18, 13, 13}
-				} else if total != 100 {
-					// normalize proportionally
-					newp := make([]int, len(m.settings.ColumnPercents))
-					acc := 0
-					for i, p := range m.settings.ColumnPercents {
-						np := (p * 100) / total
-						newp[i] = np
-						acc += np
-					}
-					// fix rounding
-					if acc < 100 {
-						newp[0] += 100 - acc
-					}
-					m.settings.ColumnPercents = newp
-				}
-				return m, nil
-			}
+		case key.Matches(msg, m.keys.Settings):
+			// settings reads its own keys while open, this only opens it
 			m.currentMode = modeSettings
 			m.suspendRefresh = true
 			m.statusMessage = "Settings: adjust column % and refresh interval"
 			return m, nil
 
-		case "f1":
+		case key.Matches(msg, m.keys.Images):
+			// images view
+			return m, m.openImages()
+
+		case key.Matches(msg, m.keys.Volumes):
+			// volumes view
+			return m, m.openVolumes()
+
+		case key.Matches(msg, m.keys.Networks):
+			// networks view
+			return m, m.openNetworks()
+
+		case key.Matches(msg, m.keys.Help):
 			// toggle help mode
 			if m.currentMode == modeHelp {
 				m.currentMode = modeNormal
@@ -484,7 +558,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "l", "L":
+		case key.Matches(msg, m.keys.Logs):
 
 			var containerID string
 			if m.infoVisible {
@@ -501,61 +575,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if containerID != "" {
 				if m.logsVisible {
-					m.logsVisible = false
+					m.closeLogs()
 					m.currentMode = modeNormal
 					m.statusMessage = "Logs closed"
 					m.updatePagination()
 				} else {
-					m.logsVisible = true
+					cmd := m.openLogs(containerID)
 					m.currentMode = modeLogs
 					m.statusMessage = "Fetching logs..."
 					m.updatePagination()
-					return m, fetchLogsCmd(containerID)
+					return m, cmd
 				}
 			}
 			return m, nil
 
-		case "enter":
+		case key.Matches(msg, m.keys.Select):
 
 			if m.columnMode {
-				var col sortColumn
-				var canSort bool = true
-
-				type ColumnDef struct {
-					Name     string
-					SortFunc sortColumn
-					ID       int
-				}
-
-				var masterColumns = []ColumnDef{
-					{"ID", sortByID, 0},
-					{"Name", sortByName, 1},
-					{"Memory", sortByMemory, 2},
-					{"CPU", sortByCPU, 3},
-					{"Net I/O", sortByNetIO, 4},
-					{"Disk I/O", sortByBlockIO, 5},
-					{"Image", sortByImage, 6},
-					{"Status", sortByStatus, 7},
-					{"Ports", sortByPorts, 8},
-				}
-
-				var activeCols []ColumnDef
-				for _, colDef := range masterColumns {
-					if m.settings.VisibleColumns[colDef.ID] {
-						activeCols = append(activeCols, colDef)
-					}
-				}
-
 				// m.selectedColumn matches the VISUAL order of TUI
-				if m.selectedColumn >= 0 && m.selectedColumn < len(activeCols) {
-					col = activeCols[m.selectedColumn].SortFunc
-					canSort = true
-				} else {
-					canSort = false
-				}
-
-				if canSort {
-
+				visible := m.visibleColumns()
+				if m.selectedColumn >= 0 && m.selectedColumn < len(visible) {
+					col := visible[m.selectedColumn].Name
 					if m.sortBy == col {
 						m.sortAsc = !m.sortAsc
 					} else {
@@ -568,23 +608,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if !m.sortAsc {
 						dir = "desc"
 					}
-
-					if m.selectedColumn >= 0 && m.selectedColumn < len(activeCols) {
-						m.statusMessage = fmt.Sprintf("Sorted by %s (%s)", activeCols[m.selectedColumn].Name, dir)
-					} else {
-						m.statusMessage = fmt.Sprintf("Sorted (%s)", dir)
-					}
+					title, _ := columns.Lookup(col)
+					m.statusMessage = fmt.Sprintf("Sorted by %s (%s)", title.Title, dir)
 				}
 			}
 			return m, nil
 
-		case "left", "h":
+		case key.Matches(msg, m.keys.ColumnLeft):
 
 			if m.columnMode {
-				colmVisCount := countVisibleColumns(m.settings.VisibleColumns)
-				if colmVisCount <= 0 {
-					return m, nil
-				}
+				colmVisCount := len(m.visibleColumns())
 				if m.selectedColumn >= colmVisCount {
 					m.selectedColumn = colmVisCount - 1
 				}
@@ -594,13 +627,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-		case "right":
+		case key.Matches(msg, m.keys.ColumnRight):
 
 			if m.columnMode {
-				colmVisCount := countVisibleColumns(m.settings.VisibleColumns)
-				if colmVisCount <= 0 {
-					return m, nil
-				}
+				colmVisCount := len(m.visibleColumns())
 				if m.selectedColumn < colmVisCount-1 {
 					m.selectedColumn++
 				}
@@ -608,8 +638,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		if m.currentMode == modeHelp {
-			switch msg.String() {
-			case "esc", "f1", "q":
+			if key.Matches(msg, m.keys.Back, m.keys.Help, m.keys.Quit) {
 				m.currentMode = modeNormal
 				m.suspendRefresh = false
 				m.statusMessage = "Help closed"
@@ -620,164 +649,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		if m.currentMode == modeSettings {
-			switch msg.String() {
-			case "up", "k":
-				if m.settingsSelected > 0 {
-					m.settingsSelected--
-				}
-				return m, nil
-			case "down", "j":
-				if m.settingsSelected < 11 {
-					m.settingsSelected++
-				}
-				return m, nil
-			case "left", "h", "-":
-				if m.settings.ColumnPercents == nil || len(m.settings.ColumnPercents) != 9 {
-					m.settings.ColumnPercents = []int{8, 14, 6, 6, 10, 12, 18, 13, 13}
-				}
-				if m.settingsSelected >= 0 && m.settingsSelected <= 8 {
-					if m.settings.ColumnPercents[m.settingsSelected] > 1 {
-						m.settings.ColumnPercents[m.settingsSelected]--
-					}
-				} else if m.settingsSelected == 9 {
-					if m.settings.RefreshInterval > 1 {
-						m.settings.RefreshInterval--
-					}
-				} else if m.settingsSelected == 10 {
-					// toggle runtime option btwn docker and podman
-					if m.settings.Runtime == RuntimeDocker {
-						m.settings.Runtime = RuntimePodman
-					} else {
-						m.settings.Runtime = RuntimeDocker
-					}
-				} else if m.settingsSelected == 11 {
-					// cycle shell options backward
-					idx := slices.Index(ShellOptions, m.settings.Shell)
-					m.settings.Shell = ShellOptions[(idx-1+len(ShellOptions))%len(ShellOptions)]
-				}
-				return m, nil
-			case "right", "l", "+":
-				if m.settings.ColumnPercents == nil || len(m.settings.ColumnPercents) != 9 {
-					m.settings.ColumnPercents = []int{8, 14, 6, 6, 10, 12, 18, 13, 13}
-				}
-				if m.settingsSelected >= 0 && m.settingsSelected <= 8 {
-					m.settings.ColumnPercents[m.settingsSelected]++
-				} else if m.settingsSelected == 9 {
-					if m.settings.RefreshInterval < 300 {
-						m.settings.RefreshInterval++
-					}
-				} else if m.settingsSelected == 10 {
-					if m.settings.Runtime == RuntimeDocker {
-						m.settings.Runtime = RuntimePodman
-					} else {
-						m.settings.Runtime = RuntimeDocker
-					}
-				} else if m.settingsSelected == 11 {
-					// cycle shell options forward
-					idx := slices.Index(ShellOptions, m.settings.Shell)
-					m.settings.Shell = ShellOptions[(idx+1)%len(ShellOptions)]
-				}
-				return m, nil
-			case "s", "S":
-				// save settings to yaml and restart
-				currentCfg, _ := config.Load()
-				// check if runtime is changed
-				runtimeChanged := string(m.settings.Runtime) != currentCfg.Runtime.Type
-				// Create .yaml config from current settings
-				cfg := &config.Config{
-					Layout: config.LayoutConfig{
-						ContainerId:        m.settings.ColumnPercents[0],
-						ContainerNameWidth: m.settings.ColumnPercents[1],
-						MemoryWidth:        m.settings.ColumnPercents[2],
-						CPUWidth:           m.settings.ColumnPercents[3],
-						NetIOWidth:         m.settings.ColumnPercents[4],
-						DiskIOWidth:        m.settings.ColumnPercents[5],
-						ImageWidth:         m.settings.ColumnPercents[6],
-						StatusWidth:        m.settings.ColumnPercents[7],
-						PortWidth:          m.settings.ColumnPercents[8],
-
-						ContainerIdVisible:   m.settings.VisibleColumns[0],
-						ContainerNameVisible: m.settings.VisibleColumns[1],
-						MemoryVisible:        m.settings.VisibleColumns[2],
-						CPUVisible:           m.settings.VisibleColumns[3],
-						NetIOVisible:         m.settings.VisibleColumns[4],
-						DiskIOVisible:        m.settings.VisibleColumns[5],
-						ImageVisible:         m.settings.VisibleColumns[6],
-						StatusVisible:        m.settings.VisibleColumns[7],
-						PortVisible:          m.settings.VisibleColumns[8],
-					},
-					Performance: config.PerformanceConfig{
-						PollRate: m.settings.RefreshInterval,
-					},
-					Runtime: config.RuntimeConfig{
-						Type: string(m.settings.Runtime),
-					},
-					Exec: config.ExecConfig{
-						Shell: m.settings.Shell,
-					},
-				}
-
-				// Save to config
-				if err := cfg.Save(); err != nil {
-					m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
-				} else {
-					if runtimeChanged {
-						m.statusMessage = "Settings saved! Restarting app..."
-
-						cfg.Runtime.RunPreChecks = true
-						if err := cfg.Save(); err != nil {
-							// log but don't fail prechecks
-							fmt.Fprintf(os.Stderr, "Warning: failed to save config after prechecks: %v\n", err)
-						}
-
-						// Create restart marker file so our app can detect and restart
-						markerPath := filepath.Join(os.TempDir(), ".dockmate_restart")
-						os.WriteFile(markerPath, []byte{}, 0644)
-						// Exit app to restart with new settings
-						return m, tea.Sequence(
-							tea.Tick(800*time.Millisecond, func(t time.Time) tea.Msg { return tea.QuitMsg{} }),
-						)
-					}
-					total := 0
-					for _, p := range m.settings.ColumnPercents {
-						total += p
-					}
-					if total == 0 {
-						m.settings.ColumnPercents = []int{8, 14, 6, 6, 10, 12, 18, 13, 13}
-					} else if total != 100 {
-						newp := make([]int, len(m.settings.ColumnPercents))
-						acc := 0
-						for i, p := range m.settings.ColumnPercents {
-							np := (p * 100) / total
-							newp[i] = np
-							acc += np
-						}
-						if acc < 100 {
-							newp[0] += 100 - acc
-						}
-						m.settings.ColumnPercents = newp
-					}
-					m.currentMode = modeNormal
-					m.suspendRefresh = false
-					m.statusMessage = "Settings saved!"
-					return m, tea.Batch(fetchContainers(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
-				}
-				return m, nil
-			case "esc":
-				m.currentMode = modeNormal
-				m.suspendRefresh = false
-				m.statusMessage = "Settings closed"
-				return m, nil
-			}
-		}
 		if m.currentMode == modeComposeView || m.currentMode == modeNormal || m.currentMode == modeLogs || m.currentMode == modeInfo {
 			// Handle key bindings
 			switch {
-			case key.Matches(msg, Keys.Quit):
-				return m, tea.Quit
+			case key.Matches(msg, m.keys.Quit):
+				return m, m.quit()
 
-			case key.Matches(msg, Keys.Up):
+			case key.Matches(msg, m.keys.Up):
 				if !m.columnMode {
 					if m.composeViewMode {
 						if len(m.flatList) > 0 {
@@ -799,7 +677,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
-			case key.Matches(msg, Keys.Down):
+			case key.Matches(msg, m.keys.Down):
 				if !m.columnMode {
 					if m.composeViewMode {
 						if m.cursor < len(m.flatList)-1 {
@@ -816,36 +694,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
-			case key.Matches(msg, Keys.PageUp):
+			case key.Matches(msg, m.keys.PrevPage):
 				if m.page > 0 {
 					m.page--
 					if m.maxContainersPerPage > 0 {
 						if m.composeViewMode {
-							pageStart := m.page * m.maxContainersPerPage
-							if pageStart < 0 {
-								pageStart = 0
-							}
-							pageEnd := pageStart + m.maxContainersPerPage
-							if pageEnd > len(m.flatList) {
-								pageEnd = len(m.flatList)
-							}
-							found := -1
-							for i := pageStart; i < pageEnd && i < len(m.flatList); i++ {
-								if !m.flatList[i].isProject {
-									found = i
-									break
-								}
-							}
-							if found != -1 {
-								m.cursor = found
-							} else if len(m.flatList) > 0 {
-								for i := pageStart - 1; i >= 0; i-- {
-									if !m.flatList[i].isProject {
-										m.cursor = i
-										break
-									}
-								}
-							}
+							// project rows are selectable, so just land on the top of the page
+							m.cursor = min(m.page*m.maxContainersPerPage, max(0, len(m.flatList)-1))
 						} else {
 							m.cursor = m.page * m.maxContainersPerPage
 							if m.cursor >= len(m.containers) {
@@ -856,7 +711,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.updatePagination()
 
-			case key.Matches(msg, Keys.PageDown):
+			case key.Matches(msg, m.keys.NextPage):
 				// Go to next page (right arrow)
 				maxPage := 0
 				if m.maxContainersPerPage > 0 {
@@ -873,31 +728,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.page++
 					if m.maxContainersPerPage > 0 {
 						if m.composeViewMode {
-							pageStart := m.page * m.maxContainersPerPage
-							if pageStart < 0 {
-								pageStart = 0
-							}
-							pageEnd := pageStart + m.maxContainersPerPage
-							if pageEnd > len(m.flatList) {
-								pageEnd = len(m.flatList)
-							}
-							found := -1
-							for i := pageStart; i < pageEnd && i < len(m.flatList); i++ {
-								if !m.flatList[i].isProject {
-									found = i
-									break
-								}
-							}
-							if found != -1 {
-								m.cursor = found
-							} else if len(m.flatList) > 0 {
-								for i := pageStart; i < len(m.flatList); i++ {
-									if !m.flatList[i].isProject {
-										m.cursor = i
-										break
-									}
-								}
-							}
+							// project rows are selectable, so just land on the top of the page
+							m.cursor = min(m.page*m.maxContainersPerPage, max(0, len(m.flatList)-1))
 						} else {
 							m.cursor = m.page * m.maxContainersPerPage
 							if m.cursor >= len(m.containers) {
@@ -908,69 +740,80 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.updatePagination()
 
-			case key.Matches(msg, Keys.Refresh):
+			case key.Matches(msg, m.keys.Refresh):
 				// Manually refresh container list
 				m.loading = true
-				m.logsVisible = false
+				m.closeLogs()
 				m.infoVisible = false
 				m.infoContainer = nil
 				m.updatePagination()
-				return m, fetchContainers()
+				return m, fetchContainers(m.rt)
 
-			case msg.String() == "c", msg.String() == "C":
+			case key.Matches(msg, m.keys.ComposeView):
 				m.composeViewMode = !m.composeViewMode
 				m.currentMode = modeComposeView
 				if m.composeViewMode {
 					m.statusMessage = "Switched to Compose view "
 					m.expandedProjects = make(map[string]bool)
-					m.expandedProjects["Standalone Containers"] = true
+					m.expandedProjects[standaloneGroup] = true
 					m.cursor = 0
 					m.page = 0
 
 					// to save up performance and API calls
-					return m, tea.Batch(fetchComposeProjects(), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
+					return m, tea.Batch(fetchComposeProjects(m.rt), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
 				}
 				// Exiting compose view  - back to normal
+				m.closeCompose()
 				m.statusMessage = "Switched to Container View"
 				m.cursor = 0
 				m.page = 0
 				m.updatePagination()
 				return m, nil
 
-			case key.Matches(msg, Keys.Start):
-				// Start selected container
-				if m.composeViewMode {
-					// In compose view mode, get container from flatList
-					if m.cursor < len(m.flatList) && !m.flatList[m.cursor].isProject {
-						container := m.flatList[m.cursor].container
-						m.statusMessage = "Starting container..."
-						return m, doAction("start", container.ID)
-					}
-				} else {
-					// Normal mode
-					if len(m.containers) > 0 {
-						m.statusMessage = "Starting container..."
-						return m, doAction("start", m.containers[m.cursor].ID)
-					}
+			case key.Matches(msg, m.keys.Mark):
+				if !m.columnMode {
+					m.toggleMark()
 				}
 
-			case key.Matches(msg, Keys.Stop):
-				// Stop selected container
-				if m.composeViewMode {
-					if m.cursor < len(m.flatList) && !m.flatList[m.cursor].isProject {
-						container := m.flatList[m.cursor].container
-						m.statusMessage = "Stopping container..."
-						return m, doAction("stop", container.ID)
-					}
-				} else {
-					// Normal mode
-					if len(m.containers) > 0 {
-						m.statusMessage = "Stopping container..."
-						return m, doAction("stop", m.containers[m.cursor].ID)
-					}
+			case key.Matches(msg, m.keys.MarkAll):
+				m.markAll()
+
+			case key.Matches(msg, m.keys.Filter):
+				m.openFilterBar()
+
+			case key.Matches(msg, m.keys.Start):
+				// Start selected (or marked) containers
+				return m, m.runAction("start")
+
+			case key.Matches(msg, m.keys.Stop):
+				// Stop selected (or marked) containers
+				return m, m.runAction("stop")
+
+			case key.Matches(msg, m.keys.Pause):
+				// Pause or unpause selected (or marked) containers
+				return m, m.runAction("pause")
+
+			case key.Matches(msg, m.keys.Kill):
+				// Send a signal to selected (or marked) containers, picked in a dialog
+				m.openKillDialog()
+
+			case key.Matches(msg, m.keys.StopWait):
+				// Stop selected (or marked) containers with a custom timeout
+				m.openStopTimeoutDialog()
+
+			case key.Matches(msg, m.keys.Rename):
+				m.openRenameDialog()
+
+			case key.Matches(msg, m.keys.Host):
+				// Switch to another configured host
+				m.openHostDialog()
+
+			case key.Matches(msg, m.keys.Inspect):
+				if selected := m.selectedContainer(); selected != nil {
+					return m, m.openInspect(inspectContainer, selected.ID)
 				}
 
-			case key.Matches(msg, Keys.Info):
+			case key.Matches(msg, m.keys.Info):
 				// Toggle info panel for selected container
 				var selected *docker.Container
 				if m.logsVisible {
@@ -1002,7 +845,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.updatePagination()
 				}
 
-			case key.Matches(msg, Keys.Exec):
+			case key.Matches(msg, m.keys.Exec):
 				// Open interactive shell in selected container (only if running)
 				var container *docker.Container
 				if m.composeViewMode {
@@ -1024,7 +867,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							"if [ -x '%s' ]; then exec '%s'; else exec /bin/sh; fi",
 						containerID, shell, shell,
 					)
-					c := exec.Command(string(m.settings.Runtime), "exec", "-it", containerID, "sh", "-c", shellCmd)
+					c := m.rt.ExecCommand(containerID, "sh", "-c", shellCmd)
 					return m, tea.ExecProcess(c, func(err error) tea.Msg {
 						if err != nil {
 							return actionDoneMsg{err: fmt.Errorf("shell error: %v", err)}
@@ -1033,37 +876,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					})
 				}
 
-			case key.Matches(msg, Keys.Restart):
-				// Restart selected container
-				if m.composeViewMode {
-
-					if m.cursor < len(m.flatList) && !m.flatList[m.cursor].isProject {
-						container := m.flatList[m.cursor].container
-						m.statusMessage = "Restarting container..."
-						return m, doAction("restart", container.ID)
-					}
-				} else {
-					// Normal mode
-					if len(m.containers) > 0 {
-						m.statusMessage = "Restarting container..."
-						return m, doAction("restart", m.containers[m.cursor].ID)
-					}
-				}
+			case key.Matches(msg, m.keys.Restart):
+				// Restart selected (or marked) containers
+				return m, m.runAction("restart")
 
-			case key.Matches(msg, Keys.Remove):
-				// Remove selected container
-				if m.composeViewMode {
-					if m.cursor < len(m.flatList) && !m.flatList[m.cursor].isProject {
-						container := m.flatList[m.cursor].container
-						m.statusMessage = "Removing container..."
-						return m, doAction("rm", container.ID)
-					}
-				} else {
-					// Normal mode
-					if len(m.containers) > 0 {
-						m.statusMessage = "Removing container..."
-						return m, doAction("rm", m.containers[m.cursor].ID)
-					}
+			case key.Matches(msg, m.keys.Remove):
+				// Remove selected (or marked) containers, behind the confirm dialog
+				if len(m.marked) > 0 {
+					m.confirmRemoveMarked()
+				} else if selected := m.selectedContainer(); selected != nil {
+					m.confirmRemoveContainer(*selected)
 				}
 			}
 		}
@@ -1080,6 +902,23 @@ func (m model) View() string {
 	if m.terminalWidth == 0 {
 		return "Initializing..."
 	}
+	if m.confirm != nil {
+		return overlayCenter(m.renderScreen(), m.renderConfirm(), max(m.terminalWidth, 80))
+	}
+	if m.actionDialog != nil {
+		return overlayCenter(m.renderScreen(), m.renderActionDialog(), max(m.terminalWidth, 80))
+	}
+	if m.bulkSummary != nil {
+		return overlayCenter(m.renderScreen(), m.renderBulkSummary(), max(m.terminalWidth, 80))
+	}
+	if m.configBanner != nil {
+		return overlayCenter(m.renderScreen(), m.renderConfigBanner(), max(m.terminalWidth, 80))
+	}
+	return m.renderScreen()
+}
+
+// renderScreen draws whichever view is active, View puts dialogs on top
+func (m model) renderScreen() string {
 
 	if m.currentMode == modeSettings {
 		return m.renderSettings(m.terminalWidth)
@@ -1089,6 +928,22 @@ func (m model) View() string {
 		return m.renderHelp(m.terminalWidth)
 	}
 
+	if m.currentMode == modeInspect {
+		return m.renderInspect(max(m.terminalWidth, 80))
+	}
+
+	if m.currentMode == modeImages {
+		return m.renderImages(max(m.terminalWidth, 80))
+	}
+
+	if m.currentMode == modeVolumes {
+		return m.renderVolumes(max(m.terminalWidth, 80))
+	}
+
+	if m.currentMode == modeNetworks {
+		return m.renderNetworks(max(m.terminalWidth, 80))
+	}
+
 	var b strings.Builder
 
 	// Ensure minimum width
@@ -1105,91 +960,30 @@ func (m model) View() string {
 
 	running := 0
 	stopped := 0
-	for _, c := range m.containers {
+	for _, c := range m.allContainers {
 		if strings.ToLower(c.State) == "running" {
 			running++
 		} else {
 			stopped++
 		}
 	}
-	total := len(m.containers)
+	total := len(m.allContainers)
 	uptime := time.Since(m.startTime).Round(time.Second)
 
 	statsSection := m.renderStatsSection(running, stopped, total, uptime, width)
 	b.WriteString(statsSection)
 	b.WriteString("\n")
 
+	// the HOST column comes off the top, the rest share what's left
+	hostW := m.hostColumnWidth()
 	usableWidth := width - 2
-
-	mins := []int{13, 17, 8, 6, 10, 11, 11, 13, 15}
-
-	percents := m.settings.ColumnPercents
-	if len(percents) != 9 {
-		percents = []int{8, 14, 6, 6, 10, 12, 11, 13, 15}
-	}
-
-	// visible columns
-	visible := m.settings.VisibleColumns
-	if visible == nil || len(visible) != 9 {
-		visible = []bool{true, true, true, true, true, true, true, true, true}
-		m.settings.VisibleColumns = visible
+	if hostW > 0 {
+		usableWidth -= hostW + 1
 	}
 
-	// allocate widths by percent, respecting minimums
-	widths := make([]int, len(mins))
-	allocated := 0
-	sumPerc := 0
-	for i := range percents {
-		if visible[i] {
-			sumPerc += percents[i]
-		}
-	}
-	if sumPerc == 0 {
+	shown := m.layoutColumns(usableWidth)
 
-		sumPerc = 100
-		for i := range percents {
-			visible[i] = true
-		}
-		m.settings.VisibleColumns = visible
-	}
-
-	for i := range mins {
-		if !visible[i] {
-			widths[i] = 0
-			continue
-		}
-		desired := (usableWidth * percents[i]) / sumPerc
-		widths[i] = max(mins[i], desired)
-		allocated += widths[i]
-	}
-
-	// if we have remaining space, distribute one char at a time across columns
-	if allocated < usableWidth {
-		remaining := usableWidth - allocated
-		for remaining > 0 {
-			for i := range widths {
-				if remaining == 0 {
-					break
-				}
-				if !visible[i] {
-					continue
-				}
-				widths[i]++
-				remaining--
-			}
-		}
-	}
-	idW := widths[0]
-	nameW := widths[1]
-	memoryW := widths[2]
-	cpuW := widths[3]
-	netIOW := widths[4]
-	blockIOW := widths[5]
-	imageW := widths[6]
-	statusW := widths[7]
-	portsW := widths[8]
-
-	sortIndicator := func(col sortColumn) string {
+	sortIndicator := func(col string) string {
 		if m.sortBy == col {
 			if m.sortAsc {
 				return " ▲"
@@ -1199,9 +993,6 @@ func (m model) View() string {
 		return ""
 	}
 
-	// highlight selected column in column mode
-	highlightStyle := lipgloss.NewStyle().Background(lipgloss.Color("#58cdffff")).Foreground(lipgloss.Color("#000000")).Bold(true)
-
 	// buildColumn builds a complete cell with spacing, padding, and title
 	buildColumn := func(columnIndex int, title string, width int, indicator string) string {
 		text := title + indicator
@@ -1213,47 +1004,26 @@ func (m model) View() string {
 		// Add leading space and apply style
 		cell := " " + text
 		if m.columnMode && m.selectedColumn == columnIndex {
-			return highlightStyle.Render(cell)
+			return columnHighlightStyle.Render(cell)
 		}
 		return headerStyle.Render(cell)
 	}
 
 	// build header for visible columns only
-	sepStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(meterGreen)
-	sep := sepStyle.Render("│")
+	sep := headerStyle.UnsetBold().Render("│")
 
 	var hdrBuilder strings.Builder
-	colTitles := []struct {
-		idx   int
-		title string
-		ind   sortColumn
-		pad   int
-	}{
-		{0, "CONTAINER ID", sortByID, idW - 1},
-		{1, "NAME", sortByName, nameW - 1},
-		{2, "MEMORY", sortByMemory, memoryW - 2},
-		{3, "CPU", sortByCPU, cpuW - 2},
-		{4, "NET I/O", sortByNetIO, netIOW - 1},
-		{5, "DISK I/O", sortByBlockIO, blockIOW - 1},
-		{6, "IMAGE", sortByImage, imageW - 1},
-		{7, "STATUS", sortByStatus, statusW},
-		{8, "PORTS", sortByPorts, portsW - 2},
-	}
-
 	first := true
-	columnIndex := 0
-	for _, col := range colTitles {
-		if !visible[col.idx] {
-			continue
-		}
+	if hostW > 0 {
+		hdrBuilder.WriteString(headerStyle.Render(" " + padRight("HOST", hostW-1)))
+		first = false
+	}
+	for i, col := range shown {
 		if !first {
 			hdrBuilder.WriteString(sep)
 		}
 		first = false
-		hdrBuilder.WriteString(buildColumn(columnIndex, col.title, col.pad, sortIndicator(col.ind)))
-		columnIndex++
+		hdrBuilder.WriteString(buildColumn(i, col.Title, col.width-col.kind.trim, sortIndicator(col.Name)))
 	}
 
 	hdr := hdrBuilder.String()
@@ -1291,7 +1061,7 @@ func (m model) View() string {
 		}
 
 		for i := pageStart; i < pageEnd; i++ {
-			row := m.renderTreeRow(m.flatList[i], i == m.cursor, idW, nameW, memoryW, cpuW, netIOW, blockIOW, imageW, statusW, portsW, width)
+			row := m.renderTreeRow(m.flatList[i], i == m.cursor, shown, width)
 			b.WriteString(row)
 			b.WriteString("\n")
 			rowsRendered++
@@ -1312,7 +1082,7 @@ func (m model) View() string {
 
 		for i := pageStart; i < pageEnd; i++ {
 			c := m.containers[i]
-			row := m.renderContainerRow(c, i == m.cursor, idW, nameW, memoryW, cpuW, netIOW, blockIOW, imageW, statusW, portsW, width)
+			row := m.renderContainerRow(c, i == m.cursor, shown, width)
 			b.WriteString(row)
 			b.WriteString("\n")
 			rowsRendered++
@@ -1353,14 +1123,15 @@ func (m model) View() string {
 	if m.infoVisible && !m.logsVisible {
 		b.WriteString(m.renderInfoPanel(width))
 	}
+	if m.composePanelVisible() {
+		b.WriteString(m.renderComposePanel(width))
+	}
 
 	pageLine := m.message
 	if pageLine == "" {
 		pageLine = fmt.Sprintf("Page %d/%d", m.page+1, 1)
 	}
-	if len(pageLine) < width {
-		pageLine += strings.Repeat(" ", width-len(pageLine))
-	}
+	pageLine = padRight(truncateToWidth(pageLine+m.filterLine(), width), width)
 	b.WriteString(messageStyle.Render(pageLine))
 	b.WriteString("\n")
 
@@ -1436,6 +1207,9 @@ func (m model) renderStatsSection(running, stopped, total int, uptime time.Durat
 		infoValueStyle.Render(fmt.Sprintf("%ds", m.settings.RefreshInterval)),
 		infoLabelStyle.Render("Runtime:"),
 		infoValueStyle.Render(string(m.settings.Runtime)))
+	if m.host != "" {
+		infoLine += fmt.Sprintf("  %s %s", infoLabelStyle.Render("Host:"), infoValueStyle.Render(m.host))
+	}
 
 	leftLen := visibleLen(runningLine)
 	rightLen := visibleLen(infoLine)
@@ -1462,6 +1236,8 @@ func (m model) renderStatsSection(running, stopped, total int, uptime time.Durat
 		meterBracketStyle.Render("]"),
 		infoValueStyle.Render(fmt.Sprintf("%d/%d", stopped, total)))
 
+	stoppedLine += m.healthCounts()
+
 	b.WriteString(stoppedLine)
 
 	// loading spinner if fetching
@@ -1476,7 +1252,32 @@ func (m model) renderStatsSection(running, stopped, total int, uptime time.Durat
 	return b.String()
 }
 
-func renderBar(pct float64, width int, fgColor, bgColor lipgloss.Color) string {
+// healthCounts is the healthcheck tally for the header, nothing when no
+// running container has a healthcheck
+func (m model) healthCounts() string {
+	counts := map[docker.Health]int{}
+	for _, c := range m.allContainers {
+		if c.State == "running" && c.Health != docker.HealthNone {
+			counts[c.Health]++
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	unhealthy := infoValueStyle.Render(fmt.Sprint(counts[docker.HealthUnhealthy]))
+	if counts[docker.HealthUnhealthy] > 0 {
+		unhealthy = unhealthyStyle.Render(fmt.Sprint(counts[docker.HealthUnhealthy]))
+	}
+	return fmt.Sprintf("   %s %s  %s %s  %s %s",
+		infoLabelStyle.Render("Healthy:"),
+		infoValueStyle.Render(fmt.Sprint(counts[docker.HealthHealthy])),
+		infoLabelStyle.Render("Unhealthy:"),
+		unhealthy,
+		infoLabelStyle.Render("Starting:"),
+		infoValueStyle.Render(fmt.Sprint(counts[docker.HealthStarting])))
+}
+
+func renderBar(pct float64, width int, fgColor, bgColor lipgloss.TerminalColor) string {
 	// clamp percentage
 	if pct < 0 {
 		pct = 0
@@ -1558,112 +1359,15 @@ func truncateToWidth(s string, width int) string {
 	return result + "…"
 }
 
-func countVisibleColumns(visible []bool) int {
-	if visible == nil || len(visible) != 9 {
-		return 9
-	}
-	count := 0
-	for _, vis := range visible {
-		if vis {
-			count++
-		}
-	}
-	return count
-}
-
 // render one container row
 // applies styles based on selection and state
-func (m model) renderContainerRow(c docker.Container, selected bool, idW, nameW, memoryW, cpuW, netIOW, blockIOW, imageW, statusW, portsW, totalWidth int) string {
-	// get name from names array
-	name := ""
-	if len(c.Names) > 0 {
-		name = c.Names[0]
-	}
-
-	// truncate fields to fit
-	id := c.ID
-	if visibleLen(id) > idW-2 {
-		id = truncateToWidth(id, idW-2)
-	}
-
-	if visibleLen(name) > nameW-2 {
-		name = truncateToWidth(name, nameW-2)
-	}
-	img := c.Image
-	if visibleLen(img) > imageW-2 {
-		img = truncateToWidth(img, imageW-2)
-	}
-	status := c.Status
-	if visibleLen(status) > statusW-2 {
-		status = truncateToWidth(status, statusW-2)
+func (m model) renderContainerRow(c docker.Container, selected bool, shown []shownColumn, totalWidth int) string {
+	parts := make([]string, 0, len(shown)+1)
+	if host := m.hostCell(&c); host != "" {
+		parts = append(parts, host)
 	}
-	// STATE column hidden for testing; keep state value for styling only
-	// state := c.State
-
-	// net IO
-	netio := c.NetIO
-	if netio == "" {
-		netio = "─"
-	}
-	if visibleLen(netio) > netIOW-2 {
-		// truncate to fit with ellipsis
-		netio = truncateToWidth(netio, netIOW-2)
-	}
-
-	// block IO
-	blockio := c.BlockIO
-	if blockio == "" {
-		blockio = "─"
-	}
-	if visibleLen(blockio) > blockIOW-2 {
-		blockio = truncateToWidth(blockio, blockIOW-2)
-	}
-
-	// placeholder for empty stuff
-	mem := c.Memory
-	if mem == "" {
-		mem = "─"
-	}
-	if visibleLen(mem) > memoryW-2 {
-		mem = truncateToWidth(mem, memoryW-2)
-	}
-
-	cpu := c.CPU
-	if visibleLen(cpu) > cpuW-2 {
-		// truncate to fit with ellipsis
-		cpu = truncateToWidth(cpu, cpuW-2)
-	}
-	if cpu == "" {
-		cpu = "─"
-	}
-	ports := c.Ports
-	if ports == "" {
-		ports = "─"
-	}
-	if visibleLen(ports) > portsW-7 {
-		ports = truncateToWidth(ports, portsW-6)
-	}
-
-	// build colm for visible columns only
-	visible := m.settings.VisibleColumns
-	if visible == nil || len(visible) != 9 {
-		visible = []bool{true, true, true, true, true, true, true, true, true}
-	}
-
-	padWidths := []int{idW - 1, nameW - 1, memoryW - 2, cpuW - 2, netIOW - 1, blockIOW - 1, imageW - 1, statusW, portsW - 2}
-	values := []string{id, name, mem, cpu, netio, blockio, img, status, ports}
-
-	parts := make([]string, 0, 9)
-	for i := 0; i < 9; i++ {
-		if !visible[i] || padWidths[i] <= 0 {
-			continue
-		}
-		// ensure value fits
-		if visibleLen(values[i]) > padWidths[i] {
-			values[i] = truncateToWidth(values[i], padWidths[i])
-		}
-		part := fmt.Sprintf(" %-*s", padWidths[i], values[i])
-		parts = append(parts, part)
+	for _, cell := range m.cells(&c, shown, m.markPrefix(c.ID)) {
+		parts = append(parts, " "+cell)
 	}
 
 	row := strings.Join(parts, "│")
@@ -1677,16 +1381,28 @@ func (m model) renderContainerRow(c docker.Container, selected bool, idW, nameW,
 	if selected {
 		return selectedStyle.Render(row)
 	}
+	if m.marked[c.ID] {
+		return markedStyle.Render(row)
+	}
 
+	return containerStyle(&c).Render(row)
+}
+
+// containerStyle colors a row by its state, failing the healthcheck stands
+// out from running
+func containerStyle(c *docker.Container) lipgloss.Style {
+	if c.Health == docker.HealthUnhealthy && c.State == "running" {
+		return unhealthyStyle
+	}
 	switch strings.ToLower(c.State) {
 	case "running":
-		return runningStyle.Render(row)
+		return runningStyle
 	case "paused":
-		return pausedStyle.Render(row)
+		return pausedStyle
 	case "exited", "dead":
-		return stoppedStyle.Render(row)
+		return stoppedStyle
 	default:
-		return normalStyle.Render(row)
+		return normalStyle
 	}
 }
 
@@ -1710,65 +1426,158 @@ func (m model) renderFooter(width int) string {
 			key  string
 			desc string
 		}{
-			{"←→", "Select Col"},
-			{"Enter", "Sort"},
-			{"Esc", "Back"},
+			{label(m.keys.ColumnLeft, m.keys.ColumnRight), "Select Col"},
+			{label(m.keys.Select), "Sort"},
+			{label(m.keys.Back), "Back"},
 		}
 	case modeLogs:
 		keys = []struct {
 			key  string
 			desc string
 		}{
-			{"l", "Close Logs"},
-			{"↑↓", "Scroll"},
-			{"E", "Interactive Shell"},
-			{"Esc", "Back"},
+			{label(m.keys.Logs), "Close Logs"},
+			{label(m.keys.Up, m.keys.Down, m.keys.PageUp, m.keys.PageDown), "Scroll"},
+			{label(m.keys.Follow), "Follow"},
+			{label(m.keys.Search), "Search"},
+			{label(m.keys.Timestamps), "Time"},
+			{label(m.keys.Window), "Window"},
+			{label(m.keys.Back), "Back"},
 		}
 	case modeInfo:
 		keys = []struct {
 			key  string
 			desc string
 		}{
-			{"i", "Close info"},
-			{"↑↓", "Scroll"},
-			{"E", "Interactive Shell"},
-			{"Esc", "Back"},
+			{label(m.keys.Info), "Close info"},
+			{label(m.keys.Up, m.keys.Down), "Scroll"},
+			{helpLabel(m.keys.Exec), "Interactive Shell"},
+			{label(m.keys.Back), "Back"},
+		}
+	case modeInspect:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{label(m.keys.Up, m.keys.Down), "Nav"},
+			{label(m.keys.Select, m.keys.Collapse, m.keys.Expand), "Fold"},
+			{label(m.keys.Reveal), "Reveal secret"},
+			{label(m.keys.Reload), "Reload"},
+			{label(m.keys.Back), "Back"},
+		}
+	case modeImages:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{label(m.keys.Up, m.keys.Down), "Nav"},
+			{label(m.keys.Select), "Inspect"},
+			{label(m.keys.PullImage, m.keys.PullNew), "Pull"},
+			{label(m.keys.Delete), "Remove"},
+			{label(m.keys.Prune), "Prune"},
+			{label(m.keys.Reload), "Refresh"},
+			{label(m.keys.Back, m.keys.Images), "Back"},
+		}
+	case modeVolumes:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{label(m.keys.Up, m.keys.Down), "Nav"},
+			{label(m.keys.Select), "Containers"},
+			{label(m.keys.Delete), "Remove"},
+			{label(m.keys.Prune), "Prune unused"},
+			{label(m.keys.Reload), "Refresh"},
+			{label(m.keys.Back, m.keys.Volumes), "Back"},
+		}
+	case modeNetworks:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{label(m.keys.Up, m.keys.Down), "Nav"},
+			{label(m.keys.Select), "Containers"},
+			{label(m.keys.Connect), "Connect"},
+			{label(m.keys.Disconnect), "Disconnect"},
+			{label(m.keys.Reload), "Refresh"},
+			{label(m.keys.Back, m.keys.Networks), "Back"},
 		}
 	case modeHelp:
 		keys = []struct {
 			key  string
 			desc string
 		}{
-			{"f1", "Close Help"},
-			{"Esc", "Back"},
+			{label(m.keys.Help), "Close Help"},
+			{label(m.keys.Back), "Back"},
 		}
 	default: // modeNormal
 		keys = []struct {
 			key  string
 			desc string
 		}{
-			{"↑↓", "Nav"},
-			{"←→", "Nav pages"},
-			{"Tab", "Col Mode"},
-			{"c", "Compose View"},
-			{"f1", "Keyboard shortcuts"},
-			{"f2", "Settings"},
-			{"q", "Quit"},
+			{label(m.keys.Up, m.keys.Down), "Nav"},
+			{label(m.keys.PrevPage, m.keys.NextPage), "Nav pages"},
+			{label(m.keys.ColumnMode), "Col Mode"},
+			{label(m.keys.ComposeView), "Compose View"},
+			{label(m.keys.Help), "Keyboard shortcuts"},
+			{label(m.keys.Settings), "Settings"},
+			{label(m.keys.Images), "Images"},
+			{label(m.keys.Quit), "Quit"},
 		}
 		if m.composeViewMode {
 			keys = []struct {
 				key  string
 				desc string
 			}{
-				{"↑↓", "Nav"},
-				{"←→", "Nav pages"},
-				{"Tab", "Col Mode"},
-
-				{"c", "Normal View"},
-				{"f1", "Keyboard shortcuts"},
-				{"f2", "Settings"},
-				{"q", "Quit"},
+				{label(m.keys.Up, m.keys.Down), "Nav"},
+				{label(m.keys.PrevPage, m.keys.NextPage), "Nav pages"},
+				{label(m.keys.ColumnMode), "Col Mode"},
+
+				{label(m.keys.ComposeView), "Normal View"},
+				{label(m.keys.Help), "Keyboard shortcuts"},
+				{label(m.keys.Settings), "Settings"},
+				{label(m.keys.Images), "Images"},
+				{label(m.keys.Quit), "Quit"},
 			}
+			if m.cursor < len(m.flatList) && m.flatList[m.cursor].isProject && m.flatList[m.cursor].projectName != standaloneGroup {
+				keys = []struct {
+					key  string
+					desc string
+				}{
+					{label(m.keys.Up, m.keys.Down), "Nav"},
+					{label(m.keys.Select), "Fold"},
+					{label(m.keys.Start, m.keys.Stop, m.keys.Restart), "Up/Stop/Restart"},
+					{label(m.keys.Remove), "Down"},
+					{label(m.keys.Pull), "Pull"},
+					{label(m.keys.Recreate), "Recreate"},
+					{label(m.keys.ComposeView), "Normal View"},
+					{label(m.keys.Quit), "Quit"},
+				}
+			}
+		}
+		if len(m.marked) > 0 && m.selectedContainer() != nil {
+			keys = []struct {
+				key  string
+				desc string
+			}{
+				{label(m.keys.Up, m.keys.Down), "Nav"},
+				{label(m.keys.Mark), "Mark"},
+				{label(m.keys.MarkAll), "All"},
+				{label(m.keys.Start, m.keys.Stop, m.keys.Restart, m.keys.Pause), "Start/Stop/Restart/Pause"},
+				{label(m.keys.Kill), "Kill"},
+				{label(m.keys.Remove), "Remove"},
+				{label(m.keys.Back), "Clear"},
+				{label(m.keys.Quit), "Quit"},
+			}
+		}
+	}
+	if m.filterBar.editing {
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{"Enter", "Apply"},
+			{"↑↓", "Recent filters"},
+			{"Esc", "Cancel"},
 		}
 	}
 
@@ -0,0 +1,1231 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/shubh-io/dockmate/internal/columns"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// commands that don't return within this are timers (tea.Tick) and get dropped
+const cmdTimeout = 200 * time.Millisecond
+
+func TestMain(m *testing.M) {
+	// plain text output so golden files don't depend on the terminal
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// harness drives the model headlessly: it feeds messages to Update and runs
+// the returned commands synchronously against a fake runtime
+type harness struct {
+	m  model
+	rt *docker.FakeRuntime
+}
+
+func newHarness(t *testing.T, containers ...docker.Container) *harness {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	rt := docker.NewFakeRuntime(containers...)
+	h := &harness{m: NewModel(config.DefaultConfig(), rt), rt: rt}
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 24})
+	h.run(h.m.Init())
+	return h
+}
+
+func (h *harness) send(msg tea.Msg) {
+	next, cmd := h.m.Update(msg)
+	h.m = next.(model)
+	h.run(cmd)
+}
+
+func (h *harness) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	select {
+	case msg := <-done:
+		switch msg := msg.(type) {
+		case nil, tea.QuitMsg:
+		case tea.BatchMsg:
+			for _, c := range msg {
+				h.run(c)
+			}
+		default:
+			h.send(msg)
+		}
+	case <-time.After(cmdTimeout):
+	}
+}
+
+// press sends a single key, named keys ("tab", "f2", "esc") or plain runes
+func (h *harness) press(k string) {
+	named := map[string]tea.KeyType{
+		"tab": tea.KeyTab, "enter": tea.KeyEnter, "esc": tea.KeyEsc, "space": tea.KeySpace,
+		"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+		"home": tea.KeyHome, "end": tea.KeyEnd, "backspace": tea.KeyBackspace,
+		"f1": tea.KeyF1, "f2": tea.KeyF2, "f3": tea.KeyF3, "f4": tea.KeyF4, "f6": tea.KeyF6, "f5": tea.KeyF5,
+	}
+	if t, ok := named[k]; ok {
+		h.send(tea.KeyMsg{Type: t})
+		return
+	}
+	h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+}
+
+// view renders the current screen with the session clock pinned at 00:00
+func (h *harness) view() string {
+	h.m.startTime = time.Now()
+	return h.m.View()
+}
+
+// golden compares got against testdata/<name>.golden, go test -update rewrites it
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll("testdata", 0755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test ./internal/tui -update to create golden files")
+	assert.Equal(t, string(want), got)
+}
+
+func sampleContainers() []docker.Container {
+	return []docker.Container{
+		{
+			ID: "a1b2c3d4e5f6", Names: []string{"shop-web-1"}, Image: "nginx:1.27",
+			Status: "Up 5 minutes", State: "running", Ports: "0.0.0.0:8080->80/tcp",
+			ComposeProject: "shop", ComposeService: "web",
+			Labels: map[string]string{"com.docker.compose.project": "shop"},
+		},
+		{
+			ID: "0f9e8d7c6b5a", Names: []string{"shop-db-1"}, Image: "postgres:16",
+			Status: "Up 3 hours", State: "running", Ports: "5432/tcp",
+			ComposeProject: "shop", ComposeService: "db",
+			Labels: map[string]string{"com.docker.compose.project": "shop"},
+		},
+		{
+			ID: "112233445566", Names: []string{"old-job"}, Image: "busybox",
+			Status: "Exited (0) 2 hours ago", State: "exited",
+		},
+	}
+}
+
+func newSampleHarness(t *testing.T) *harness {
+	return newHarness(t, sampleContainers()...)
+}
+
+func TestViewContainerList(t *testing.T) {
+	h := newHarness(t)
+	h.rt.SetContainers(sampleContainers()...)
+	h.rt.SetStats(docker.ContainerStats{ID: "a1b2c3d4e5f6", CPU: 0.015, MemUsed: 32, MemLimit: 1000, NetRx: 1200, NetTx: 648})
+	h.press("f5")
+
+	require.Len(t, h.m.containers, 3)
+	golden(t, "container_list", h.view())
+}
+
+func TestViewComposeTree(t *testing.T) {
+	h := newSampleHarness(t)
+	h.press("c")
+
+	require.Contains(t, h.m.projects, "shop")
+	golden(t, "compose_tree", h.view())
+}
+
+func TestComposeProjectActions(t *testing.T) {
+	containers := sampleContainers()
+	for i := range containers[:2] {
+		containers[i].Labels["com.docker.compose.project.config_files"] = "/srv/shop/compose.yaml"
+	}
+	h := newHarness(t, containers...)
+	h.press("c")
+
+	// the cursor starts on the shop header, enter folds it
+	require.True(t, h.m.flatList[h.m.cursor].isProject)
+	h.press("enter")
+	assert.Len(t, h.m.flatList, 3)
+	h.press("enter")
+	assert.Len(t, h.m.flatList, 5)
+
+	h.press("x")
+	assert.Equal(t, []string{"compose stop shop"}, h.rt.Calls())
+	require.NotNil(t, h.m.composeRun)
+	assert.Equal(t, 2, h.m.composeRun.out.Len())
+	assert.Equal(t, "compose stop finished for shop", h.m.statusMessage)
+	assert.Equal(t, 0, h.m.flatList[0].running)
+	golden(t, "compose_actions", h.view())
+
+	// long output is cut to the panel by characters, not bytes
+	h.m.composeRun.out.Push(docker.ComposeOutput{Text: strings.Repeat("é", 200)})
+	view := h.view()
+	assert.True(t, utf8.ValidString(view))
+	assert.Contains(t, view, "  "+strings.Repeat("é", 115)+"…  \n")
+
+	h.press("u")
+	assert.Equal(t, "compose up -d --force-recreate shop", h.rt.Calls()[1])
+	assert.Equal(t, 2, h.m.flatList[0].running)
+
+	// down asks first
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	h.press("n")
+	assert.Nil(t, h.m.confirm)
+	assert.Len(t, h.rt.Calls(), 2)
+	h.press("d")
+	h.press("y")
+	assert.Equal(t, "compose down shop", h.rt.Calls()[2])
+	assert.NotContains(t, h.m.projects, "shop")
+
+	h.press("esc")
+	assert.Nil(t, h.m.composeRun)
+}
+
+func TestComposeDownAllHosts(t *testing.T) {
+	containers := sampleContainers()
+	for i := range containers[:2] {
+		containers[i].Labels["com.docker.compose.project.config_files"] = "/srv/shop/compose.yaml"
+	}
+	h := newHarness(t, containers...)
+	remote := docker.NewFakeRuntime()
+	h.m.hostNames = []string{docker.LocalHost, "vm1", docker.AllHosts}
+	h.m.connect = func(string) (docker.Runtime, error) {
+		return docker.NewMultiRuntime(
+			docker.NamedRuntime{Name: docker.LocalHost, Runtime: h.rt},
+			docker.NamedRuntime{Name: "vm1", Runtime: remote},
+		), nil
+	}
+	h.press("H")
+	h.press("down")
+	h.press("down")
+	h.press("enter")
+	require.Equal(t, docker.AllHosts, h.m.host)
+
+	// projects are keyed host/name here, down still finds its project
+	h.press("c")
+	require.True(t, h.m.flatList[h.m.cursor].isProject)
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	h.press("y")
+	assert.Equal(t, []string{"compose down shop"}, h.rt.Calls())
+	assert.Empty(t, remote.Calls())
+	assert.NotEqual(t, "Project shop is gone", h.m.statusMessage)
+}
+
+func TestComposeActionNeedsFile(t *testing.T) {
+	h := newSampleHarness(t)
+	h.press("c")
+
+	// no config_files label: stop works off the project name, up can't
+	h.press("s")
+	assert.Contains(t, h.m.statusMessage, "no compose file recorded for project shop")
+	assert.True(t, h.m.composeRun.done)
+
+	// standalone containers aren't a project
+	h.press("down")
+	h.press("down")
+	h.press("down")
+	require.Equal(t, standaloneGroup, h.m.flatList[h.m.cursor].projectName)
+	h.press("esc")
+	h.press("x")
+	assert.Contains(t, h.m.statusMessage, "aren't a compose project")
+}
+
+func TestStopSelectedContainer(t *testing.T) {
+	h := newSampleHarness(t)
+
+	// sorted by status desc, so the first row is the most recently started
+	require.Equal(t, "a1b2c3d4e5f6", h.m.containers[0].ID)
+	h.press("x")
+
+	assert.Equal(t, []string{"stop a1b2c3d4e5f6"}, h.rt.Calls())
+	assert.Equal(t, "Action completed successfully", h.m.statusMessage)
+	for _, c := range h.m.containers {
+		if c.ID == "a1b2c3d4e5f6" {
+			assert.Equal(t, "exited", c.State)
+		}
+	}
+}
+
+func TestRemoveConfirmDialog(t *testing.T) {
+	h := newSampleHarness(t)
+
+	// nothing happens on the first keypress
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	assert.Empty(t, h.rt.Calls())
+	golden(t, "confirm_remove", h.view())
+
+	h.press("esc")
+	assert.Nil(t, h.m.confirm)
+	assert.Empty(t, h.rt.Calls())
+
+	// running, so stop first is ticked
+	h.press("d")
+	h.press("down")
+	h.press("down")
+	h.press("space")
+	h.press("y")
+	assert.Equal(t, []string{"stop a1b2c3d4e5f6", "rm -v a1b2c3d4e5f6"}, h.rt.Calls())
+	assert.Len(t, h.m.containers, 2)
+}
+
+func TestRemoveProtectedContainer(t *testing.T) {
+	containers := sampleContainers()
+	containers[1].Labels["dockmate.protected"] = "true"
+	h := newHarness(t, containers...)
+
+	h.press("down")
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	assert.Equal(t, "shop-db-1", h.m.confirm.typed)
+
+	// y is just typed text here, enter needs the full name
+	h.press("y")
+	h.press("enter")
+	assert.NotNil(t, h.m.confirm)
+	assert.Contains(t, h.m.statusMessage, `Type "shop-db-1" to confirm`)
+
+	h.send(tea.KeyMsg{Type: tea.KeyBackspace})
+	h.press("shop-db-1")
+	h.press("enter")
+	assert.Nil(t, h.m.confirm)
+	assert.Equal(t, []string{"stop 0f9e8d7c6b5a", "rm 0f9e8d7c6b5a"}, h.rt.Calls())
+}
+
+func TestBulkActions(t *testing.T) {
+	h := newSampleHarness(t)
+
+	// space marks and moves down
+	h.press("space")
+	h.press("v")
+	assert.Equal(t, map[string]bool{"a1b2c3d4e5f6": true, "0f9e8d7c6b5a": true}, h.m.marked)
+	assert.Equal(t, 2, h.m.cursor)
+
+	h.press("x")
+	assert.ElementsMatch(t, []string{"stop a1b2c3d4e5f6", "stop 0f9e8d7c6b5a"}, h.rt.Calls())
+	require.NotNil(t, h.m.bulkSummary)
+	assert.Equal(t, "stop: 2 ok, 0 failed", h.m.statusMessage)
+	assert.Empty(t, h.m.marked)
+
+	// any key closes the summary
+	h.press("x")
+	assert.Nil(t, h.m.bulkSummary)
+	assert.Len(t, h.rt.Calls(), 2)
+}
+
+func TestBulkRemovePartialFailure(t *testing.T) {
+	h := newSampleHarness(t)
+
+	h.press("a")
+	require.Len(t, h.m.marked, 3)
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	assert.Equal(t, "Remove 3 containers?", h.m.confirm.title)
+
+	// without stop first or force the running ones are refused
+	h.press("space")
+	h.press("y")
+	require.NotNil(t, h.m.bulkSummary)
+	assert.Equal(t, "remove: 1 ok, 2 failed", h.m.statusMessage)
+	assert.Equal(t, map[string]bool{"a1b2c3d4e5f6": true, "0f9e8d7c6b5a": true}, h.m.marked)
+	golden(t, "bulk_summary", h.view())
+
+	h.press("esc")
+	h.press("esc")
+	assert.Empty(t, h.m.marked)
+}
+
+func TestActionDialogs(t *testing.T) {
+	h := newSampleHarness(t)
+
+	// kill: pick the signal
+	h.press("K")
+	require.NotNil(t, h.m.actionDialog)
+	h.press("down")
+	h.press("down")
+	h.press("enter")
+	assert.Nil(t, h.m.actionDialog)
+	assert.Equal(t, []string{"kill SIGHUP a1b2c3d4e5f6"}, h.rt.Calls())
+
+	// stop with a timeout
+	h.press("t")
+	h.press("backspace")
+	h.press("backspace")
+	h.press("3")
+	h.press("enter")
+	assert.Equal(t, "stop -t 3 a1b2c3d4e5f6", h.rt.Calls()[1])
+
+	// rename refuses a bad name and stays open. the stopped web container
+	// sorted down, so the cursor is on db now
+	h.press("N")
+	for range "shop-db-1" {
+		h.press("backspace")
+	}
+	h.press("web front")
+	h.press("enter")
+	require.NotNil(t, h.m.actionDialog)
+	require.Error(t, h.m.actionDialog.err)
+	golden(t, "rename_dialog", h.view())
+
+	for range "front" {
+		h.press("backspace")
+	}
+	h.press("backspace")
+	h.press("enter")
+	assert.Nil(t, h.m.actionDialog)
+	assert.Equal(t, "rename web 0f9e8d7c6b5a", h.rt.Calls()[2])
+
+	// esc leaves without running anything
+	h.press("K")
+	h.press("esc")
+	assert.Nil(t, h.m.actionDialog)
+	assert.Len(t, h.rt.Calls(), 3)
+}
+
+func TestFilterBar(t *testing.T) {
+	h := newSampleHarness(t)
+
+	// applies as it's typed, a half typed term that doesn't parse keeps the last good filter
+	h.press("/")
+	h.press("state:running")
+	require.Len(t, h.m.containers, 2)
+	h.press("space")
+	h.press("cpu>x")
+	assert.Error(t, h.m.filterBar.err)
+	assert.Len(t, h.m.containers, 2)
+	for range "cpu>x" {
+		h.send(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	h.press("db")
+	require.Len(t, h.m.containers, 1)
+	assert.Equal(t, "0f9e8d7c6b5a", h.m.containers[0].ID)
+	golden(t, "filter_bar", h.view())
+
+	// enter keeps it and saves it for recall
+	h.press("enter")
+	assert.False(t, h.m.filterBar.editing)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"state:running db"}, cfg.Filter.Recent)
+	// the watcher knows the write was ours
+	h.send(tickMsg(time.Now()))
+	assert.NotEqual(t, "Config reloaded", h.m.statusMessage)
+
+	// the compose tree is filtered too
+	h.press("c")
+	require.Len(t, h.m.flatList, 2)
+	assert.Equal(t, "shop", h.m.flatList[0].projectName)
+	assert.Equal(t, "0f9e8d7c6b5a", h.m.flatList[1].container.ID)
+
+	// esc on the list clears it
+	h.press("esc")
+	assert.True(t, h.m.filter.Empty())
+	assert.Len(t, h.m.containers, 3)
+	assert.Len(t, h.m.flatList, 5)
+
+	// and up brings it back
+	h.press("/")
+	h.press("up")
+	assert.Equal(t, "state:running db", h.m.filterBar.input)
+	assert.Len(t, h.m.containers, 1)
+	h.press("esc")
+	assert.Len(t, h.m.containers, 3)
+}
+
+func TestStatsHistory(t *testing.T) {
+	h := newSampleHarness(t)
+	h.send(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	for _, cpu := range []float64{0.1, 0.4, 0.8, 0.2} {
+		h.send(statsMsg{Stats: map[string]docker.ContainerStats{
+			"a1b2c3d4e5f6": {ID: "a1b2c3d4e5f6", CPU: cpu, MemUsed: 50, MemLimit: 1000, NetRx: 1000, NetTx: 2000},
+		}})
+	}
+	cpu := h.m.series("a1b2c3d4e5f6", func(s statSample) float64 { return s.cpu })
+	assert.InDeltaSlice(t, []float64{10, 40, 80, 20}, cpu, 1e-9)
+	assert.Nil(t, h.m.series("112233445566", func(s statSample) float64 { return s.cpu }))
+
+	// inline in the list, and charted in the info panel
+	h.press("i")
+	golden(t, "stats_history", h.view())
+
+	// a list refresh keeps the stats but doesn't add a sample
+	h.press("f5")
+	assert.Equal(t, 4, h.m.statsHistory["a1b2c3d4e5f6"].Len())
+
+	// history goes with the container
+	h.rt.SetContainers(sampleContainers()[1:]...)
+	h.press("f5")
+	assert.NotContains(t, h.m.statsHistory, "a1b2c3d4e5f6")
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▅█", sparkline([]float64{0, 50, 100}, 10, 100))
+	assert.Equal(t, "▅█", sparkline([]float64{0, 50, 100}, 2, 100))
+	assert.Equal(t, []string{
+		"  █",
+		" ▆█",
+		"▁██",
+	}, chart([]float64{0, 60, 100}, 3, 3, 100))
+	assert.Equal(t, 10.0, chartTop(5.5))
+	assert.Equal(t, 300.0, chartTop(220))
+}
+
+func TestLogsPanel(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetLogLines("a1b2c3d4e5f6",
+		docker.LogLine{Stream: "stdout", Text: "listening on :80"},
+		docker.LogLine{Stream: "stderr", Text: "warn: slow upstream"},
+		docker.LogLine{Stream: "stdout", Text: "GET / 200"},
+	)
+	h.press("l")
+
+	require.True(t, h.m.logsVisible)
+	require.Equal(t, 3, h.m.logsBuf.Len())
+	assert.Equal(t, "warn: slow upstream", h.m.logsBuf.At(1).Text)
+	assert.True(t, h.m.logsFollow)
+	golden(t, "logs_panel", h.view())
+}
+
+func TestLogsScrollAndSearch(t *testing.T) {
+	h := newSampleHarness(t)
+	var lines []string
+	for i := 0; i < 40; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	h.rt.SetLogs("a1b2c3d4e5f6", lines...)
+	h.press("l")
+	require.Equal(t, 40, h.m.logsBuf.Len())
+
+	// scrolling up pauses follow, paging back down resumes it
+	h.send(tea.KeyMsg{Type: tea.KeyPgUp})
+	assert.False(t, h.m.logsFollow)
+	assert.Equal(t, h.m.logsPageSize(), h.m.logsScroll)
+	h.send(tea.KeyMsg{Type: tea.KeyPgDown})
+	assert.True(t, h.m.logsFollow)
+	assert.Equal(t, 0, h.m.logsScroll)
+
+	// incremental search jumps to the newest match, N goes to older ones
+	h.press("/")
+	h.press("1")
+	h.press("2")
+	assert.Equal(t, "12", h.m.logsSearch)
+	assert.Equal(t, 12, h.m.logsMatch)
+	h.press("enter")
+	h.press("N")
+	assert.Equal(t, 12, h.m.logsMatch, "no older match for 12")
+
+	h.press("/")
+	h.press("3")
+	h.press("enter")
+	assert.Equal(t, 39, h.m.logsMatch)
+	h.press("N")
+	assert.Equal(t, 38, h.m.logsMatch)
+	h.press("n")
+	assert.Equal(t, 39, h.m.logsMatch)
+	assert.False(t, h.m.logsFollow)
+}
+func TestEventsPatchContainers(t *testing.T) {
+	h := newSampleHarness(t)
+	require.NotNil(t, h.m.events, "fake runtime should be streaming events")
+
+	find := func(id string) *docker.Container {
+		for i := range h.m.containers {
+			if h.m.containers[i].ID == id {
+				return &h.m.containers[i]
+			}
+		}
+		return nil
+	}
+
+	h.send(eventMsg{Action: "die", ContainerID: "a1b2c3d4e5f6", ExitCode: "137"})
+	assert.Equal(t, "exited", find("a1b2c3d4e5f6").State)
+	assert.Equal(t, "Exited (137) Less than a second ago", find("a1b2c3d4e5f6").Status)
+
+	h.send(eventMsg{Action: "health_status", ContainerID: "0f9e8d7c6b5a", Health: "unhealthy"})
+	assert.Equal(t, "Up 3 hours (unhealthy)", find("0f9e8d7c6b5a").Status)
+	assert.Equal(t, docker.HealthUnhealthy, find("0f9e8d7c6b5a").Health)
+
+	h.send(eventMsg{Action: "destroy", ContainerID: "112233445566"})
+	assert.Nil(t, find("112233445566"))
+	assert.Len(t, h.m.containers, 2)
+
+	// a container we haven't listed yet triggers a refetch
+	h.rt.SetContainers(append(sampleContainers(), docker.Container{
+		ID: "778899aabbcc", Names: []string{"new-one"}, Image: "redis", Status: "Created", State: "created",
+	})...)
+	h.send(eventMsg{Action: "create", ContainerID: "778899aabbcc"})
+	assert.NotNil(t, find("778899aabbcc"))
+}
+
+func TestInspectView(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetInspect("a1b2c3d4e5f6", &docker.ContainerInspect{
+		ID: "a1b2c3d4e5f6", Name: "shop-web-1", Image: "nginx:1.27", State: "running",
+		Cmd:           []string{"nginx", "-g", "daemon off;"},
+		Env:           []string{"PATH=/usr/sbin:/usr/bin", "DB_PASSWORD=hunter2"},
+		RestartPolicy: "unless-stopped",
+		Mounts:        []docker.Mount{{Type: "volume", Name: "shop_data", Destination: "/data", ReadWrite: true}},
+		Networks:      []docker.NetworkEndpoint{{Name: "shop_default", IPAddress: "172.18.0.2/16", Gateway: "172.18.0.1"}},
+		Labels:        map[string]string{"com.docker.compose.project": "shop"},
+	})
+	h.press("o")
+
+	require.Equal(t, modeInspect, h.m.currentMode)
+	require.NotEmpty(t, h.m.inspectTree)
+	view := h.view()
+	assert.NotContains(t, view, "hunter2")
+	golden(t, "inspect_view", view)
+
+	// move onto the password and reveal it
+	for _, r := range h.m.inspectRows() {
+		if r.node.key == "DB_PASSWORD" {
+			break
+		}
+		h.press("down")
+	}
+	h.press("v")
+	assert.Contains(t, h.view(), "DB_PASSWORD: hunter2")
+
+	h.press("esc")
+	assert.Equal(t, modeNormal, h.m.currentMode)
+}
+
+func sampleImages() []docker.Image {
+	now := time.Now()
+	return []docker.Image{
+		{ID: "aa11bb22cc33", Repository: "nginx", Tag: "1.27", Size: 192_000_000, Created: now.Add(-50 * time.Hour)},
+		{ID: "dd44ee55ff66", Repository: "postgres", Tag: "16", Size: 438_000_000, Created: now.Add(-3 * 7 * 24 * time.Hour)},
+		{ID: "0a0b0c0d0e0f", Repository: "<none>", Tag: "<none>", Size: 64_000_000, Created: now.Add(-5 * time.Hour), Dangling: true},
+		{ID: "1a1b1c1d1e1f", Repository: "<none>", Tag: "<none>", Size: 32_000_000, Created: now.Add(-6 * time.Hour), Dangling: true},
+	}
+}
+
+func TestImagesView(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetImages(sampleImages()...)
+	h.press("f3")
+
+	require.Equal(t, modeImages, h.m.currentMode)
+	require.Len(t, h.m.imagesView.images, 4)
+	assert.Equal(t, []string{"shop-web-1"}, h.m.imagesView.images[0].Containers)
+	golden(t, "images_view", h.view())
+
+	h.press("enter")
+	require.Equal(t, modeInspect, h.m.currentMode)
+	assert.Contains(t, h.view(), "nginx:1.27")
+	h.press("esc")
+	assert.Equal(t, modeImages, h.m.currentMode)
+
+	h.press("esc")
+	assert.Equal(t, modeNormal, h.m.currentMode)
+}
+
+func TestImagesRemoveGuardAndPrune(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetImages(sampleImages()...)
+	h.press("f3")
+
+	// nginx is used by shop-web-1, removing it takes the dialog and force
+	h.press("d")
+	require.NotNil(t, h.m.confirm)
+	assert.Contains(t, h.view(), "Remove image nginx:1.27, used by 1 containers?")
+	h.press("y")
+	assert.Empty(t, h.rt.Calls())
+	assert.Equal(t, "nginx:1.27 is used by shop-web-1, check Force to remove it", h.m.statusMessage)
+	h.press("d")
+	h.press("space")
+	h.press("y")
+	assert.Equal(t, []string{"rmi nginx:1.27"}, h.rt.Calls())
+	assert.Equal(t, "Removed nginx:1.27", h.m.statusMessage)
+	require.Len(t, h.m.imagesView.images, 3)
+
+	// prune shows what it would reclaim and waits for y
+	h.press("D")
+	assert.Contains(t, h.view(), "Prune 2 dangling images and reclaim 96MB? (y/n)")
+	h.press("n")
+	assert.Equal(t, "Prune cancelled", h.m.statusMessage)
+	h.press("D")
+	h.press("y")
+	assert.Equal(t, []string{"rmi nginx:1.27", "image prune"}, h.rt.Calls())
+	assert.Equal(t, "Pruned 2 images, reclaimed 96MB", h.m.statusMessage)
+	assert.Len(t, h.m.imagesView.images, 1)
+}
+
+func TestImagesPull(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetImages(sampleImages()...)
+	h.press("f3")
+
+	h.press("P")
+	for _, r := range "redis:7" {
+		h.press(string(r))
+	}
+	h.press("enter")
+
+	assert.Equal(t, []string{"pull redis:7"}, h.rt.Calls())
+	p := h.m.imagesView.pull
+	require.NotNil(t, p)
+	assert.True(t, p.done)
+	assert.Empty(t, p.err)
+	assert.Equal(t, []string{"aaaaaaaaaaaa"}, p.layers)
+	assert.Equal(t, "Pull complete", p.progress["aaaaaaaaaaaa"].Status)
+	assert.Equal(t, "Pulled redis:7", h.m.statusMessage)
+
+	view := h.view()
+	assert.Contains(t, view, "Status: Downloaded newer image for redis:7")
+	var refs []string
+	for _, img := range h.m.imagesView.images {
+		refs = append(refs, img.Ref())
+	}
+	assert.Contains(t, refs, "redis:7")
+}
+
+func sampleVolumes() []docker.Volume {
+	return []docker.Volume{
+		{Name: "shop_data", Driver: "local", Mountpoint: "/var/lib/docker/volumes/shop_data/_data", Size: 1_200_000_000,
+			Containers: []docker.VolumeUser{
+				{ID: "0f9e8d7c6b5a", Name: "shop-db-1", State: "running"},
+				{ID: "112233445566", Name: "old-job", State: "exited"},
+			}},
+		{Name: "build_cache", Driver: "local", Mountpoint: "/var/lib/docker/volumes/build_cache/_data", Size: 300_000_000},
+		{Name: "nfs_share", Driver: "nfs", Size: -1},
+	}
+}
+
+func TestVolumesView(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetVolumes(sampleVolumes()...)
+	h.press("f4")
+
+	require.Equal(t, modeVolumes, h.m.currentMode)
+	require.Len(t, h.m.volumesView.rows, 3)
+
+	// shop_data sorts last, expanding it lists both containers
+	h.press("end")
+	h.press("enter")
+	require.Len(t, h.m.volumesView.rows, 5)
+	golden(t, "volumes_view", h.view())
+
+	h.press("down")
+	h.press("down")
+	assert.Equal(t, "old-job", h.m.volumesView.rows[h.m.volumesView.cursor].user.Name)
+	h.press("left")
+	assert.Len(t, h.m.volumesView.rows, 3)
+	assert.Equal(t, "shop_data", h.m.selectedVolume().Name)
+
+	h.press("esc")
+	assert.Equal(t, modeNormal, h.m.currentMode)
+}
+
+func TestVolumesSafeRemoval(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetVolumes(sampleVolumes()...)
+	h.press("f4")
+
+	// mounted volumes can't be removed from here at all
+	h.press("end")
+	h.press("d")
+	assert.Empty(t, h.rt.Calls())
+	assert.Equal(t, "shop_data is mounted by shop-db-1, old-job, remove those containers first", h.m.statusMessage)
+
+	// unused ones ask first
+	h.press("home")
+	h.press("d")
+	assert.Contains(t, h.view(), "Remove volume build_cache? Its data is gone for good (y/n)")
+	h.press("n")
+	assert.Empty(t, h.rt.Calls())
+	h.press("d")
+	h.press("y")
+	assert.Equal(t, []string{"volume rm build_cache"}, h.rt.Calls())
+	assert.Equal(t, "Removed volume build_cache", h.m.statusMessage)
+	require.Len(t, h.m.volumesView.volumes, 2)
+
+	// prune previews the unused volumes, sizes we don't know count as 0
+	h.press("D")
+	assert.Contains(t, h.view(), "Prune 1 unused volumes and reclaim 0B?")
+	h.press("y")
+	assert.Equal(t, []string{"volume rm build_cache", "volume prune"}, h.rt.Calls())
+	require.Len(t, h.m.volumesView.volumes, 1)
+	assert.Equal(t, "shop_data", h.m.volumesView.volumes[0].Name)
+}
+
+func sampleNetworks() []docker.Network {
+	return []docker.Network{
+		{ID: "9a8b7c6d5e4f", Name: "bridge", Driver: "bridge", Scope: "local", Subnet: "172.17.0.0/16", Gateway: "172.17.0.1"},
+		{ID: "1f2e3d4c5b6a", Name: "shop_default", Driver: "bridge", Scope: "local", Subnet: "172.18.0.0/16", Gateway: "172.18.0.1",
+			Containers: []docker.NetworkAttachment{
+				{ContainerID: "0f9e8d7c6b5a", Name: "shop-db-1", State: "running", IPAddress: "172.18.0.3/16", MacAddress: "02:42:ac:12:00:03"},
+				{ContainerID: "a1b2c3d4e5f6", Name: "shop-web-1", State: "running", IPAddress: "172.18.0.2/16", MacAddress: "02:42:ac:12:00:02"},
+			}},
+	}
+}
+
+func TestNetworksView(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetNetworks(sampleNetworks()...)
+	h.press("f6")
+
+	require.Equal(t, modeNetworks, h.m.currentMode)
+	// user networks sort first and start expanded, bridge stays folded
+	require.Len(t, h.m.networksView.rows, 4)
+	assert.Equal(t, "shop_default", h.m.networksView.rows[0].network.Name)
+	golden(t, "networks_view", h.view())
+
+	h.press("esc")
+	assert.Equal(t, modeNormal, h.m.currentMode)
+}
+
+func TestNetworksConnectDisconnect(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetNetworks(sampleNetworks()...)
+	h.press("f6")
+
+	// only old-job isn't on shop_default yet
+	h.press("c")
+	require.True(t, h.m.networksView.picking)
+	require.Len(t, h.m.networksView.candidates, 1)
+	assert.Equal(t, "old-job", h.m.networksView.candidates[0].Names[0])
+	h.press("enter")
+	assert.Equal(t, []string{"network connect shop_default 112233445566"}, h.rt.Calls())
+	assert.Equal(t, "Connected old-job to shop_default", h.m.statusMessage)
+	require.Len(t, h.m.networksView.networks[0].Containers, 3)
+
+	// disconnect shop-db-1, it asks first
+	h.press("home")
+	h.press("down")
+	require.Equal(t, "shop-db-1", h.m.selectedNetworkRow().att.Name)
+	h.press("d")
+	assert.Contains(t, h.view(), "Disconnect shop-db-1 from shop_default? (y/n)")
+	h.press("n")
+	assert.Len(t, h.rt.Calls(), 1)
+	h.press("d")
+	h.press("y")
+	assert.Equal(t, "network disconnect shop_default 0f9e8d7c6b5a", h.rt.Calls()[1])
+	assert.Equal(t, "Disconnected shop-db-1 from shop_default", h.m.statusMessage)
+	require.Len(t, h.m.networksView.networks[0].Containers, 2)
+}
+
+func TestStatsUnits(t *testing.T) {
+	// sorting goes by the numbers, not the text: 999kB is less than 1.2MB
+	h := newSampleHarness(t)
+	h.send(statsMsg{Stats: map[string]docker.ContainerStats{
+		"a1b2c3d4e5f6": {ID: "a1b2c3d4e5f6", MemUsed: 1_200_000},
+		"0f9e8d7c6b5a": {ID: "0f9e8d7c6b5a", MemUsed: 999_000},
+	}})
+	h.m.sortBy, h.m.sortAsc = "memory", false
+	h.m.sortContainers()
+	assert.Equal(t, "a1b2c3d4e5f6", h.m.containers[0].ID)
+	assert.Equal(t, "1.2MB", h.m.memText(h.m.containers[0].Stats))
+
+	h.m.settings.Units = units.IEC
+	assert.Equal(t, "976KiB", h.m.memText(h.m.containers[1].Stats))
+}
+
+func TestHostSwitcher(t *testing.T) {
+	h := newSampleHarness(t)
+	remote := docker.NewFakeRuntime(docker.Container{
+		ID: "99aabbccddee", Names: []string{"api-1"}, Image: "node:22",
+		Status: "Up 1 minute", State: "running",
+	})
+	h.m.hostNames = []string{docker.LocalHost, "vm1", docker.AllHosts}
+	h.m.connect = func(host string) (docker.Runtime, error) {
+		if host != docker.AllHosts {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return docker.NewMultiRuntime(
+			docker.NamedRuntime{Name: docker.LocalHost, Runtime: h.rt},
+			docker.NamedRuntime{Name: "vm1", Runtime: remote},
+		), nil
+	}
+
+	// a host that doesn't answer leaves everything as it was
+	h.press("H")
+	require.NotNil(t, h.m.actionDialog)
+	h.press("down")
+	h.press("enter")
+	assert.Contains(t, h.m.statusMessage, "Can't connect to vm1")
+	assert.Empty(t, h.m.host)
+	assert.Len(t, h.m.allContainers, 3)
+
+	// all lists both hosts with a HOST column
+	h.press("H")
+	h.press("down")
+	h.press("down")
+	h.press("enter")
+	assert.Equal(t, docker.AllHosts, h.m.host)
+	require.Len(t, h.m.allContainers, 4)
+	golden(t, "hosts_all", h.view())
+
+	// actions go to the container's own host
+	h.press("/")
+	h.press("host:vm1")
+	h.press("enter")
+	require.Len(t, h.m.containers, 1)
+	h.press("x")
+	assert.Equal(t, []string{"stop 99aabbccddee"}, remote.Calls())
+	assert.Empty(t, h.rt.Calls())
+}
+
+func TestConfigBanner(t *testing.T) {
+	h := newSampleHarness(t)
+	_, problems := config.Validate([]byte("performance:\n  poll_rate: 0\nruntime:\n  type: lxc\n"))
+	h.m = h.m.WithConfigProblems(&config.ValidationError{Path: "/home/me/.config/dockmate/config.yml", Problems: problems})
+	require.NotNil(t, h.m.configBanner)
+	golden(t, "config_banner", h.view())
+
+	// any key closes it and does nothing else
+	h.press("x")
+	assert.Nil(t, h.m.configBanner)
+	assert.Empty(t, h.rt.Calls())
+
+	// a clean config opens nothing
+	h.m = h.m.WithConfigProblems(nil)
+	assert.Nil(t, h.m.configBanner)
+}
+
+func TestConfigReload(t *testing.T) {
+	h := newSampleHarness(t)
+	podman := docker.NewFakeRuntime(sampleContainers()...)
+	openHost = func(cfg *config.Config, host string) (docker.Runtime, error) {
+		if cfg.Runtime.Type == "podman" {
+			return podman, nil
+		}
+		return nil, fmt.Errorf("unexpected runtime %s", cfg.Runtime.Type)
+	}
+	t.Cleanup(func() { openHost = docker.OpenHost })
+
+	h.press("down")
+	h.press("l")
+	require.True(t, h.m.logsVisible)
+	logsFor := h.m.logsContainer
+
+	// nothing changed on disk, nothing happens
+	h.send(tickMsg(time.Now()))
+	assert.Empty(t, h.m.statusMessage)
+
+	cfg := config.DefaultConfig()
+	cfg.Performance.PollRate = 5
+	cfg.Layout.Columns[3].Hidden = true
+	cfg.Exec.Shell = "/bin/bash"
+	require.NoError(t, cfg.Save())
+	h.send(tickMsg(time.Now()))
+	assert.Equal(t, "Config reloaded", h.m.statusMessage)
+	assert.Equal(t, 5, h.m.settings.RefreshInterval)
+	assert.Equal(t, "/bin/bash", h.m.settings.Shell)
+	assert.True(t, h.m.settings.Columns[3].Hidden)
+	assert.Same(t, h.rt, h.m.rt, "same runtime settings, same backend")
+
+	// a runtime change swaps the backend and keeps the cursor and panels
+	cfg.Runtime.Type = "podman"
+	require.NoError(t, cfg.Save())
+	h.send(tickMsg(time.Now()))
+	assert.Same(t, podman, h.m.rt)
+	assert.Equal(t, 1, h.m.cursor)
+	assert.True(t, h.m.logsVisible)
+	assert.Equal(t, logsFor, h.m.logsContainer)
+	assert.Len(t, h.m.allContainers, 3)
+
+	// a broken edit brings the banner back, the defaults are used meanwhile
+	path, err := config.GetConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("version: 1\nperformance:\n  poll_rate: 0\nruntime:\n  type: podman\n"), 0644))
+	h.send(tickMsg(time.Now()))
+	require.NotNil(t, h.m.configBanner)
+	assert.Equal(t, 2, h.m.settings.RefreshInterval)
+
+	// and saving settings over it is refused, the file stays as it is
+	h.press("x")
+	broken := "perf: [\n"
+	require.NoError(t, os.WriteFile(path, []byte(broken), 0644))
+	h.press("f2")
+	h.press("s")
+	assert.Equal(t, "Not saved: fix the errors in config.yml first", h.m.statusMessage)
+	assert.NotNil(t, h.m.configBanner)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, broken, string(data))
+}
+
+func TestRemappedKeys(t *testing.T) {
+	h := newSampleHarness(t)
+	cfg := config.DefaultConfig()
+	cfg.Keys = config.KeysConfig{Bindings: map[string]config.KeyList{"stop": {"b"}, "images": {"f7"}}}
+	h.m.applyConfig(cfg)
+
+	// the old key is free, the new one stops
+	h.press("x")
+	assert.Empty(t, h.rt.Calls())
+	h.press("b")
+	assert.Equal(t, []string{"stop a1b2c3d4e5f6"}, h.rt.Calls())
+
+	// footer and help follow the bindings
+	assert.Contains(t, h.view(), "[f7]→Images")
+	var stop string
+	for _, it := range getHelpItems(h.m) {
+		if strings.HasPrefix(it.(item).desc, "Stop selected container (") {
+			stop = it.(item).key
+		}
+	}
+	assert.Equal(t, "b", stop)
+}
+
+func TestRemappedViewKeys(t *testing.T) {
+	h := newSampleHarness(t)
+	h.rt.SetImages(sampleImages()...)
+	cfg := config.DefaultConfig()
+	cfg.Keys = config.KeysConfig{Bindings: map[string]config.KeyList{
+		"timestamps": {"T"}, "delete": {"backspace"}, "cancel": {"b"},
+	}}
+	h.m.applyConfig(cfg)
+
+	// with the logs panel focused t is nothing now, stop_wait isn't read there
+	h.press("l")
+	h.press("t")
+	assert.False(t, h.m.logsTimestamps)
+	assert.Nil(t, h.m.actionDialog)
+	h.press("T")
+	assert.True(t, h.m.logsTimestamps)
+	assert.Contains(t, h.view(), "[T]→Time")
+	h.press("esc")
+
+	h.press("f3")
+	h.press("d")
+	assert.Nil(t, h.m.confirm)
+	h.press("backspace")
+	require.NotNil(t, h.m.confirm)
+	assert.Contains(t, h.view(), "y/Enter confirm  b/Esc cancel")
+	h.press("n")
+	assert.NotNil(t, h.m.confirm)
+	h.press("b")
+	assert.Nil(t, h.m.confirm)
+	assert.Empty(t, h.rt.Calls())
+
+	var timestamps string
+	for _, it := range getHelpItems(h.m) {
+		if it.(item).desc == "Logs: show/hide timestamps" {
+			timestamps = it.(item).key
+		}
+	}
+	assert.Equal(t, "T", timestamps)
+}
+
+func TestThemes(t *testing.T) {
+	h := newSampleHarness(t)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		applyTheme(config.ThemeConfig{})
+	})
+
+	// picked in settings, saved and applied right away
+	h.press("f2")
+	for i := 0; i < len(h.m.settings.Columns)+settingsTheme; i++ {
+		h.press("down")
+	}
+	h.press("right")
+	assert.Equal(t, "light", h.m.settings.Theme)
+	h.press("s")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "light", cfg.Theme.Name)
+	assert.Equal(t, lipgloss.Color("#0F172A"), textPrimary)
+
+	// a role override goes on top of the theme
+	applyTheme(config.ThemeConfig{Name: "dark", Colors: map[string]string{"accent": "#ffffff"}})
+	assert.Equal(t, lipgloss.Color("#ffffff"), accent)
+	assert.Equal(t, lipgloss.Color("#F8FAFC"), textPrimary)
+
+	// 16 color terminals get the theme's own pick instead of the nearest color
+	lipgloss.SetColorProfile(termenv.ANSI)
+	applyTheme(config.ThemeConfig{Name: "dark"})
+	assert.Equal(t, lipgloss.Color("10"), meterGreen)
+
+	// no colors, the bars turn into reverse video so the cursor still shows
+	applyTheme(config.ThemeConfig{Name: "monochrome"})
+	assert.True(t, selectedStyle.GetReverse())
+	assert.True(t, markedStyle.GetUnderline())
+	assert.NotEqual(t, "shop-web-1", selectedStyle.Render("shop-web-1"))
+}
+
+func TestSettingsSaveKeepsFlags(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { applyTheme(config.ThemeConfig{}) })
+	require.NoError(t, config.DefaultConfig().Save())
+
+	// what --runtime podman --theme monochrome --refresh 9s do to the config
+	withFlags := func() (*config.Config, error) {
+		cfg, err := config.Load()
+		cfg.Runtime.Type = "podman"
+		cfg.Theme.Name = "monochrome"
+		cfg.Performance.PollRate = 9
+		return cfg, err
+	}
+	cfg, _ := withFlags()
+	rt := docker.NewFakeRuntime(sampleContainers()...)
+	h := &harness{m: NewModel(cfg, rt).WithConfigLoader(withFlags), rt: rt}
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 24})
+	h.run(h.m.Init())
+
+	// only the shell is edited
+	h.press("f2")
+	for i := 0; i < len(h.m.settings.Columns)+settingsShell; i++ {
+		h.press("down")
+	}
+	h.press("right")
+	shell := h.m.settings.Shell
+	h.press("s")
+	assert.Equal(t, "Settings saved!", h.m.statusMessage)
+
+	saved, err := config.Load()
+	require.NoError(t, err)
+	defaults := config.DefaultConfig()
+	assert.Equal(t, shell, saved.Exec.Shell)
+	assert.Equal(t, defaults.Runtime, saved.Runtime)
+	assert.Equal(t, defaults.Theme.Name, saved.Theme.Name)
+	assert.Equal(t, defaults.Performance.PollRate, saved.Performance.PollRate)
+
+	// and the run keeps its flags, no backend switch
+	assert.Same(t, rt, h.m.rt)
+	assert.Equal(t, RuntimePodman, h.m.settings.Runtime)
+	assert.Equal(t, "monochrome", h.m.settings.Theme)
+	assert.Equal(t, 9, h.m.settings.RefreshInterval)
+	assert.Equal(t, shell, h.m.settings.Shell)
+}
+
+func TestColumnLayout(t *testing.T) {
+	containers := sampleContainers()
+	containers[1].Status = "Up 3 hours (healthy)"
+	h := newHarness(t, containers...)
+	h.rt.SetInspect("a1b2c3d4e5f6", &docker.ContainerInspect{ID: "a1b2c3d4e5f6", State: "running", RestartCount: 2,
+		Networks: []docker.NetworkEndpoint{{Name: "shop_default", IPAddress: "172.18.0.2/16"}}})
+	h.rt.SetInspect("0f9e8d7c6b5a", &docker.ContainerInspect{ID: "0f9e8d7c6b5a", State: "running",
+		Networks: []docker.NetworkEndpoint{{Name: "shop_default", IPAddress: "172.18.0.3/16"}}})
+
+	// reordered, new columns, a label column, restarts and ip come from an inspect
+	cfg := config.DefaultConfig()
+	cfg.Layout.Columns = []config.ColumnConfig{
+		{Name: "name", Width: 20},
+		{Name: "service", Width: 10},
+		{Name: "restarts", Width: 10},
+		{Name: "health", Width: 10},
+		{Name: "ip", Width: 14},
+		{Name: "label:com.docker.compose.project", Width: 10},
+		{Name: "id", Width: 8, Hidden: true},
+	}
+	h.run(h.m.applyConfig(cfg))
+	assert.Equal(t, 2, h.m.details["a1b2c3d4e5f6"].restarts)
+	golden(t, "column_layout", h.view())
+
+	// any column sorts
+	h.press("tab")
+	h.press("right")
+	h.press("right")
+	h.press("enter")
+	h.press("enter")
+	assert.Equal(t, "Sorted by RESTARTS (desc)", h.m.statusMessage)
+	assert.Equal(t, "a1b2c3d4e5f6", h.m.containers[0].ID)
+	h.press("esc")
+
+	// settings moves columns around and switches the unlisted ones on
+	h.press("f2")
+	require.Len(t, h.m.settings.Columns, len(cfg.Layout.Columns)+len(columns.All)-6)
+	h.press("J")
+	assert.Equal(t, "service", h.m.settings.Columns[0].Name)
+	assert.Equal(t, 1, h.m.settingsSelected)
+	for i := 1; i < 6; i++ {
+		h.press("down")
+	}
+	h.press("space")
+	h.press("s")
+	saved, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "service", saved.Layout.Columns[0].Name)
+	assert.Equal(t, config.ColumnConfig{Name: "id", Width: 8}, saved.Layout.Columns[6])
+	assert.Contains(t, h.view(), "CONTAINER ID")
+}
+
+func TestColumnLayoutFits(t *testing.T) {
+	h := newSampleHarness(t)
+	visible := h.m.visibleColumns()
+	for _, usable := range []int{10, 40, 60, 83, 118, 200} {
+		shown := h.m.layoutColumns(usable)
+		total := 0
+		for i, col := range shown {
+			// the first columns, in order, none under its minimum
+			assert.Equal(t, visible[i].Name, col.Name, usable)
+			if len(shown) > 1 {
+				assert.GreaterOrEqual(t, col.width, col.Min, "%d: %s", usable, col.Name)
+			}
+			total += col.width
+		}
+		assert.Equal(t, usable, total, "%d wide", usable)
+	}
+
+	// the minimums add up to 83, narrower drops columns off the end
+	assert.Len(t, h.m.layoutColumns(83), len(visible))
+	assert.Len(t, h.m.layoutColumns(60), 5)
+	assert.Len(t, h.m.layoutColumns(10), 1)
+}
+
+func TestHealth(t *testing.T) {
+	containers := sampleContainers()
+	containers[0].Status = "Up 5 minutes (unhealthy)"
+	containers[1].Status = "Up 3 hours (healthy)"
+	h := newHarness(t, containers...)
+	h.rt.SetInspect("a1b2c3d4e5f6", &docker.ContainerInspect{ID: "a1b2c3d4e5f6", State: "running",
+		Health: &docker.HealthState{Status: docker.HealthUnhealthy, FailingStreak: 3, Probes: []docker.HealthProbe{
+			{Start: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ExitCode: 0, Output: "ok"},
+			{Start: time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC), ExitCode: 1, Output: "connection refused"},
+		}}})
+
+	// counted in the header, failing rows get their own color
+	assert.Contains(t, h.view(), "Healthy: 1  Unhealthy: 1  Starting: 0")
+	assert.Equal(t, unhealthyStyle, containerStyle(&h.m.containers[0]))
+	assert.Equal(t, runningStyle, containerStyle(&h.m.containers[1]))
+
+	// the health column sorts the failing ones to the top
+	h.m.sortBy, h.m.sortAsc = "health", false
+	h.m.sortContainers()
+	assert.Equal(t, []docker.Health{docker.HealthUnhealthy, docker.HealthHealthy, docker.HealthNone},
+		[]docker.Health{h.m.containers[0].Health, h.m.containers[1].Health, h.m.containers[2].Health})
+
+	h.press("/")
+	h.press("health:unhealthy")
+	require.Len(t, h.m.containers, 1)
+	assert.Equal(t, "a1b2c3d4e5f6", h.m.containers[0].ID)
+	h.press("enter")
+
+	// inspect lists the probes, the last one first
+	h.press("o")
+	require.Equal(t, modeInspect, h.m.currentMode)
+	var probes []string
+	for _, r := range h.m.inspectRows() {
+		if strings.HasPrefix(r.node.key, "2024-05-01") {
+			probes = append(probes, r.node.value)
+		}
+	}
+	assert.Equal(t, []string{"exit 1  connection refused", "exit 0  ok"}, probes)
+}
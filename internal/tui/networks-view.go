@@ -0,0 +1,472 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Networks view
+// ============================================================================
+
+// networksView is everything the networks mode keeps between frames
+type networksView struct {
+	networks []docker.Network
+	expanded map[string]bool // networks showing their containers
+	rows     []networkRow    // networks + expanded attachments, what the cursor moves over
+	cursor   int
+	scroll   int
+	loading  bool
+
+	confirmDisconnect *networkRow // attachment waiting for y to be disconnected
+
+	picking    bool               // choosing a container to connect
+	candidates []docker.Container // containers not on the selected network yet
+	pickCursor int
+}
+
+// networkRow is a network, or one of its containers when att is set
+type networkRow struct {
+	network *docker.Network
+	att     *docker.NetworkAttachment
+}
+
+type networksMsg struct {
+	networks []docker.Network
+	err      error
+}
+
+type networkActionMsg struct {
+	action    string // connect/disconnect
+	network   string
+	container string // name, for the status line
+	err       error
+}
+
+func fetchNetworks(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		networks, err := rt.ListNetworks()
+		return networksMsg{networks: networks, err: err}
+	}
+}
+
+func connectNetworkCmd(rt docker.Runtime, network, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		return networkActionMsg{action: "connect", network: network, container: name, err: rt.ConnectNetwork(network, id)}
+	}
+}
+
+func disconnectNetworkCmd(rt docker.Runtime, network, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		return networkActionMsg{action: "disconnect", network: network, container: name, err: rt.DisconnectNetwork(network, id)}
+	}
+}
+
+func (m *model) openNetworks() tea.Cmd {
+	m.closeLogs()
+	m.infoVisible = false
+	m.columnMode = false
+	m.currentMode = modeNetworks
+	if m.networksView.expanded == nil {
+		m.networksView.expanded = map[string]bool{}
+	}
+	m.networksView.loading = true
+	m.networksView.confirmDisconnect = nil
+	m.networksView.picking = false
+	m.statusMessage = "Loading networks..."
+	return fetchNetworks(m.rt)
+}
+
+func (m *model) closeNetworks() {
+	m.currentMode = modeNormal
+	if m.composeViewMode {
+		m.currentMode = modeComposeView
+	}
+	m.updatePagination()
+	m.statusMessage = "Networks closed"
+}
+
+// buildNetworkRows flattens the network tree, same idea as buildFlatList
+func (m *model) buildNetworkRows() {
+	v := &m.networksView
+	v.rows = v.rows[:0]
+	for i := range v.networks {
+		n := &v.networks[i]
+		v.rows = append(v.rows, networkRow{network: n})
+		if v.expanded[n.Name] {
+			for j := range n.Containers {
+				v.rows = append(v.rows, networkRow{network: n, att: &n.Containers[j]})
+			}
+		}
+	}
+}
+
+func (m model) selectedNetworkRow() *networkRow {
+	v := m.networksView
+	if v.cursor < 0 || v.cursor >= len(v.rows) {
+		return nil
+	}
+	return &v.rows[v.cursor]
+}
+
+func (m model) networksPageSize() int {
+	return max(1, m.terminalHeight-5)
+}
+
+func (m *model) clampNetworksCursor() {
+	v := &m.networksView
+	if v.cursor >= len(v.rows) {
+		v.cursor = len(v.rows) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+	page := m.networksPageSize()
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+page {
+		v.scroll = v.cursor - page + 1
+	}
+	if v.scroll < 0 {
+		v.scroll = 0
+	}
+	if v.pickCursor >= len(v.candidates) {
+		v.pickCursor = len(v.candidates) - 1
+	}
+	if v.pickCursor < 0 {
+		v.pickCursor = 0
+	}
+}
+
+func (m model) handleNetworksMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	v := &m.networksView
+
+	switch msg := msg.(type) {
+	case networksMsg:
+		v.loading = false
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error listing networks: %v", msg.err)
+			return m, nil
+		}
+		// keep the cursor on the same row across refreshes
+		var selNet, selID string
+		if row := m.selectedNetworkRow(); row != nil {
+			selNet = row.network.Name
+			if row.att != nil {
+				selID = row.att.ContainerID
+			}
+		}
+		v.networks = msg.networks
+		for _, n := range v.networks {
+			// user networks start expanded, they're the ones being debugged
+			if _, seen := v.expanded[n.Name]; !seen {
+				v.expanded[n.Name] = !n.Builtin()
+			}
+		}
+		m.buildNetworkRows()
+		for i, row := range v.rows {
+			if row.network.Name != selNet {
+				continue
+			}
+			if (row.att == nil && selID == "") || (row.att != nil && row.att.ContainerID == selID) {
+				v.cursor = i
+				break
+			}
+		}
+		if m.statusMessage == "Loading networks..." {
+			m.statusMessage = ""
+		}
+		m.clampNetworksCursor()
+		return m, nil
+
+	case networkActionMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", msg.err)
+		} else if msg.action == "connect" {
+			m.statusMessage = fmt.Sprintf("Connected %s to %s", msg.container, msg.network)
+		} else {
+			m.statusMessage = fmt.Sprintf("Disconnected %s from %s", msg.container, msg.network)
+		}
+		// the container's ports/ips changed too
+		return m, tea.Batch(fetchNetworks(m.rt), fetchContainers(m.rt))
+	}
+	return m, nil
+}
+
+// startPicking lists the containers that could be connected to n
+func (m *model) startPicking(n *docker.Network) {
+	v := &m.networksView
+	attached := map[string]bool{}
+	for _, a := range n.Containers {
+		attached[a.ContainerID] = true
+	}
+	v.candidates = nil
+	for _, c := range m.allContainers {
+		if !attached[shortContainerID(c.ID)] {
+			v.candidates = append(v.candidates, c)
+		}
+	}
+	if len(v.candidates) == 0 {
+		m.statusMessage = fmt.Sprintf("Every container is already on %s", n.Name)
+		return
+	}
+	v.picking = true
+	v.pickCursor = 0
+}
+
+// podman hands out 64 char ids, attachments always carry the short form
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func (m *model) handleNetworksKey(msg tea.KeyMsg) tea.Cmd {
+	v := &m.networksView
+
+	if msg.String() == "ctrl+c" {
+		return m.quit()
+	}
+
+	row := m.selectedNetworkRow()
+
+	// container picker for connect
+	if v.picking {
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			v.picking = false
+		case key.Matches(msg, m.keys.Up):
+			v.pickCursor--
+		case key.Matches(msg, m.keys.Down):
+			v.pickCursor++
+		case key.Matches(msg, m.keys.Select):
+			v.picking = false
+			if row == nil || v.pickCursor >= len(v.candidates) {
+				return nil
+			}
+			c := v.candidates[v.pickCursor]
+			name := c.ID
+			if len(c.Names) > 0 {
+				name = c.Names[0]
+			}
+			m.statusMessage = fmt.Sprintf("Connecting %s to %s...", name, row.network.Name)
+			return connectNetworkCmd(m.rt, row.network.Name, c.ID, name)
+		}
+		m.clampNetworksCursor()
+		return nil
+	}
+
+	// disconnect confirmation only takes yes
+	if target := v.confirmDisconnect; target != nil {
+		v.confirmDisconnect = nil
+		if !key.Matches(msg, m.keys.Confirm) {
+			m.statusMessage = "Cancelled"
+			return nil
+		}
+		m.statusMessage = fmt.Sprintf("Disconnecting %s from %s...", target.att.Name, target.network.Name)
+		return disconnectNetworkCmd(m.rt, target.network.Name, target.att.ContainerID, target.att.Name)
+	}
+
+	// the key that opened the view closes it again
+	if key.Matches(msg, m.keys.Networks) {
+		m.closeNetworks()
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.quit()
+	case key.Matches(msg, m.keys.Back):
+		m.closeNetworks()
+		return nil
+	case key.Matches(msg, m.keys.Up):
+		v.cursor--
+	case key.Matches(msg, m.keys.Down):
+		v.cursor++
+	case key.Matches(msg, m.keys.PageUp):
+		v.cursor -= m.networksPageSize()
+	case key.Matches(msg, m.keys.PageDown):
+		v.cursor += m.networksPageSize()
+	case key.Matches(msg, m.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		v.cursor = len(v.rows) - 1
+	case key.Matches(msg, m.keys.Select, m.keys.Toggle, m.keys.Expand, m.keys.Collapse):
+		if row == nil {
+			break
+		}
+		name := row.network.Name
+		switch {
+		case key.Matches(msg, m.keys.Expand):
+			v.expanded[name] = true
+		case key.Matches(msg, m.keys.Collapse):
+			v.expanded[name] = false
+		default:
+			v.expanded[name] = !v.expanded[name]
+		}
+		m.buildNetworkRows()
+		// collapsing from a container row lands on its network
+		if !v.expanded[name] {
+			for i, r := range v.rows {
+				if r.att == nil && r.network.Name == name {
+					v.cursor = i
+				}
+			}
+		}
+	case key.Matches(msg, m.keys.Reload):
+		m.statusMessage = "Loading networks..."
+		return fetchNetworks(m.rt)
+	case key.Matches(msg, m.keys.Connect):
+		if row == nil {
+			return nil
+		}
+		if row.network.Name == "host" || row.network.Name == "none" {
+			m.statusMessage = fmt.Sprintf("Containers can't be connected to %s after they're created", row.network.Name)
+			return nil
+		}
+		m.startPicking(row.network)
+	case key.Matches(msg, m.keys.Disconnect):
+		if row == nil || row.att == nil {
+			m.statusMessage = "Select a container under a network to disconnect it"
+			return nil
+		}
+		target := *row
+		v.confirmDisconnect = &target
+	}
+	m.clampNetworksCursor()
+	return nil
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderNetworks(width int) string {
+	var b strings.Builder
+	v := m.networksView
+
+	b.WriteString(m.renderTitleBar(width))
+	b.WriteString("\n")
+
+	attached := 0
+	for _, n := range v.networks {
+		attached += len(n.Containers)
+	}
+	title := fmt.Sprintf("Networks: %d  Endpoints: %d ", len(v.networks), attached)
+	if v.loading && len(v.networks) == 0 {
+		title = "Networks: loading... "
+	}
+	if v.picking {
+		if row := m.selectedNetworkRow(); row != nil {
+			title = fmt.Sprintf("Connect a container to %s ", row.network.Name)
+		}
+	}
+	if visibleLen(title) < width {
+		title += strings.Repeat(" ", width-visibleLen(title))
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	// network rows:   name / driver / subnet / gateway / containers
+	// container rows: name / state  / ip     / mac     / ipv6
+	driverW, subnetW, gatewayW := 10, 20, 18
+	nameW := max(20, (width-3-driverW-subnetW-gatewayW)/2)
+	lastW := max(10, width-3-nameW-driverW-subnetW-gatewayW-4)
+	columns := func(name, driver, subnet, gateway, last string) string {
+		return strings.Join([]string{
+			padRight(truncateToWidth(name, nameW), nameW),
+			padRight(truncateToWidth(driver, driverW), driverW),
+			padRight(truncateToWidth(subnet, subnetW), subnetW),
+			padRight(truncateToWidth(gateway, gatewayW), gatewayW),
+			truncateToWidth(last, lastW),
+		}, " ")
+	}
+
+	header := "   " + columns("NETWORK / CONTAINER", "DRIVER", "SUBNET / IP", "GATEWAY / MAC", "CONTAINERS / IPV6")
+	b.WriteString(headerStyle.Render(padRight(header, width)))
+	b.WriteString("\n")
+
+	page := m.networksPageSize()
+	var lines []string
+	if v.picking {
+		for i, c := range v.candidates {
+			name := c.ID
+			if len(c.Names) > 0 {
+				name = c.Names[0]
+			}
+			line := padRight(fmt.Sprintf("   %s (%s) %s", name, shortContainerID(c.ID), c.State), width)
+			if i == v.pickCursor {
+				line = selectedStyle.Render(line)
+			} else {
+				line = normalStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > page {
+			start := min(max(0, v.pickCursor-page+1), len(lines)-page)
+			lines = lines[start : start+page]
+		}
+	} else {
+		end := min(len(v.rows), v.scroll+page)
+		for i := v.scroll; i < end; i++ {
+			row := v.rows[i]
+			selected := i == v.cursor
+
+			if row.att == nil {
+				n := row.network
+				running := 0
+				for _, a := range n.Containers {
+					if a.State == "running" {
+						running++
+					}
+				}
+				driver := n.Driver
+				if n.Internal {
+					driver += " (int)"
+				}
+				users := fmt.Sprintf("%d/%d running", running, len(n.Containers))
+				label := columns(n.Name, driver, orDash(n.Subnet), orDash(n.Gateway), users)
+				lines = append(lines, renderGroupRow(label, v.expanded[n.Name], selected, width))
+				continue
+			}
+
+			a := row.att
+			name := treeBranch(1) + a.Name
+			line := padRight("   "+columns(name, a.State, orDash(a.IPAddress), orDash(a.MacAddress), a.IPv6Address), width)
+			switch {
+			case selected:
+				line = selectedStyle.Render(line)
+			case a.State == "running":
+				line = runningStyle.Render(line)
+			default:
+				line = stoppedStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+	for len(lines) < page {
+		lines = append(lines, "")
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n")
+
+	status := m.statusMessage
+	switch {
+	case v.picking:
+		status = fmt.Sprintf("%s pick a container, %s to connect, %s to cancel", label(m.keys.Up, m.keys.Down), label(m.keys.Select), label(m.keys.Back))
+	case v.confirmDisconnect != nil:
+		status = fmt.Sprintf("Disconnect %s from %s? (%s/n)", v.confirmDisconnect.att.Name, v.confirmDisconnect.network.Name, label(m.keys.Confirm))
+	case status == "" && len(v.rows) > page:
+		status = fmt.Sprintf("%d-%d of %d", v.scroll+1, min(len(v.rows), v.scroll+page), len(v.rows))
+	}
+	b.WriteString(normalStyle.Render(" " + status))
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter(width))
+	return b.String()
+}
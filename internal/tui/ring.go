@@ -0,0 +1,55 @@
+package tui
+
+// ring is a fixed size buffer that drops the oldest item once full
+type ring[T any] struct {
+	items []T
+	start int // index of the oldest item
+	size  int
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ring[T]{items: make([]T, capacity)}
+}
+
+// Push appends v, overwriting the oldest item when full
+func (r *ring[T]) Push(v T) {
+	if r.size < len(r.items) {
+		r.items[(r.start+r.size)%len(r.items)] = v
+		r.size++
+		return
+	}
+	r.items[r.start] = v
+	r.start = (r.start + 1) % len(r.items)
+}
+
+// Len is the number of items held
+func (r *ring[T]) Len() int { return r.size }
+
+// Cap is the most items the ring holds
+func (r *ring[T]) Cap() int { return len(r.items) }
+
+// At returns the i-th item, 0 being the oldest
+func (r *ring[T]) At(i int) T {
+	return r.items[(r.start+i)%len(r.items)]
+}
+
+// Slice copies the items out, oldest first
+func (r *ring[T]) Slice() []T {
+	out := make([]T, r.size)
+	for i := range out {
+		out[i] = r.At(i)
+	}
+	return out
+}
+
+// Reset empties the ring without reallocating
+func (r *ring[T]) Reset() {
+	var zero T
+	for i := range r.items {
+		r.items[i] = zero
+	}
+	r.start, r.size = 0, 0
+}
@@ -0,0 +1,360 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Multi-select & bulk actions
+// ============================================================================
+
+const (
+	bulkParallelism = 4  // actions in flight at once during a bulk action
+	bulkSummaryRows = 12 // result lines shown before "... and N more"
+)
+
+// bulkResult is the outcome of an action on one container
+type bulkResult struct {
+	name string
+	err  error
+}
+
+type bulkDoneMsg struct {
+	action  string
+	results []bulkResult
+	failed  map[string]bool // ids that failed, they stay marked for a retry
+}
+
+// bulkSummary is the result modal shown after a bulk action, any key closes it
+type bulkSummary struct {
+	action  string
+	results []bulkResult
+}
+
+// bulkActionCmd runs fn over every target, at most bulkParallelism at a time
+func bulkActionCmd(action string, targets []docker.Container, fn func(c docker.Container) error) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, len(targets))
+		sem := make(chan struct{}, bulkParallelism)
+		var wg sync.WaitGroup
+		for i, c := range targets {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = bulkResult{name: containerName(c), err: fn(c)}
+			}()
+		}
+		wg.Wait()
+
+		failed := map[string]bool{}
+		for i, r := range results {
+			if r.err != nil {
+				failed[targets[i].ID] = true
+			}
+		}
+		return bulkDoneMsg{action: action, results: results, failed: failed}
+	}
+}
+
+// ============================================================================
+// Marks
+// ============================================================================
+
+// markPrefix is drawn in front of the name of marked rows
+func (m model) markPrefix(id string) string {
+	if m.marked[id] {
+		return "● "
+	}
+	return ""
+}
+
+// listedContainers is every container the current view lists (so what
+// matches the filter), in order
+func (m *model) listedContainers() []*docker.Container {
+	var out []*docker.Container
+	if m.composeViewMode {
+		for _, p := range sortedProjectNames(m.projects) {
+			for i, c := range m.projects[p].Containers {
+				if m.filter.Match(c) {
+					out = append(out, &m.projects[p].Containers[i])
+				}
+			}
+		}
+		for _, c := range m.standaloneContainers() {
+			out = append(out, c)
+		}
+		return out
+	}
+	for i := range m.containers {
+		out = append(out, &m.containers[i])
+	}
+	return out
+}
+
+// toggleMark marks the row under the cursor (a project row marks all its
+// containers) and moves down so runs of rows can be marked quickly
+func (m *model) toggleMark() {
+	var ids []string
+	if m.composeViewMode {
+		if m.cursor >= len(m.flatList) {
+			return
+		}
+		row := m.flatList[m.cursor]
+		switch {
+		case !row.isProject:
+			ids = []string{row.container.ID}
+		case row.projectName == standaloneGroup:
+			for _, c := range m.standaloneContainers() {
+				ids = append(ids, c.ID)
+			}
+		case m.projects[row.projectName] != nil:
+			for _, c := range m.projects[row.projectName].Containers {
+				if m.filter.Match(c) {
+					ids = append(ids, c.ID)
+				}
+			}
+		}
+	} else if m.cursor < len(m.containers) {
+		ids = []string{m.containers[m.cursor].ID}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	// a group toggles as a whole: mark them all unless they all are already
+	mark := false
+	for _, id := range ids {
+		if !m.marked[id] {
+			mark = true
+		}
+	}
+	for _, id := range ids {
+		if mark {
+			m.marked[id] = true
+		} else {
+			delete(m.marked, id)
+		}
+	}
+
+	if m.composeViewMode {
+		m.moveCursorDownTree()
+	} else if m.cursor < len(m.containers)-1 {
+		m.cursor++
+	}
+	m.updatePagination()
+	m.statusMessage = fmt.Sprintf("%d selected", len(m.marked))
+}
+
+// markAll marks every listed container, or clears the marks if they all are
+func (m *model) markAll() {
+	listed := m.listedContainers()
+	all := len(listed) > 0
+	for _, c := range listed {
+		if !m.marked[c.ID] {
+			all = false
+		}
+	}
+	if all {
+		m.clearMarks()
+		return
+	}
+	for _, c := range listed {
+		m.marked[c.ID] = true
+	}
+	m.statusMessage = fmt.Sprintf("%d selected", len(m.marked))
+}
+
+func (m *model) clearMarks() {
+	m.marked = map[string]bool{}
+	m.statusMessage = "Selection cleared"
+}
+
+// pruneMarks drops marks of containers that are gone
+func (m *model) pruneMarks() {
+	exists := make(map[string]bool, len(m.allContainers))
+	for _, c := range m.allContainers {
+		exists[c.ID] = true
+	}
+	for id := range m.marked {
+		if !exists[id] {
+			delete(m.marked, id)
+		}
+	}
+}
+
+// actionTargets is what an action key works on: the marked containers, or
+// the one under the cursor when nothing is marked
+func (m *model) actionTargets() []docker.Container {
+	if len(m.marked) == 0 {
+		if c := m.selectedContainer(); c != nil {
+			return []docker.Container{*c}
+		}
+		return nil
+	}
+	var out []docker.Container
+	for _, c := range m.listedContainers() {
+		if m.marked[c.ID] {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+var actionVerbs = map[string]string{
+	"start": "Starting", "stop": "Stopping", "restart": "Restarting",
+	"pause": "Pausing", "unpause": "Unpausing", "kill": "Killing", "rename": "Renaming",
+}
+
+// runAction runs start/stop/restart/pause on the action targets
+func (m *model) runAction(kind string) tea.Cmd {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// pause toggles: unpause the selection if it's all paused already
+	if kind == "pause" {
+		allPaused := true
+		for _, c := range targets {
+			if c.State != "paused" {
+				allPaused = false
+			}
+		}
+		if allPaused {
+			kind = "unpause"
+		}
+	}
+	return m.act(targets, docker.Action(kind))
+}
+
+// act runs action on the selected container, or on every marked one as a bulk action
+func (m *model) act(targets []docker.Container, action docker.ContainerAction) tea.Cmd {
+	verb := actionVerbs[action.Kind]
+	if len(m.marked) == 0 {
+		m.statusMessage = verb + " container..."
+		return doAction(m.rt, action, targets[0].ID)
+	}
+	m.statusMessage = fmt.Sprintf("%s %d containers...", verb, len(targets))
+	rt := m.rt
+	return bulkActionCmd(action.String(), targets, func(c docker.Container) error {
+		return rt.DoAction(action, c.ID)
+	})
+}
+
+// confirmRemoveMarked opens the remove dialog for every marked container
+func (m *model) confirmRemoveMarked() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	running, protected := 0, []string{}
+	names := make([]string, 0, len(targets))
+	for _, c := range targets {
+		names = append(names, containerName(c))
+		if c.State == "running" {
+			running++
+		}
+		if m.settings.Safety.Protects(containerName(c), c.Labels) {
+			protected = append(protected, containerName(c))
+		}
+	}
+
+	d := &confirmDialog{
+		title: fmt.Sprintf("Remove %d containers?", len(targets)),
+		details: [][2]string{
+			{"Containers", strings.Join(names, ", ")},
+			{"Running", fmt.Sprintf("%d of %d", running, len(targets))},
+			{"Protected", strings.Join(protected, ", ")},
+		},
+		options: []confirmOption{
+			{label: "Stop first", checked: running > 0},
+			{label: "Force (kill if running)"},
+			{label: "Remove anonymous volumes"},
+		},
+	}
+	if len(protected) > 0 {
+		d.title = fmt.Sprintf("Remove %d containers, %d PROTECTED?", len(targets), len(protected))
+		d.typed = fmt.Sprintf("remove %d", len(targets))
+	}
+
+	d.onConfirm = func(m *model, options []bool) tea.Cmd {
+		rt := m.rt
+		stopFirst, opts := options[0], docker.RemoveOptions{Force: options[1], Volumes: options[2]}
+		m.statusMessage = fmt.Sprintf("Removing %d containers...", len(targets))
+		return bulkActionCmd("remove", targets, func(c docker.Container) error {
+			if stopFirst && c.State == "running" {
+				if err := rt.DoAction(docker.Action("stop"), c.ID); err != nil {
+					return fmt.Errorf("stopping before remove: %w", err)
+				}
+			}
+			return rt.RemoveContainer(c.ID, opts)
+		})
+	}
+	m.confirm = d
+}
+
+// handleBulkDone keeps the failed containers marked and opens the summary
+func (m *model) handleBulkDone(msg bulkDoneMsg) tea.Cmd {
+	m.marked = msg.failed
+	m.bulkSummary = &bulkSummary{action: msg.action, results: msg.results}
+	ok := len(msg.results) - len(msg.failed)
+	m.statusMessage = fmt.Sprintf("%s: %d ok, %d failed", msg.action, ok, len(msg.failed))
+	return m.fullRefresh()
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderBulkSummary() string {
+	s := m.bulkSummary
+	inner := dialogWidth - 6
+
+	// failures first, they're what needs looking at
+	results := append([]bulkResult(nil), s.results...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].err != nil && results[j].err == nil
+	})
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+
+	var b strings.Builder
+	title := fmt.Sprintf("%s: %d ok, %d failed", s.action, len(results)-failed, failed)
+	if failed > 0 {
+		b.WriteString(dialogTitleStyle.Render(title))
+	} else {
+		b.WriteString(titleStyle.UnsetPadding().Render(title))
+	}
+	b.WriteString("\n\n")
+
+	for i, r := range results {
+		if i == bulkSummaryRows {
+			b.WriteString(fmt.Sprintf("... and %d more\n", len(results)-i))
+			break
+		}
+		if r.err != nil {
+			b.WriteString(logStderrStyle.Render(truncateToWidth("✗ "+r.name+": "+r.err.Error(), inner)))
+		} else {
+			b.WriteString(runningStyle.Render(truncateToWidth("✓ "+r.name, inner)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerDescStyle.Render("any key to close"))
+
+	return dialogStyle.Width(dialogWidth - 2).Render(b.String())
+}
@@ -1,10 +1,40 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/columns"
+	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/theme"
+)
+
+// settings rows after the column list, counted from its end
+const (
+	settingsRefresh = iota
+	settingsRuntime
+	settingsShell
+	settingsUnits
+	settingsTheme
+	settingsRows // how many there are
 )
 
+// lines of the settings screen that aren't columns
+const settingsLines = 18
+
+// settingsRow is the selected column, or -1 and the row below the columns
+func (m model) settingsRow() (col, row int) {
+	if m.settingsSelected < len(m.settings.Columns) {
+		return m.settingsSelected, -1
+	}
+	return -1, m.settingsSelected - len(m.settings.Columns)
+}
+
 func (m model) renderSettings(width int) string {
 	var b strings.Builder
 
@@ -20,23 +50,30 @@ func (m model) renderSettings(width int) string {
 	b.WriteString(header)
 	b.WriteString("\n")
 
-	// Column list
-	colNames := []string{"CONTAINER ID", "NAME", "MEMORY", "CPU", "NET I/O", "Disk I/O", "IMAGE", "STATUS", "PORTS"}
-	if m.settings.ColumnPercents == nil || len(m.settings.ColumnPercents) != 9 {
-		m.settings.ColumnPercents = []int{8, 14, 6, 6, 10, 12, 18, 13, 13}
+	// Column list, scrolled to the selection when it doesn't fit
+	cols := m.settings.Columns
+	start, end := 0, len(cols)
+	if room := max(3, m.terminalHeight-settingsLines); m.terminalHeight > 0 && len(cols) > room {
+		start = min(max(0, m.settingsSelected-room/2), len(cols)-room)
+		end = start + room
 	}
-
-	for i, name := range colNames {
-		pct := m.settings.ColumnPercents[i]
-		visible := true
-		if m.settings.VisibleColumns != nil && len(m.settings.VisibleColumns) == len(colNames) {
-			visible = m.settings.VisibleColumns[i]
+	for i := start; i < end; i++ {
+		col := cols[i]
+		title := col.Name
+		if c, ok := columns.Lookup(col.Name); ok {
+			title = c.Title
 		}
 		checkMark := "[x]"
-		if !visible {
+		if col.Hidden {
 			checkMark = "[ ]"
 		}
-		line := fmt.Sprintf(" %2d%%  %s  %s", pct, name, checkMark)
+		line := fmt.Sprintf(" %2d%%  %s  %s", col.Width, title, checkMark)
+		if i == start && start > 0 {
+			line += "  ↑"
+		}
+		if i == end-1 && end < len(cols) {
+			line += "  ↓"
+		}
 		if m.settingsSelected == i {
 			// highlight selected
 			b.WriteString(selectedStyle.Render(padRight(line, width)))
@@ -46,20 +83,21 @@ func (m model) renderSettings(width int) string {
 		b.WriteString("\n")
 	}
 
-	// Refresh interval row (index 9)
+	// Refresh interval row
+	_, row := m.settingsRow()
 	b.WriteString("\n")
 	refreshLine := fmt.Sprintf(" %2ds  Refresh Interval", m.settings.RefreshInterval)
-	if m.settingsSelected == 9 {
+	if row == settingsRefresh {
 		b.WriteString(selectedStyle.Render(padRight(refreshLine, width)))
 	} else {
 		b.WriteString(normalStyle.Render(padRight(refreshLine, width)))
 	}
 	b.WriteString("\n")
 
-	// runtime row (index 10)
+	// runtime row
 	b.WriteString("\n")
 	runtime := fmt.Sprintf("Runtime: %s", m.settings.Runtime)
-	if m.settingsSelected == 10 {
+	if row == settingsRuntime {
 		b.WriteString(selectedStyle.Render(padRight(runtime, width)))
 	} else {
 		b.WriteString(normalStyle.Render(padRight(runtime, width)))
@@ -67,10 +105,10 @@ func (m model) renderSettings(width int) string {
 	b.WriteString("\n")
 	b.WriteString(normalStyle.Render("Changing the runtime will trigger a RESTART!"))
 
-	// shell row (index 11)
+	// shell row
 	b.WriteString("\n\n")
 	shellLine := fmt.Sprintf("Shell: %s", m.settings.Shell)
-	if m.settingsSelected == 11 {
+	if row == settingsShell {
 		b.WriteString(selectedStyle.Render(padRight(shellLine, width)))
 	} else {
 		b.WriteString(normalStyle.Render(padRight(shellLine, width)))
@@ -78,8 +116,32 @@ func (m model) renderSettings(width int) string {
 	b.WriteString("\n")
 	b.WriteString(normalStyle.Render("Shell used for container exec (fallback: /bin/sh)"))
 
+	// units row
+	b.WriteString("\n\n")
+	unitsLine := fmt.Sprintf("Units: %s", strings.ToUpper(m.settings.Units))
+	if row == settingsUnits {
+		b.WriteString(selectedStyle.Render(padRight(unitsLine, width)))
+	} else {
+		b.WriteString(normalStyle.Render(padRight(unitsLine, width)))
+	}
+	b.WriteString("\n")
+	b.WriteString(normalStyle.Render("Sizes in SI (kB, MB) or IEC (KiB, MiB)"))
+
+	// theme row
+	b.WriteString("\n\n")
+	themeLine := fmt.Sprintf("Theme: %s", m.settings.Theme)
+	if row == settingsTheme {
+		b.WriteString(selectedStyle.Render(padRight(themeLine, width)))
+	} else {
+		b.WriteString(normalStyle.Render(padRight(themeLine, width)))
+	}
+	b.WriteString("\n")
+	b.WriteString(normalStyle.Render("Colors, per-color overrides go under theme: in config.yml"))
+
 	b.WriteString("\n")
-	instr := "[←/→] or [+/-] adjust  •  [space] toggle  •  [↑/↓] navigate • [s] save  •   [Esc] cancel"
+	k := m.keys
+	instr := fmt.Sprintf("[%s] adjust  •  [%s] toggle  •  [%s] move column  •  [%s] navigate  •  [%s] save  •  [%s] cancel",
+		label(k.Decrease, k.Increase), label(k.Toggle), label(k.MoveUp, k.MoveDown), label(k.Up, k.Down), label(k.Save), label(k.Back))
 	if visibleLen(instr) < width {
 		instr += strings.Repeat(" ", width-visibleLen(instr))
 	}
@@ -88,3 +150,145 @@ func (m model) renderSettings(width int) string {
 
 	return b.String()
 }
+
+// handleSettingsKey handles keys on the settings screen, it takes every key
+func (m *model) handleSettingsKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.quit()
+	case key.Matches(msg, m.keys.Settings):
+		m.currentMode = modeNormal
+		m.suspendRefresh = false
+		m.statusMessage = "Settings closed"
+		fitPercents(m.settings.Columns)
+		return nil
+	case key.Matches(msg, m.keys.Toggle):
+		// toggle visibility for column when selected
+		if col, _ := m.settingsRow(); col >= 0 {
+			m.settings.Columns[col].Hidden = !m.settings.Columns[col].Hidden
+		}
+		return nil
+	case key.Matches(msg, m.keys.Up):
+		if m.settingsSelected > 0 {
+			m.settingsSelected--
+		}
+		return nil
+	case key.Matches(msg, m.keys.Down):
+		if m.settingsSelected < len(m.settings.Columns)+settingsRows-1 {
+			m.settingsSelected++
+		}
+		return nil
+	case key.Matches(msg, m.keys.MoveUp):
+		// move the column up the list, that's left on screen
+		if col, _ := m.settingsRow(); col > 0 {
+			cols := m.settings.Columns
+			cols[col-1], cols[col] = cols[col], cols[col-1]
+			m.settingsSelected--
+		}
+		return nil
+	case key.Matches(msg, m.keys.MoveDown):
+		if col, _ := m.settingsRow(); col >= 0 && col < len(m.settings.Columns)-1 {
+			cols := m.settings.Columns
+			cols[col+1], cols[col] = cols[col], cols[col+1]
+			m.settingsSelected++
+		}
+		return nil
+	case key.Matches(msg, m.keys.Decrease):
+		col, row := m.settingsRow()
+		if col >= 0 {
+			if m.settings.Columns[col].Width > 1 {
+				m.settings.Columns[col].Width--
+			}
+		} else if row == settingsRefresh {
+			if m.settings.RefreshInterval > 1 {
+				m.settings.RefreshInterval--
+			}
+		} else if row == settingsRuntime {
+			// toggle runtime option btwn docker and podman
+			if m.settings.Runtime == RuntimeDocker {
+				m.settings.Runtime = RuntimePodman
+			} else {
+				m.settings.Runtime = RuntimeDocker
+			}
+		} else if row == settingsShell {
+			// cycle shell options backward
+			idx := slices.Index(ShellOptions, m.settings.Shell)
+			m.settings.Shell = ShellOptions[(idx-1+len(ShellOptions))%len(ShellOptions)]
+		} else if row == settingsUnits {
+			m.toggleUnits()
+		} else if row == settingsTheme {
+			idx := slices.Index(theme.Names, m.settings.Theme)
+			m.settings.Theme = theme.Names[(idx-1+len(theme.Names))%len(theme.Names)]
+		}
+		return nil
+	case key.Matches(msg, m.keys.Increase):
+		col, row := m.settingsRow()
+		if col >= 0 {
+			m.settings.Columns[col].Width++
+		} else if row == settingsRefresh {
+			if m.settings.RefreshInterval < 300 {
+				m.settings.RefreshInterval++
+			}
+		} else if row == settingsRuntime {
+			if m.settings.Runtime == RuntimeDocker {
+				m.settings.Runtime = RuntimePodman
+			} else {
+				m.settings.Runtime = RuntimeDocker
+			}
+		} else if row == settingsShell {
+			// cycle shell options forward
+			idx := slices.Index(ShellOptions, m.settings.Shell)
+			m.settings.Shell = ShellOptions[(idx+1)%len(ShellOptions)]
+		} else if row == settingsUnits {
+			m.toggleUnits()
+		} else if row == settingsTheme {
+			idx := slices.Index(theme.Names, m.settings.Theme)
+			m.settings.Theme = theme.Names[(idx+1)%len(theme.Names)]
+		}
+		return nil
+	case key.Matches(msg, m.keys.Save):
+		// save settings to yaml, they take effect right away. start from
+		// the file so anything settings doesn't edit (socket, hosts, keys,
+		// colors) is saved as it was, and only write the edited fields
+		cfg, err := config.Load()
+		if err != nil {
+			// what Load couldn't read came back as defaults, saving would
+			// write them over the file. show what's wrong instead
+			var invalid *config.ValidationError
+			if errors.As(err, &invalid) {
+				m.configBanner = invalid
+			}
+			m.statusMessage = "Not saved: fix the errors in config.yml first"
+			return nil
+		}
+		m.putEdits(cfg)
+		runtimeChanged := string(m.settings.Runtime) != m.runtimeConfig.Type
+
+		// Save to config, then apply it here rather than waiting for the
+		// watcher. the running config keeps its flags on top
+		if err := cfg.Save(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+			return nil
+		}
+		m.configStamp = statConfig()
+		if live, _ := m.loadConfig(); live != nil {
+			cfg = live
+			m.putEdits(cfg)
+		}
+		reload := m.applyConfig(cfg)
+
+		m.currentMode = modeNormal
+		m.suspendRefresh = false
+		m.statusMessage = "Settings saved!"
+		if runtimeChanged {
+			m.statusMessage = fmt.Sprintf("Settings saved! Switching to %s...", m.settings.Runtime)
+		}
+		return tea.Batch(reload, fetchContainers(m.rt), tickCmd(time.Duration(m.settings.RefreshInterval)*time.Second))
+	case key.Matches(msg, m.keys.Back):
+		m.currentMode = modeNormal
+		m.suspendRefresh = false
+		m.statusMessage = "Settings closed"
+		return nil
+	}
+	return nil
+}
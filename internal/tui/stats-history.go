@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/units"
+)
+
+// ============================================================================
+// Stats history (sparklines and the info panel charts)
+// ============================================================================
+
+const (
+	statsHistoryLen = 120 // samples kept per container, 4 minutes at the default 2s refresh
+	chartHeight     = 3   // rows of the cpu/mem charts in the info panel
+	minChartWidth   = 40  // info panel needs this much room next to the fields for charts
+)
+
+// statSample is one stats reading as the charts want it. net and block io
+// are the running totals the runtime reports, in bytes.
+type statSample struct {
+	at               time.Time
+	cpu, mem         float64 // percent
+	netRx, netTx     float64
+	blockRd, blockWr float64
+}
+
+// sampleOf reads the stats on c, ok is false when it has none
+func sampleOf(c docker.Container) (statSample, bool) {
+	st := c.Stats
+	if st == nil {
+		return statSample{}, false
+	}
+	return statSample{
+		at:      time.Now(),
+		cpu:     st.CPU * 100,
+		mem:     st.MemPercent(),
+		netRx:   float64(st.NetRx),
+		netTx:   float64(st.NetTx),
+		blockRd: float64(st.BlockRead),
+		blockWr: float64(st.BlockWrite),
+	}, true
+}
+
+// recordStats pushes a sample for every container a stats fetch just brought
+// in and forgets the history of containers that are gone or stopped. only
+// fetched stats count, one sample per tick, so the ring always spans the same
+// stretch of time
+func (m *model) recordStats(fetched map[string]docker.ContainerStats) {
+	if m.statsHistory == nil {
+		m.statsHistory = map[string]*ring[statSample]{}
+	}
+	// ids of different lengths match, the way events are matched
+	wasFetched := func(id string) bool {
+		for fid := range fetched {
+			if sameContainer(fid, id) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range m.allContainers {
+		if c.State != "running" || !wasFetched(c.ID) {
+			continue
+		}
+		s, ok := sampleOf(c)
+		if !ok {
+			continue
+		}
+		h := m.statsHistory[c.ID]
+		if h == nil {
+			h = newRing[statSample](statsHistoryLen)
+			m.statsHistory[c.ID] = h
+		}
+		h.Push(s)
+	}
+	m.pruneStatsHistory()
+}
+
+// pruneStatsHistory forgets the history of containers that are gone or
+// stopped, a list refresh runs it without adding a sample
+func (m *model) pruneStatsHistory() {
+	running := map[string]bool{}
+	for _, c := range m.allContainers {
+		if c.State == "running" {
+			running[c.ID] = true
+		}
+	}
+	for id := range m.statsHistory {
+		if !running[id] {
+			delete(m.statsHistory, id)
+		}
+	}
+}
+
+// series pulls one metric out of a container's history, oldest first
+func (m model) series(id string, pick func(statSample) float64) []float64 {
+	h := m.statsHistory[id]
+	if h == nil {
+		return nil
+	}
+	out := make([]float64, h.Len())
+	for i := range out {
+		out[i] = pick(h.At(i))
+	}
+	return out
+}
+
+// rates turns one of the running io totals into bytes per second between
+// samples. a counter going backwards (container restarted) counts as 0.
+func (m model) rates(id string, pick func(statSample) float64) []float64 {
+	h := m.statsHistory[id]
+	if h == nil {
+		return nil
+	}
+	var out []float64
+	for i := 1; i < h.Len(); i++ {
+		prev, cur := h.At(i-1), h.At(i)
+		dt := cur.at.Sub(prev.at).Seconds()
+		if dt <= 0 {
+			continue
+		}
+		out = append(out, math.Max(0, pick(cur)-pick(prev))/dt)
+	}
+	return out
+}
+
+// chartTop rounds a percentage peak up past the next round scale (1, 2, 5, 10, 25,
+// 50, 100, then hundreds) so a steady 5% doesn't draw as a full chart
+func chartTop(p float64) float64 {
+	for _, top := range []float64{1, 2, 5, 10, 25, 50, 100} {
+		if p < top {
+			return top
+		}
+	}
+	return math.Ceil(p/100) * 100
+}
+
+func peak(values []float64) float64 {
+	p := 0.0
+	for _, v := range values {
+		p = math.Max(p, v)
+	}
+	return p
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the newest width values on one line, scaled to top
+func sparkline(values []float64, width int, top float64) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if top > 0 {
+			level = int(math.Round(v / top * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[max(0, min(level, len(sparkBlocks)-1))])
+	}
+	return b.String()
+}
+
+// chart is a sparkline stretched over height rows, top row first
+func chart(values []float64, width, height int, top float64) []string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	steps := len(sparkBlocks)
+	rows := make([]string, height)
+	for r := range rows {
+		var b strings.Builder
+		floor := float64((height - 1 - r) * steps) // eighths below this row
+		for _, v := range values {
+			eighths := 0.0
+			if top > 0 {
+				eighths = v / top * float64(height*steps)
+			}
+			switch {
+			case eighths-floor >= float64(steps):
+				b.WriteRune(sparkBlocks[steps-1])
+			case eighths-floor >= 1:
+				b.WriteRune(sparkBlocks[int(eighths-floor)-1])
+			case r == height-1:
+				b.WriteRune(sparkBlocks[0]) // keep a baseline so idle shows as idle
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[r] = padRight(b.String(), width)
+	}
+	return rows
+}
+
+// inlineSpark puts a sparkline after a cpu/mem value when the column has room
+// for a few samples next to it
+func (m model) inlineSpark(value, id string, pick func(statSample) float64, width int) string {
+	values := m.series(id, pick)
+	room := width - visibleLen(value) - 1
+	if len(values) < 2 || room < 3 {
+		return value
+	}
+	// percentages, so the scale only grows past 100 on multi-core cpu use
+	return value + " " + sparkline(values, room, math.Max(100, peak(values)))
+}
+
+// renderStatsCharts draws the cpu/mem charts and net/disk rates for the info
+// panel, nil until there are two samples
+func (m model) renderStatsCharts(id string, width int) []string {
+	cpu := m.series(id, func(s statSample) float64 { return s.cpu })
+	if len(cpu) < 2 {
+		return nil
+	}
+	mem := m.series(id, func(s statSample) float64 { return s.mem })
+	rx := m.rates(id, func(s statSample) float64 { return s.netRx })
+	tx := m.rates(id, func(s statSample) float64 { return s.netTx })
+	rd := m.rates(id, func(s statSample) float64 { return s.blockRd })
+	wr := m.rates(id, func(s statSample) float64 { return s.blockWr })
+
+	var lines []string
+	label := func(name string, now, top float64) string {
+		return infoLabelStyle.Render(name) + infoValueStyle.Render(fmt.Sprintf(" %.1f%%  peak %.1f%%", now, top))
+	}
+	lines = append(lines, label("CPU", cpu[len(cpu)-1], peak(cpu)))
+	lines = append(lines, chart(cpu, width, chartHeight, chartTop(peak(cpu)))...)
+	lines = append(lines, label("MEM", mem[len(mem)-1], peak(mem)))
+	lines = append(lines, chart(mem, width, chartHeight, chartTop(peak(mem)))...)
+
+	// io gets one line: current rates, then a sparkline of the incoming side
+	io := func(name, inArrow, outArrow string, in, out []float64) string {
+		if len(in) == 0 {
+			return infoLabelStyle.Render(name) + infoValueStyle.Render(" ─")
+		}
+		value := fmt.Sprintf(" %s%s/s %s%s/s ", inArrow, units.Bytes(in[len(in)-1], m.settings.Units), outArrow, units.Bytes(out[len(out)-1], m.settings.Units))
+		return infoLabelStyle.Render(name) + infoValueStyle.Render(value) +
+			sparkline(in, width-visibleLen(name+value), peak(in))
+	}
+	lines = append(lines, io("NET", "↓", "↑", rx, tx))
+	lines = append(lines, io("DISK", "r", "w", rd, wr))
+	return lines
+}
@@ -1,14 +1,19 @@
 package tui
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/shubh-io/dockmate/internal/config"
 	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/filter"
 )
 
 type model struct {
-	containers           []docker.Container                // all containers (running + stopped)
+	rt                   docker.Runtime                    // container backend (engine api, cli or fake)
+	allContainers        []docker.Container                // all containers (running + stopped)
+	containers           []docker.Container                // allContainers narrowed down by the filter, what the list shows
 	projects             map[string]*docker.ComposeProject // compose projects
 	expandedProjects     map[string]bool                   // track which projects are expanded
 	flatList             []treeRow                         // flattened tree for rendering
@@ -24,21 +29,65 @@ type model struct {
 	startTime            time.Time                         // when app started
 	logsVisible          bool                              // logs panel visible?
 	logPanelHeight       int                               // height of logs panel
-	logsLines            []string                          // log lines
 	logsContainer        string                            // container id for logs
+	logsBuf              *ring[docker.LogLine]             // scrollback for the open log stream
+	logsStream           <-chan docker.LogLine             // open log stream, nil once it ends
+	logsCancel           context.CancelFunc                // closes the log stream
+	logsScroll           int                               // lines scrolled up from the newest
+	logsFollow           bool                              // auto-scroll as lines arrive
+	logsTimestamps       bool                              // show timestamps in front of lines
+	logsSearch           string                            // highlighted search term
+	logsMatch            int                               // buffer index of the current match, -1 for none
+	logsPrompt           logPrompt                         // search/window prompt reading keys
+	logsInput            string                            // text typed into the prompt
+	logsSince            time.Time                         // log window start, zero = all history
+	logsUntil            time.Time                         // log window end, zero = follow
 	infoVisible          bool                              // info panel visible?
 	infoPanelHeight      int                               // height of info panel
 	infoContainer        *docker.Container                 // container for info display
 	infoContainerID      string                            // info container ID
-	sortBy               sortColumn                        // which column to sort by
+	sortBy               string                            // column to sort by, a layout.columns name
 	sortAsc              bool                              // sort direction
 	columnMode           bool                              // column nav mode (vs row nav)
-	selectedColumn       int                               // selected column (0-8)
+	selectedColumn       int                               // selected column, counted in the visible ones
 	currentMode          appMode                           // current UI mode
 	helpList             list.Model
+	inspectKind          inspectKind                               // container or image
+	inspectID            string                                    // container/image shown in the inspect view
+	inspectTitle         string                                    // name (id) once loaded
+	inspectTree          []*inspectNode                            // inspect document laid out as a tree, nil while loading
+	inspectCollapsed     map[string]bool                           // collapsed sections by path
+	inspectRevealed      map[string]bool                           // unmasked secret values by path
+	inspectCursor        int                                       // selected row
+	inspectScroll        int                                       // first visible row
+	inspectPrevMode      appMode                                   // mode to go back to on close
+	events               <-chan docker.Event                       // live event stream, nil while polling
+	cancelEvents         context.CancelFunc                        // closes the event stream
+	lastSync             time.Time                                 // last full container refresh
+	imagesView           imagesView                                // images mode state
+	volumesView          volumesView                               // volumes mode state
+	networksView         networksView                              // networks mode state
+	composeRun           *composeRun                               // compose project action output, nil when the panel is closed
+	confirm              *confirmDialog                            // open confirm dialog, it takes every key
+	actionDialog         *actionDialog                             // kill signal / stop timeout / rename / host prompt, takes every key too
+	marked               map[string]bool                           // multi-selected container ids
+	bulkSummary          *bulkSummary                              // results of the last bulk action, shown until a key is pressed
+	configBanner         *config.ValidationError                   // config problems from startup or a reload, shown until a key is pressed
+	loadConfig           func() (*config.Config, error)            // reads the config for a reload
+	configStamp          fileStamp                                 // config file as of the last load, see watchConfig
+	runtimeConfig        config.RuntimeConfig                      // runtime settings the backend was opened with
+	keys                 keyMap                                    // active bindings, from the keys: section
+	filter               filter.Query                              // active filter, the zero query shows everything
+	filterBar            filterBar                                 // `/` prompt state
+	statsHistory         map[string]*ring[statSample]              // recent stats per running container, for sparklines
+	details              map[string]containerDetails               // inspected extras for the restarts/uptime/ip columns
+	host                 string                                    // host we're connected to, "" for local
+	hostNames            []string                                  // what the host switcher offers
+	connect              func(host string) (docker.Runtime, error) // opens a host picked in the switcher
 
 	// settings
 	settings         Settings
+	savedSettings    Settings // settings as the running config has them, flags included
 	composeViewMode  bool
 	suspendRefresh   bool
 	settingsSelected int
@@ -67,28 +116,16 @@ var ShellOptions = []string{"/bin/sh", "/bin/bash", "/bin/zsh", "/bin/ash"}
 
 // app settings
 type Settings struct {
-	ColumnPercents  []int
+	Columns         []config.ColumnConfig // every column, the hidden ones too, in order
 	RefreshInterval int
 	Runtime         ContainerRuntime
 	Shell           string
-	VisibleColumns  []bool
+	Safety          config.SafetyConfig
+	Filter          config.FilterConfig
+	Units           string // units.SI or units.IEC
+	Theme           string // theme.name, the colors under it stay as they are
 }
 
-// which column to sort by
-type sortColumn int
-
-const (
-	sortByID sortColumn = iota
-	sortByName
-	sortByMemory
-	sortByCPU
-	sortByNetIO
-	sortByBlockIO
-	sortByImage
-	sortByStatus
-	sortByPorts
-)
-
 // which mode the TUI is in
 type appMode int
 
@@ -100,6 +137,10 @@ const (
 	modeSettings
 	modeComposeView
 	modeHelp
+	modeInspect
+	modeImages
+	modeVolumes
+	modeNetworks
 )
 
 type actionDoneMsg struct {
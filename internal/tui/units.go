@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/shubh-io/dockmate/internal/docker"
+	"github.com/shubh-io/dockmate/internal/units"
+)
+
+// ============================================================================
+// Stats formatting
+// ============================================================================
+//
+// stats come in as plain numbers, these turn them into column text. sizes use
+// the units picked in settings, see the units package.
+
+func (m model) bytes(v uint64) string {
+	return units.Bytes(float64(v), m.settings.Units)
+}
+
+func (m model) bytePair(a, b uint64) string {
+	return m.bytes(a) + " / " + m.bytes(b)
+}
+
+// the column texts, "─" while there are no stats
+
+func cpuText(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	return fmt.Sprintf("%.2f%%", s.CPU*100)
+}
+
+// memText is the percentage of the limit, or the bytes used without one
+func (m model) memText(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	if s.MemLimit == 0 {
+		return m.bytes(s.MemUsed)
+	}
+	return fmt.Sprintf("%.2f%%", s.MemPercent())
+}
+
+func (m model) netText(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	return m.bytePair(s.NetRx, s.NetTx)
+}
+
+func (m model) blockText(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	return m.bytePair(s.BlockRead, s.BlockWrite)
+}
+
+// memUsage is the long form for the info panel, "12.5MiB / 1GiB (1.22%)"
+func (m model) memUsage(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	if s.MemLimit == 0 {
+		return m.bytes(s.MemUsed)
+	}
+	return fmt.Sprintf("%s (%.2f%%)", m.bytePair(s.MemUsed, s.MemLimit), s.MemPercent())
+}
+
+func pidsText(s *docker.ContainerStats) string {
+	if s == nil {
+		return "─"
+	}
+	return fmt.Sprintf("%d", s.PIDs)
+}
+
+func (m *model) toggleUnits() {
+	if m.settings.Units == units.IEC {
+		m.settings.Units = units.SI
+	} else {
+		m.settings.Units = units.IEC
+	}
+}
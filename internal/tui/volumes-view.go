@@ -0,0 +1,419 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubh-io/dockmate/internal/docker"
+)
+
+// ============================================================================
+// Volumes view
+// ============================================================================
+
+// volumesView is everything the volumes mode keeps between frames
+type volumesView struct {
+	volumes  []docker.Volume
+	expanded map[string]bool // volumes showing their containers
+	rows     []volumeRow     // volumes + expanded consumers, what the cursor moves over
+	cursor   int
+	scroll   int
+	loading  bool
+
+	confirmRemove string // volume waiting for y to be removed
+	confirmPrune  bool   // prune preview is showing, y runs it
+}
+
+// volumeRow is a volume, or one of its containers when user is set
+type volumeRow struct {
+	volume *docker.Volume
+	user   *docker.VolumeUser
+}
+
+type volumesMsg struct {
+	volumes []docker.Volume
+	err     error
+}
+
+type volumeRemovedMsg struct {
+	name string
+	err  error
+}
+
+type volumesPrunedMsg struct {
+	report docker.PruneReport
+	err    error
+}
+
+func fetchVolumes(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		volumes, err := rt.ListVolumes()
+		return volumesMsg{volumes: volumes, err: err}
+	}
+}
+
+func removeVolumeCmd(rt docker.Runtime, name string) tea.Cmd {
+	return func() tea.Msg {
+		// never forced, the daemon refusing an in-use volume is the last guard
+		return volumeRemovedMsg{name: name, err: rt.RemoveVolume(name, false)}
+	}
+}
+
+func pruneVolumesCmd(rt docker.Runtime) tea.Cmd {
+	return func() tea.Msg {
+		report, err := rt.PruneVolumes()
+		return volumesPrunedMsg{report: report, err: err}
+	}
+}
+
+func (m *model) openVolumes() tea.Cmd {
+	m.closeLogs()
+	m.infoVisible = false
+	m.columnMode = false
+	m.currentMode = modeVolumes
+	if m.volumesView.expanded == nil {
+		m.volumesView.expanded = map[string]bool{}
+	}
+	m.volumesView.loading = true
+	m.volumesView.confirmRemove = ""
+	m.volumesView.confirmPrune = false
+	m.statusMessage = "Loading volumes..."
+	return fetchVolumes(m.rt)
+}
+
+func (m *model) closeVolumes() {
+	m.currentMode = modeNormal
+	if m.composeViewMode {
+		m.currentMode = modeComposeView
+	}
+	m.updatePagination()
+	m.statusMessage = "Volumes closed"
+}
+
+// buildVolumeRows flattens the volume tree, same idea as buildFlatList
+func (m *model) buildVolumeRows() {
+	v := &m.volumesView
+	v.rows = v.rows[:0]
+	for i := range v.volumes {
+		vol := &v.volumes[i]
+		v.rows = append(v.rows, volumeRow{volume: vol})
+		if v.expanded[vol.Name] {
+			for j := range vol.Containers {
+				v.rows = append(v.rows, volumeRow{volume: vol, user: &vol.Containers[j]})
+			}
+		}
+	}
+}
+
+// selectedVolume is the volume under the cursor, or the parent of the container under it
+func (m model) selectedVolume() *docker.Volume {
+	v := m.volumesView
+	if v.cursor < 0 || v.cursor >= len(v.rows) {
+		return nil
+	}
+	return v.rows[v.cursor].volume
+}
+
+// unusedVolumes returns how many volumes a prune would remove and the space it frees
+func (m model) unusedVolumes() (int, int64) {
+	n, size := 0, int64(0)
+	for _, vol := range m.volumesView.volumes {
+		if !vol.InUse() {
+			n++
+			if vol.Size > 0 {
+				size += vol.Size
+			}
+		}
+	}
+	return n, size
+}
+
+func (m model) volumesPageSize() int {
+	return max(1, m.terminalHeight-5)
+}
+
+func (m *model) clampVolumesCursor() {
+	v := &m.volumesView
+	if v.cursor >= len(v.rows) {
+		v.cursor = len(v.rows) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+	page := m.volumesPageSize()
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+page {
+		v.scroll = v.cursor - page + 1
+	}
+	if v.scroll < 0 {
+		v.scroll = 0
+	}
+}
+
+func (m model) handleVolumesMsgs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	v := &m.volumesView
+
+	switch msg := msg.(type) {
+	case volumesMsg:
+		v.loading = false
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error listing volumes: %v", msg.err)
+			return m, nil
+		}
+		// keep the cursor on the same volume across refreshes
+		var selected string
+		if vol := m.selectedVolume(); vol != nil {
+			selected = vol.Name
+		}
+		v.volumes = msg.volumes
+		m.buildVolumeRows()
+		for i, row := range v.rows {
+			if row.user == nil && row.volume.Name == selected {
+				v.cursor = i
+			}
+		}
+		if m.statusMessage == "Loading volumes..." {
+			m.statusMessage = ""
+		}
+		m.clampVolumesCursor()
+		return m, nil
+
+	case volumeRemovedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error removing %s: %v", msg.name, msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Removed volume %s", msg.name)
+		}
+		return m, fetchVolumes(m.rt)
+
+	case volumesPrunedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error pruning volumes: %v", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Pruned %d volumes, reclaimed %s", len(msg.report.Deleted), docker.FormatBytes(msg.report.SpaceReclaimed))
+		}
+		return m, fetchVolumes(m.rt)
+	}
+	return m, nil
+}
+
+func (m *model) handleVolumesKey(msg tea.KeyMsg) tea.Cmd {
+	v := &m.volumesView
+
+	if msg.String() == "ctrl+c" {
+		return m.quit()
+	}
+
+	// confirmations only take yes, anything else backs out
+	if v.confirmRemove != "" || v.confirmPrune {
+		name, prune := v.confirmRemove, v.confirmPrune
+		v.confirmRemove, v.confirmPrune = "", false
+		if !key.Matches(msg, m.keys.Confirm) {
+			m.statusMessage = "Cancelled"
+			return nil
+		}
+		if prune {
+			m.statusMessage = "Pruning unused volumes..."
+			return pruneVolumesCmd(m.rt)
+		}
+		m.statusMessage = fmt.Sprintf("Removing volume %s...", name)
+		return removeVolumeCmd(m.rt, name)
+	}
+
+	// the key that opened the view closes it again
+	if key.Matches(msg, m.keys.Volumes) {
+		m.closeVolumes()
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.quit()
+	case key.Matches(msg, m.keys.Back):
+		m.closeVolumes()
+		return nil
+	case key.Matches(msg, m.keys.Up):
+		v.cursor--
+	case key.Matches(msg, m.keys.Down):
+		v.cursor++
+	case key.Matches(msg, m.keys.PageUp):
+		v.cursor -= m.volumesPageSize()
+	case key.Matches(msg, m.keys.PageDown):
+		v.cursor += m.volumesPageSize()
+	case key.Matches(msg, m.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		v.cursor = len(v.rows) - 1
+	case key.Matches(msg, m.keys.Select, m.keys.Toggle, m.keys.Expand, m.keys.Collapse):
+		vol := m.selectedVolume()
+		if vol == nil || !vol.InUse() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.Expand):
+			v.expanded[vol.Name] = true
+		case key.Matches(msg, m.keys.Collapse):
+			v.expanded[vol.Name] = false
+		default:
+			v.expanded[vol.Name] = !v.expanded[vol.Name]
+		}
+		m.buildVolumeRows()
+		// collapsing from a child row lands on its volume
+		for i, row := range v.rows {
+			if row.user == nil && row.volume.Name == vol.Name && !v.expanded[vol.Name] {
+				v.cursor = i
+			}
+		}
+	case key.Matches(msg, m.keys.Reload):
+		m.statusMessage = "Loading volumes..."
+		return fetchVolumes(m.rt)
+	case key.Matches(msg, m.keys.Delete):
+		vol := m.selectedVolume()
+		if vol == nil {
+			return nil
+		}
+		if vol.InUse() {
+			m.statusMessage = fmt.Sprintf("%s is mounted by %s, remove those containers first", vol.Name, volumeUserNames(*vol))
+			return nil
+		}
+		v.confirmRemove = vol.Name
+	case key.Matches(msg, m.keys.Prune):
+		if n, _ := m.unusedVolumes(); n == 0 {
+			m.statusMessage = "No unused volumes to prune"
+			return nil
+		}
+		v.confirmPrune = true
+	}
+	m.clampVolumesCursor()
+	return nil
+}
+
+func volumeUserNames(vol docker.Volume) string {
+	names := make([]string, 0, len(vol.Containers))
+	for _, u := range vol.Containers {
+		names = append(names, u.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatVolumeSize(size int64) string {
+	if size < 0 {
+		return "─"
+	}
+	return docker.FormatBytes(size)
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+func (m model) renderVolumes(width int) string {
+	var b strings.Builder
+	v := m.volumesView
+
+	b.WriteString(m.renderTitleBar(width))
+	b.WriteString("\n")
+
+	unused, reclaim := m.unusedVolumes()
+	var total int64
+	for _, vol := range v.volumes {
+		if vol.Size > 0 {
+			total += vol.Size
+		}
+	}
+	title := fmt.Sprintf("Volumes: %d (%s)  Unused: %d (%s reclaimable) ", len(v.volumes), docker.FormatBytes(total), unused, docker.FormatBytes(reclaim))
+	if v.loading && len(v.volumes) == 0 {
+		title = "Volumes: loading... "
+	}
+	if visibleLen(title) < width {
+		title += strings.Repeat(" ", width-visibleLen(title))
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	// group rows get " ▼ " in front, the header lines up with that
+	driverW, sizeW, usersW := 10, 10, 14
+	nameW := max(20, (width-3-driverW-sizeW-usersW)/2)
+	mountW := max(10, width-3-nameW-driverW-sizeW-usersW-4)
+	columns := func(name, driver, size, users, mount string) string {
+		return strings.Join([]string{
+			padRight(truncateToWidth(name, nameW), nameW),
+			padRight(truncateToWidth(driver, driverW), driverW),
+			padRight(truncateToWidth(size, sizeW), sizeW),
+			padRight(truncateToWidth(users, usersW), usersW),
+			truncateToWidth(mount, mountW),
+		}, " ")
+	}
+
+	header := "   " + columns("VOLUME NAME", "DRIVER", "SIZE", "CONTAINERS", "MOUNTPOINT")
+	b.WriteString(headerStyle.Render(padRight(header, width)))
+	b.WriteString("\n")
+
+	page := m.volumesPageSize()
+	end := min(len(v.rows), v.scroll+page)
+	for i := v.scroll; i < end; i++ {
+		row := v.rows[i]
+		selected := i == v.cursor
+
+		if row.user == nil {
+			vol := row.volume
+			running := 0
+			for _, u := range vol.Containers {
+				if u.State == "running" {
+					running++
+				}
+			}
+			users := "unused"
+			if vol.InUse() {
+				users = fmt.Sprintf("%d/%d running", running, len(vol.Containers))
+			}
+			label := columns(vol.Name, vol.Driver, formatVolumeSize(vol.Size), users, vol.Mountpoint)
+			if vol.InUse() {
+				b.WriteString(renderGroupRow(label, v.expanded[vol.Name], selected, width))
+			} else {
+				// nothing to expand, keep the column alignment without the arrow
+				line := padRight("   "+label, width)
+				if selected {
+					b.WriteString(selectedStyle.Render(line))
+				} else {
+					b.WriteString(stoppedStyle.Render(line))
+				}
+			}
+			b.WriteString("\n")
+			continue
+		}
+
+		u := row.user
+		line := padRight(fmt.Sprintf("  %s%s (%s) %s", treeBranch(1), u.Name, u.ID, u.State), width)
+		switch {
+		case selected:
+			b.WriteString(selectedStyle.Render(line))
+		case u.State == "running":
+			b.WriteString(runningStyle.Render(line))
+		default:
+			b.WriteString(stoppedStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	for i := end - v.scroll; i < page; i++ {
+		b.WriteString("\n")
+	}
+
+	status := m.statusMessage
+	switch {
+	case v.confirmRemove != "":
+		status = fmt.Sprintf("Remove volume %s? Its data is gone for good (%s/n)", v.confirmRemove, label(m.keys.Confirm))
+	case v.confirmPrune:
+		status = fmt.Sprintf("Prune %d unused volumes and reclaim %s? Their data is gone for good (%s/n)", unused, docker.FormatBytes(reclaim), label(m.keys.Confirm))
+	case status == "" && len(v.rows) > page:
+		status = fmt.Sprintf("%d-%d of %d", v.scroll+1, end, len(v.rows))
+	}
+	b.WriteString(normalStyle.Render(" " + status))
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter(width))
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package units
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Byte sizes
+// ============================================================================
+//
+// shared by the TUI and the CLI so both print the same thing. si is powers
+// of 1000 (kB, MB) like docker prints io, iec powers of 1024 (KiB, MiB) like
+// docker prints memory.
+
+const (
+	SI  = "si"
+	IEC = "iec"
+)
+
+var (
+	siNames  = []string{"B", "kB", "MB", "GB", "TB"}
+	iecNames = []string{"B", "KiB", "MiB", "GiB", "TiB"}
+)
+
+// Bytes is a compact size with up to three significant digits, "1.2kB"
+func Bytes(v float64, units string) string {
+	step, names := 1000.0, siNames
+	if units == IEC {
+		step, names = 1024, iecNames
+	}
+	i := 0
+	for v >= step && i < len(names)-1 {
+		v /= step
+		i++
+	}
+	prec := 0
+	switch {
+	case i == 0:
+	case v < 10:
+		prec = 2
+	case v < 100:
+		prec = 1
+	}
+	num := strconv.FormatFloat(v, 'f', prec, 64)
+	if prec > 0 {
+		num = strings.TrimRight(strings.TrimRight(num, "0"), ".")
+	}
+	return num + names[i]
+}
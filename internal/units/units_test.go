@@ -0,0 +1,14 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	assert.Equal(t, "648B", Bytes(648, SI))
+	assert.Equal(t, "1.2kB", Bytes(1200, SI))
+	assert.Equal(t, "12.5MiB", Bytes(12.5*(1<<20), IEC))
+	assert.Equal(t, "1GiB", Bytes(1<<30, IEC))
+}
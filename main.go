@@ -1,114 +1,195 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/shubh-io/dockmate/internal/check"
+	"github.com/shubh-io/dockmate/internal/cli"
 	"github.com/shubh-io/dockmate/internal/config"
+	"github.com/shubh-io/dockmate/internal/docker"
 	"github.com/shubh-io/dockmate/internal/tui"
 	"github.com/shubh-io/dockmate/internal/update"
 	"github.com/shubh-io/dockmate/pkg/version"
 )
 
-// just a temporary file to indicate a restart is needed
-const restartMarkerFile = ".dockmate_restart"
-
 // ============================================================================
 // Main
 // ============================================================================
 
 func main() {
-	// Restart loop for settings changes
-	for {
-		if !runApp() {
-			break
+	runApp()
+}
+
+func runApp() {
+	opts, args, err := parseOptions(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		// the flag package already printed the error and the usage
+		os.Exit(2)
+	}
+	if opts.configPath != "" {
+		config.SetPath(opts.configPath)
+	}
+	if opts.debugLog != "" {
+		if err := tui.SetDebugFile(opts.debugLog); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: can't open debug log: %v\n", err)
 		}
 	}
-}
 
-func getRestartMarkerPath() string {
-	tmpDir := os.TempDir()
-	return filepath.Join(tmpDir, restartMarkerFile)
-}
+	switch {
+	case opts.version:
+		fmt.Printf("DockMate version: %s\n", version.Dockmate_Version)
+		return
+	case opts.pickRuntime:
+		pickRuntime()
+		return
+	}
 
-func runApp() bool {
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "version", "--version", "-v":
+	if len(args) > 0 {
+		switch args[0] {
+		case "version":
 			fmt.Printf("DockMate version: %s\n", version.Dockmate_Version)
-			return false
+			return
 		case "update":
 			update.UpdateCommand()
-			return false
-		case "--runtime":
-			runtimeSelector := tui.NewRuntimeSelectionModel()
-			program := tea.NewProgram(runtimeSelector, tea.WithAltScreen())
-
-			finalModel, err := program.Run()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Runtime selection failed: %v\n", err)
+			return
+		case "help":
+			printUsage(os.Stdout)
+			return
+		case "config":
+			if err := cli.RunConfig(args[1:], os.Stdout, os.Stderr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-
-			rsModel, ok := finalModel.(tui.RuntimeSelectionModel)
-			if !ok {
-				fmt.Fprintf(os.Stderr, "Invalid model type returned\n")
-				os.Exit(1)
-			}
-
-			selectedRuntime := strings.TrimSpace(rsModel.GetChoice())
-			if selectedRuntime == "" {
-				fmt.Fprintf(os.Stderr, "No runtime selected\n")
-				os.Exit(1)
+			return
+		default:
+			if !cli.IsCommand(args[0]) {
+				fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+				printUsage(os.Stderr)
+				os.Exit(2)
 			}
+			os.Exit(runCommand(args, opts))
+		}
+	}
 
-			// load current config and update runtime
-			cfg, _ := config.Load()
-			cfg.Runtime.Type = selectedRuntime
+	cfg, host, loadErr := opts.load()
 
-			// Save updated config (if you dont know, config location is ~/.config/dockmate/config.yml or $XDG_CONFIG_HOME/dockmate/config.yml)
-			if err := cfg.Save(); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to save runtime selection: %v\n", err)
-				os.Exit(1)
-			}
+	// the prechecks look at the local daemon, a remote one answers for itself
+	if !opts.noPrechecks && (host == "" || host == docker.LocalHost) {
+		result := check.RunPreChecks(opts.runtime)
 
-			fmt.Printf("Runtime set to %s.\n\n", selectedRuntime)
-			fmt.Printf("To run the application: run 'dockmate'\n")
-			fmt.Printf("To change runtime interactively later: 'dockmate --runtime'.\n")
-			return false
+		if !result.Passed {
+			fmt.Fprintf(os.Stderr, "%s\n\n%s\n", result.ErrorMessage, result.SuggestedAction)
+			os.Stderr.Sync()
+			os.Exit(1)
 		}
 	}
 
-	result := check.RunPreChecks()
+	// NO_COLOR makes lipgloss drop every escape code, bold and reverse video
+	// too, and the cursor row with them. the monochrome theme it gets has no
+	// colors to drop, so the plain attributes are safe to turn back on
+	if os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
 
-	if !result.Passed {
-		fmt.Fprintf(os.Stderr, "%s\n\n%s\n", result.ErrorMessage, result.SuggestedAction)
-		os.Stderr.Sync()
+	m, err := tui.InitialModel(cfg, host)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't connect to %s: %v\n", host, err)
 		os.Exit(1)
 	}
+	// a config that fell back to defaults gets a banner instead of going unnoticed
+	var invalid *config.ValidationError
+	if errors.As(loadErr, &invalid) {
+		m = m.WithConfigProblems(invalid)
+	}
+	// reloads keep the command line overrides on top of the file
+	m = m.WithConfigLoader(func() (*config.Config, error) {
+		cfg, _, err := opts.load()
+		return cfg, err
+	})
 
 	// start the TUI with alternate screen mode
 	// (alternate screen = your terminal history stays clean)
 
-	p := tea.NewProgram(tui.InitialModel(), tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
+}
 
-	// check if a restart was requested (if temp marker file exists)
-	markerPath := getRestartMarkerPath()
-	_, err := os.Stat(markerPath)
-	if err == nil {
-		// Marker file exists then we restarting ;-;
-		os.Remove(markerPath) // Clean up the temp file
-		return true           // Continue the loop to restart
+// runCommand runs a headless subcommand and returns the exit code
+func runCommand(args []string, opts options) int {
+	cfg, host, loadErr := opts.load()
+	if loadErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", loadErr)
+	}
+	rt, err := docker.OpenHost(cfg, host)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't connect to %s: %v\n", host, err)
+		return 1
+	}
+
+	// ctrl+c ends `stats` and `logs -f` cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := cli.Run(ctx, rt, cfg, args, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// pickRuntime runs the interactive runtime selector and saves the choice
+func pickRuntime() {
+	runtimeSelector := tui.NewRuntimeSelectionModel()
+	program := tea.NewProgram(runtimeSelector, tea.WithAltScreen())
+
+	finalModel, err := program.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Runtime selection failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	rsModel, ok := finalModel.(tui.RuntimeSelectionModel)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid model type returned\n")
+		os.Exit(1)
+	}
+
+	selectedRuntime := strings.TrimSpace(rsModel.GetChoice())
+	if selectedRuntime == "" {
+		fmt.Fprintf(os.Stderr, "No runtime selected\n")
+		os.Exit(1)
+	}
+
+	// load current config and update runtime. a file with errors came back
+	// partly as defaults, saving it would write them over the file
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't save the runtime, fix the config first:\n%v\n", err)
+		os.Exit(1)
+	}
+	cfg.Runtime.Type = selectedRuntime
+
+	// Save updated config (if you dont know, config location is ~/.config/dockmate/config.yml or $XDG_CONFIG_HOME/dockmate/config.yml)
+	if err := cfg.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save runtime selection: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Normal quit
-	return false
+	fmt.Printf("Runtime set to %s.\n\n", selectedRuntime)
+	fmt.Printf("To run the application: run 'dockmate'\n")
+	fmt.Printf("To change runtime interactively later: 'dockmate --runtime'.\n")
 }